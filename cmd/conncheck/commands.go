@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"conncheck/internal/config"
+	"conncheck/internal/engine"
+	"conncheck/internal/i18n"
+)
+
+const defaultOutputs = "outputs"
+
+// command is one conncheck subcommand.
+type command struct {
+	name    string
+	args    string // positional arguments, for the usage line
+	summary string
+	run     func(args []string)
+}
+
+func commands() []command {
+	return []command{
+		{"run", "", "Run the test suite and write the reports (default)", runRun},
+		{"report", "[run]", "Rebuild the reports of a run from its results.json (default: latest run)", runReport},
+		{"diff", "[old] [new]", "Compare two runs (default: the latest two)", runDiff},
+		{"validate", "", "Check the configuration without running", runValidate},
+		{"list-tests", "", "List the tests, their category and whether a run would include them", runListTests},
+		{"monitor", "", "Run the suite repeatedly on an interval", runMonitor},
+		{"serve", "", "Browse previous runs and their reports in a local web page", runServe},
+		{"history", "", "List previous runs with their headline numbers", runHistory},
+		{"init", "", "Write a conncheck.yaml tailored to this machine", runInit},
+		{"config", "show|keygen|sign", "Show the effective configuration, or manage remote config signatures", runConfig},
+		{"exporter", "", "Serve Prometheus metrics for the latest run", runExporter},
+		{"version", "", "Print version and build information", runVersion},
+	}
+}
+
+func main() {
+	args := os.Args[1:]
+	// No arguments, or flags only, is a plain run so double-clicking the
+	// executable and older scripts keep working.
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && !isHelp(args[0])) {
+		runRun(args)
+		return
+	}
+	if isHelp(args[0]) || args[0] == "help" {
+		runHelp(args[1:])
+		return
+	}
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			cmd.run(args[1:])
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "conncheck: unknown command %q\n\n", args[0])
+	printUsage(os.Stderr)
+	os.Exit(exitError)
+}
+
+func isHelp(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+// runHelp prints the command list, or the flags of one command.
+func runHelp(args []string) {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return
+	}
+	for _, cmd := range commands() {
+		if cmd.name == args[0] {
+			cmd.run([]string{"-h"})
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "conncheck: unknown command %q\n", args[0])
+	os.Exit(exitError)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: conncheck [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command conncheck runs the suite. Global flags, accepted by every")
+	fmt.Fprintln(w, "command that reads the configuration or previous runs:")
+	fs := flag.NewFlagSet("conncheck", flag.ContinueOnError)
+	fs.SetOutput(w)
+	(&globalFlags{}).register(fs)
+	fs.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "conncheck help <command>" for the flags of a command.`)
+}
+
+// newFlagSet returns the flag set of a command with usage text taken from
+// the command table. name may carry a sub-action, as in "config show".
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		out := fs.Output()
+		for _, cmd := range commands() {
+			if cmd.name != strings.Fields(name)[0] {
+				continue
+			}
+			args := cmd.args
+			if name != cmd.name {
+				args = ""
+			}
+			fmt.Fprintf(out, "Usage: conncheck %s [flags] %s\n\n%s\n\nFlags:\n", name, args, cmd.summary)
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses the flags of a command and exits when it cannot go on:
+// with exitOK after -h has printed the usage, and with exitError on a bad
+// flag, so that scripts do not mistake a usage error for a failed test.
+func parseFlags(fs *flag.FlagSet, args []string) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(exitOK)
+		}
+		os.Exit(exitError)
+	}
+}
+
+// globalFlags are the flags shared by the commands that load the
+// configuration or read previous runs.
+type globalFlags struct {
+	configPath string
+	sets       setFlags
+	language   string
+	outputs    string
+}
+
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.configPath, "config", "", "Path or https URL of conncheck.yaml")
+	addSetFlag(fs, &g.sets)
+	fs.StringVar(&g.language, "lang", "", "Report and message language (en, it); overrides config")
+	fs.StringVar(&g.outputs, "outputs", defaultOutputs, "Folder holding the run folders")
+}
+
+// load reads the configuration and applies -lang.
+func (g *globalFlags) load() (config.Config, error) {
+	cfg, err := loadConfig(g.configPath, g.sets)
+	if err != nil {
+		return cfg, err
+	}
+	if g.language != "" {
+		cfg.Language = g.language
+	}
+	if err := i18n.SetLanguage(cfg.Language); err != nil {
+		return cfg, fmt.Errorf("language: %w", err)
+	}
+	return cfg, nil
+}
+
+// setLanguage applies -lang for commands that do not need the configuration.
+func (g *globalFlags) setLanguage() error {
+	if g.language == "" {
+		return nil
+	}
+	return i18n.SetLanguage(g.language)
+}
+
+// selectionFlags are the -only and -skip flags of the commands that run the
+// suite.
+type selectionFlags struct {
+	only listFlags
+	skip listFlags
+}
+
+func (s *selectionFlags) register(fs *flag.FlagSet) {
+	fs.Var(&s.only, "only", "Run only these tests: names, categories or globs, comma-separated (repeatable)")
+	fs.Var(&s.skip, "skip", "Skip these tests: names, categories or globs, comma-separated (repeatable)")
+}
+
+func (s *selectionFlags) parse() (engine.Selection, error) {
+	return engine.ParseSelection(s.only, s.skip)
+}
+
+// listFlags collects the values of a repeatable flag.
+type listFlags []string
+
+func (l *listFlags) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlags) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"conncheck/internal/config"
+	"conncheck/internal/i18n"
+	"conncheck/internal/remoteconfig"
+)
+
+// setFlags collects repeated -set path.to.key=value overrides.
+type setFlags []string
+
+func (s *setFlags) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *setFlags) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func addSetFlag(fs *flag.FlagSet, sets *setFlags) {
+	fs.Var(sets, "set", "Override a config key, e.g. -set thresholds.ping_warn_ms=80 (repeatable)")
+}
+
+// loadConfig merges the config file, CONNCHECK_* variables and -set
+// overrides. A path that is an http(s) URL is fetched and verified with
+// remoteconfig first; notes about the fetch go to stderr.
+func loadConfig(path string, sets setFlags) (config.Config, error) {
+	if !remoteconfig.IsURL(path) {
+		return config.LoadLayered(config.Sources{Path: path, Env: os.Environ(), Set: sets})
+	}
+	fetcher, err := remoteconfig.New()
+	if err != nil {
+		return config.Config{}, err
+	}
+	fetched, err := fetcher.Fetch(context.Background(), path)
+	if err != nil {
+		return config.Config{}, err
+	}
+	if fetched.FromCache {
+		fmt.Fprintln(os.Stderr, i18n.T("cli.remote.cached", path, fetched.FetchErr, fetched.FetchedAt.Format(time.RFC3339)))
+	} else {
+		fmt.Fprintln(os.Stderr, i18n.T("cli.remote.verified", path))
+	}
+	cfg, err := config.LoadLayered(config.Sources{Path: fetched.Path, Env: os.Environ(), Set: sets})
+	if err != nil {
+		return config.Config{}, err
+	}
+	cfg.Source = path
+	return cfg, nil
+}
+
+// runConfig handles the config subcommands: show prints the effective
+// configuration with the layer that set each value; keygen and sign manage
+// the key pair for remote configs.
+func runConfig(args []string) {
+	if len(args) == 0 {
+		configUsage()
+	}
+	switch args[0] {
+	case "show":
+		runConfigShow(args[1:])
+	case "keygen":
+		runConfigKeygen(args[1:])
+	case "sign":
+		runConfigSign(args[1:])
+	default:
+		configUsage()
+	}
+}
+
+func configUsage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  conncheck config show [-config file|url] [-set key=value]
+  conncheck config keygen -out signing.key
+  conncheck config sign -key signing.key conncheck.yaml`)
+	os.Exit(exitError)
+}
+
+func runConfigShow(args []string) {
+	var global globalFlags
+	fs := newFlagSet("config show")
+	global.register(fs)
+	parseFlags(fs, args)
+
+	cfg, err := global.load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	data, err := config.Show(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	os.Stdout.Write(data)
+}
+
+// runConfigKeygen writes a new private key and prints the public key to embed
+// with -ldflags.
+func runConfigKeygen(args []string) {
+	var out string
+	fs := newFlagSet("config keygen")
+	fs.StringVar(&out, "out", "", "File for the private key (required)")
+	parseFlags(fs, args)
+	if out == "" {
+		configUsage()
+	}
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	encoded := base64.StdEncoding.EncodeToString(private.Seed()) + "\n"
+	if err := os.WriteFile(out, []byte(encoded), 0o600); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	fmt.Println(i18n.T("cli.remote.keygen", out, base64.StdEncoding.EncodeToString(public)))
+}
+
+// runConfigSign writes <file>.sig next to each file, ready to be served
+// alongside it.
+func runConfigSign(args []string) {
+	var keyPath string
+	fs := newFlagSet("config sign")
+	fs.StringVar(&keyPath, "key", "", "Private key written by config keygen (required)")
+	parseFlags(fs, args)
+	if keyPath == "" || fs.NArg() == 0 {
+		configUsage()
+	}
+	keyData, err := os.ReadFile(keyPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(keyData)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		fmt.Fprintf(os.Stderr, "%s: not a key written by config keygen\n", keyPath)
+		os.Exit(exitError)
+	}
+	private := ed25519.NewKeyFromSeed(seed)
+	for _, path := range fs.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitError)
+		}
+		issues, err := config.ValidateData(path, data)
+		if err == nil && len(issues) > 0 {
+			err = &config.ValidationError{Issues: issues}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitError)
+		}
+		if err := os.WriteFile(path+remoteconfig.SignatureSuffix, remoteconfig.Sign(private, data), 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitError)
+		}
+		fmt.Println(i18n.T("cli.remote.signed", path+remoteconfig.SignatureSuffix))
+	}
+}
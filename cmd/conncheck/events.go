@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"conncheck/internal/model"
+)
+
+// eventWriter prints events as NDJSON, one object per line. A nil writer
+// drops them, so callers need not check whether -ndjson was given.
+type eventWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (w *eventWriter) emit(event model.Event) {
+	if w == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_ = w.enc.Encode(event)
+}
+
+// listener returns emit as an engine listener, or nil for a nil writer.
+func (w *eventWriter) listener() func(model.Event) {
+	if w == nil {
+		return nil
+	}
+	return w.emit
+}
+
+// writeJSON prints result the way results.json holds it.
+func writeJSON(out io.Writer, result model.Result) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
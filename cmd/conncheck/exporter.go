@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"conncheck/internal/exporter"
+	"conncheck/internal/i18n"
+)
+
+const defaultExporterListen = "127.0.0.1:9469"
+
+// runExporter serves /metrics. Without -interval it exposes the newest run
+// found under -outputs; with -interval it runs the suite itself on that
+// schedule (monitor mode) and exposes each completed cycle.
+func runExporter(args []string) {
+	var (
+		global    globalFlags
+		selection selectionFlags
+		listen    string
+		interval  time.Duration
+	)
+	fs := newFlagSet("exporter")
+	global.register(fs)
+	selection.register(fs)
+	fs.StringVar(&listen, "listen", "", "Listen address (default: exporter.listen or "+defaultExporterListen+")")
+	fs.DurationVar(&interval, "interval", 0, "Run the suite on this interval instead of reading previous runs (e.g. 15m)")
+	parseFlags(fs, args)
+
+	logger := log.New(os.Stdout, "conncheck: ", log.LstdFlags)
+	cfg, err := global.load()
+	if err != nil {
+		fatalf(logger, "config load failed: %v", err)
+	}
+	sel, err := selection.parse()
+	if err != nil {
+		fatalf(logger, "%v", err)
+	}
+	outputs := global.outputs
+	if listen == "" {
+		listen = cfg.Exporter.Listen
+	}
+	if listen == "" {
+		listen = defaultExporterListen
+	}
+
+	var source exporter.Source = exporter.DirSource{Root: outputs}
+	if interval > 0 {
+		store := &exporter.Store{}
+		source = store
+		logger.Println(i18n.T("cli.exporter.monitor", interval))
+		go monitorLoop(context.Background(), suite{cfg: cfg, sel: sel, logger: logger}, outputs, interval, 0, store.Set)
+	} else {
+		logger.Println(i18n.T("cli.exporter.source_dir", outputs))
+	}
+
+	logger.Println(i18n.T("cli.exporter.listening", listen))
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           exporter.Handler(source),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err := server.ListenAndServe(); err != nil {
+		fatalf(logger, "exporter: %v", err)
+	}
+}
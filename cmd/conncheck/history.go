@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"conncheck/internal/engine"
+	"conncheck/internal/history"
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
+	"conncheck/internal/report"
+)
+
+// runHistory lists the runs under -outputs, newest first, with the worst
+// status, the status counts and the first speedtest category that has
+// results.
+func runHistory(args []string) {
+	var (
+		global globalFlags
+		limit  int
+	)
+	fs := newFlagSet("history")
+	global.register(fs)
+	fs.IntVar(&limit, "limit", 20, "Show at most this many runs (0 = all)")
+	parseFlags(fs, args)
+	if err := global.setLanguage(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+
+	runs, err := history.List(global.outputs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	if len(runs) == 0 {
+		fmt.Println(i18n.T("cli.history.none", global.outputs))
+		return
+	}
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, i18n.T("cli.history.header"))
+	for _, run := range runs {
+		counts := run.Result.Summary.StatusCounts
+		down, up, ping := headlineSpeed(run.Result)
+		fmt.Fprintf(w, "%s\t%s\t%d/%d/%d/%d\t%s\t%s\t%s\n",
+			run.Name,
+			report.WorstStatus(run.Result),
+			counts["OK"], counts["WARN"], counts["FAIL"], counts["SKIPPED"],
+			down, up, ping,
+		)
+	}
+	w.Flush()
+}
+
+// headlineSpeed returns download and upload Mbps and ping ms from the
+// nearest speedtest category with results, or dashes.
+func headlineSpeed(result model.Result) (string, string, string) {
+	for _, test := range result.Tests {
+		if test.Name != "speedtest" {
+			continue
+		}
+		for _, category := range []string{"local", "national", "eu", "us"} {
+			down, ok := metricFloat(test.Metrics, category+"_avg_down_bps")
+			if !ok {
+				continue
+			}
+			up, _ := metricFloat(test.Metrics, category+"_avg_up_bps")
+			ping, _ := metricFloat(test.Metrics, category+"_avg_ping_ms")
+			return fmt.Sprintf("%.0f", down/1e6), fmt.Sprintf("%.0f", up/1e6), fmt.Sprintf("%.1f", ping)
+		}
+	}
+	return "-", "-", "-"
+}
+
+func metricFloat(metrics model.StringMap, key string) (float64, bool) {
+	value, ok := metrics[key]
+	if !ok {
+		return 0, false
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	return parsed, err == nil
+}
+
+// runListTests prints the tests in execution order with their category and
+// whether a run with the same configuration and -only/-skip would run each
+// one, so a selection can be checked before running it.
+func runListTests(args []string) {
+	var (
+		global    globalFlags
+		selection selectionFlags
+	)
+	fs := newFlagSet("list-tests")
+	global.register(fs)
+	selection.register(fs)
+	parseFlags(fs, args)
+	cfg, err := global.load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	sel, err := selection.parse()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	suite := engine.Engine{Cfg: cfg, Selection: sel}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, i18n.T("cli.list_tests.header"))
+	for _, name := range engine.TestNames() {
+		runs := yesNo(true)
+		if excluded, ok := suite.Exclusion(name); ok {
+			runs = yesNo(false) + " (" + report.ExclusionReason(excluded) + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, i18n.Label("test", name), engine.Category(name), runs)
+	}
+	w.Flush()
+}
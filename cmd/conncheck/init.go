@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"conncheck/internal/config"
+	"conncheck/internal/i18n"
+	"conncheck/internal/setup"
+)
+
+// runInit writes a conncheck.yaml tailored to this machine. Values missing
+// from the flags are asked for on a terminal; with -non-interactive, or when
+// stdin is not a terminal, the flag defaults are used so the command can be
+// scripted.
+func runInit(args []string) {
+	var (
+		path           string
+		answers        setup.Answers
+		nonInteractive bool
+		force          bool
+	)
+	fs := newFlagSet("init")
+	fs.StringVar(&path, "out", config.DefaultConfigFilename, "File to write")
+	fs.StringVar(&answers.Region, "region", "", "Region preset: "+strings.Join(setup.Regions(), ", ")+" (default: other)")
+	fs.Float64Var(&answers.DownloadMbps, "download-mbps", 0, "Subscribed download speed in Mbps (0 = unknown)")
+	fs.Float64Var(&answers.UploadMbps, "upload-mbps", 0, "Subscribed upload speed in Mbps (0 = unknown)")
+	fs.StringVar(&answers.Language, "lang", "", "Language for messages and the generated config (en, it)")
+	fs.BoolVar(&nonInteractive, "non-interactive", false, "Never prompt; use flags and defaults")
+	fs.BoolVar(&force, "force", false, "Overwrite an existing file")
+	parseFlags(fs, args)
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if answers.Language == "" {
+		answers.Language = i18n.DefaultLanguage
+	}
+	if err := i18n.SetLanguage(answers.Language); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	answers.Language = i18n.Language()
+
+	if _, err := os.Stat(path); err == nil && !force {
+		fmt.Fprintln(os.Stderr, i18n.T("cli.init.exists", path))
+		os.Exit(exitError)
+	}
+
+	fmt.Println(i18n.T("cli.init.detecting"))
+	detected := setup.Detect()
+	fmt.Println(i18n.T("cli.init.detected", orNone(detected.Gateway), orNone(strings.Join(detected.Resolvers, ", ")), yesNo(detected.IPv6)))
+
+	if !nonInteractive && isTerminal(os.Stdin) {
+		in := bufio.NewReader(os.Stdin)
+		if !set["region"] {
+			answers.Region = promptString(in, i18n.T("cli.init.prompt.region", strings.Join(setup.Regions(), ", ")), "other", func(value string) error {
+				_, err := setup.LookupRegion(value)
+				return err
+			})
+		}
+		if !set["download-mbps"] {
+			answers.DownloadMbps = promptFloat(in, i18n.T("cli.init.prompt.download"))
+		}
+		if !set["upload-mbps"] {
+			answers.UploadMbps = promptFloat(in, i18n.T("cli.init.prompt.upload"))
+		}
+	}
+	if answers.Region == "" {
+		answers.Region = "other"
+	}
+
+	data, err := setup.Render(detected, answers)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	fmt.Println(i18n.T("cli.init.written", path))
+}
+
+func promptString(in *bufio.Reader, question, fallback string, check func(string) error) string {
+	for {
+		fmt.Printf("%s [%s]: ", question, fallback)
+		line, err := in.ReadString('\n')
+		value := strings.TrimSpace(line)
+		if value == "" {
+			return fallback
+		}
+		if checkErr := check(value); checkErr == nil {
+			return value
+		} else if err != nil {
+			return fallback
+		} else {
+			fmt.Println(checkErr)
+		}
+	}
+}
+
+func promptFloat(in *bufio.Reader, question string) float64 {
+	value := promptString(in, question, "0", func(value string) error {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("%s", i18n.T("cli.init.invalid_number", value))
+		}
+		return nil
+	})
+	parsed, _ := strconv.ParseFloat(value, 64)
+	return parsed
+}
+
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func yesNo(value bool) string {
+	if value {
+		return i18n.T("cli.yes")
+	}
+	return i18n.T("cli.no")
+}
@@ -2,73 +2,265 @@ package main
 
 import (
 	"context"
-	"flag"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
-	"time"
 
 	"conncheck/internal/config"
+	"conncheck/internal/dashboard"
 	"conncheck/internal/engine"
+	"conncheck/internal/history"
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
 	"conncheck/internal/report"
+	"conncheck/internal/timeseries"
 )
 
-func main() {
+// Exit codes: the worst test status of the run, or exitError when the run
+// could not complete or the command line is wrong.
+const (
+	exitOK    = 0
+	exitWarn  = 1
+	exitFail  = 2
+	exitError = 3
+)
+
+func exitCode(worst string) int {
+	switch worst {
+	case "FAIL":
+		return exitFail
+	case "WARN":
+		return exitWarn
+	default:
+		return exitOK
+	}
+}
+
+// fatalf logs and exits with exitError, keeping 1 and 2 for WARN and FAIL.
+func fatalf(logger *log.Logger, format string, args ...any) {
+	logger.Printf(format, args...)
+	os.Exit(exitError)
+}
+
+// runRun runs the suite once and exits with the worst test status. With
+// -json or -ndjson stdout carries only the result or the event stream and
+// the log goes to stderr.
+func runRun(args []string) {
 	var (
-		configPath string
-		outDir     string
-		noUI       bool
+		global    globalFlags
+		selection selectionFlags
+		outDir    string
+		noUI      bool
+		asJSON    bool
+		asNDJSON  bool
 	)
-	flag.StringVar(&configPath, "config", "", "Path to conncheck.yaml")
-	flag.StringVar(&outDir, "out", "", "Output directory (default: ./outputs/<timestamp>)")
-	flag.BoolVar(&noUI, "no-ui", false, "Disable UI (CLI only)")
-	flag.Parse()
+	fs := newFlagSet("run")
+	global.register(fs)
+	selection.register(fs)
+	fs.StringVar(&outDir, "out", "", "Output directory (default: <outputs>/<timestamp>)")
+	fs.BoolVar(&noUI, "no-ui", false, "Never open the status window; show progress in the terminal")
+	fs.BoolVar(&asJSON, "json", false, "Print only the final result as JSON on stdout; log to stderr")
+	fs.BoolVar(&asNDJSON, "ndjson", false, "Print progress events as NDJSON on stdout; log to stderr")
+	parseFlags(fs, args)
 
 	logger := log.New(os.Stdout, "conncheck: ", log.LstdFlags)
-	cfg, err := config.Load(configPath)
+	var events *eventWriter
+	if asJSON || asNDJSON {
+		logger.SetOutput(os.Stderr)
+		noUI = true
+	}
+	if asNDJSON {
+		events = &eventWriter{enc: json.NewEncoder(os.Stdout)}
+	}
+	listener := events.listener()
+	useWindow := !noUI && guiAvailable
+	var dash *dashboard.Dashboard
+	if !asJSON && !asNDJSON && !useWindow {
+		dash = dashboard.New(os.Stdout, dashboard.IsTerminal(os.Stdout))
+		logger.SetOutput(dash)
+		listener = dash.Handle
+	}
+	fail := func(format string, args ...any) {
+		if dash != nil {
+			dash.Close()
+		}
+		events.emit(model.Event{Type: "error", Message: fmt.Sprintf(format, args...), ExitCode: intPtr(exitError)})
+		fatalf(logger, format, args...)
+	}
+
+	cfg, err := global.load()
 	if err != nil {
-		logger.Fatalf("config load failed: %v", err)
+		fail("config load failed: %v", err)
+	}
+	sel, err := selection.parse()
+	if err != nil {
+		fail("%v", err)
+	}
+	if cfg.Source == "" {
+		logger.Println(i18n.T("cli.config_defaults"))
+	} else {
+		logger.Println(i18n.T("cli.config_loaded", cfg.Source))
 	}
 	if outDir == "" {
-		outDir = filepath.Join("outputs", time.Now().Format("20060102-150405"))
+		outDir = history.NewRunDir(global.outputs)
 	}
 	if cfg.OutputDir != "" {
 		outDir = cfg.OutputDir
 	}
 
 	if err := os.MkdirAll(filepath.Join(outDir, "raw_logs"), 0o755); err != nil {
-		logger.Fatalf("failed to create output dir: %v", err)
+		fail("failed to create output dir: %v", err)
+	}
+
+	run := suite{cfg: cfg, sel: sel, logger: logger, events: listener}
+	if useWindow {
+		os.Exit(runWithWindow(run, outDir))
+	}
+	result, paths, err := run.run(context.Background(), outDir)
+	if err != nil {
+		fail("%v", err)
+	}
+	if dash != nil {
+		dash.Close()
 	}
 
-	if !noUI {
-		logger.Println("UI mode placeholder: status window will be added later")
+	logOutputs(logger, result, paths)
+
+	worst := report.WorstStatus(result)
+	code := exitCode(worst)
+	switch {
+	case asNDJSON:
+		events.emit(model.Event{Type: "run_end", Status: worst, Run: &result, Outputs: paths, ExitCode: &code})
+	case asJSON:
+		if err := writeJSON(os.Stdout, result); err != nil {
+			fatalf(logger, "%v", err)
+		}
+	default:
+		fmt.Println()
 	}
+	os.Exit(code)
+}
 
-	engine := engine.Engine{Cfg: cfg, Logger: logger, OutDir: outDir}
-	ctx := context.Background()
+// logOutputs logs the files a run wrote and its summary.
+func logOutputs(logger *log.Logger, result model.Result, paths []string) {
+	logger.Println(i18n.T("cli.outputs"))
+	for _, path := range paths {
+		logger.Printf("- %s", path)
+	}
+	logger.Println(i18n.T("cli.summary", report.FormatSummary(result)))
+	logger.Println(i18n.T("cli.done"))
+}
+
+// suite is what a run needs besides its output folder.
+type suite struct {
+	cfg    config.Config
+	sel    engine.Selection
+	logger *log.Logger
+	// events, when set, receives the engine's progress events.
+	events func(model.Event)
+}
+
+// run executes the tests that the configuration enables and the selection
+// keeps, and writes every output format into outDir, returning the result
+// and the paths written.
+func (s suite) run(ctx context.Context, outDir string) (model.Result, []string, error) {
+	cfg, logger := s.cfg, s.logger
+	effectivePath := filepath.Join(outDir, config.EffectiveFilename)
+	if err := config.WriteEffective(effectivePath, cfg); err != nil {
+		return model.Result{}, nil, fmt.Errorf("write effective config failed: %w", err)
+	}
+
+	engine := engine.Engine{Cfg: cfg, Selection: s.sel, Logger: logger, OutDir: outDir, Events: s.events}
 	result, err := engine.Run(ctx)
 	if err != nil {
-		logger.Fatalf("run failed: %v", err)
+		return result, nil, fmt.Errorf("run failed: %w", err)
 	}
 
 	jsonPath, err := report.WriteJSON(outDir, result)
 	if err != nil {
-		logger.Fatalf("write json failed: %v", err)
+		return result, nil, fmt.Errorf("write json failed: %w", err)
+	}
+	reportPaths, err := writeReports(outDir, result, cfg)
+	if err != nil {
+		return result, nil, err
+	}
+	paths := append([]string{jsonPath, effectivePath}, reportPaths...)
+	if cfg.Exporter.TextfilePath != "" {
+		if err := report.WriteOpenMetricsFile(cfg.Exporter.TextfilePath, result); err != nil {
+			return result, nil, fmt.Errorf("write textfile metrics failed: %w", err)
+		}
+		paths = append(paths, cfg.Exporter.TextfilePath)
+	}
+	seriesPaths, err := exportTimeseries(ctx, cfg, outDir, result, logger)
+	if err != nil {
+		return result, nil, fmt.Errorf("write time series failed: %w", err)
 	}
+	return result, append(paths, seriesPaths...), nil
+}
+
+// writeReports writes every format derived from result: XML, HTML, JUnit,
+// OpenMetrics and CSV. The report command uses it to rebuild a past run.
+func writeReports(outDir string, result model.Result, cfg config.Config) ([]string, error) {
 	xmlPath, err := report.WriteXML(outDir, result)
 	if err != nil {
-		logger.Fatalf("write xml failed: %v", err)
+		return nil, fmt.Errorf("write xml failed: %w", err)
 	}
 	htmlPath, err := report.WriteHTML(outDir, result, cfg)
 	if err != nil {
-		logger.Fatalf("write html failed: %v", err)
+		return nil, fmt.Errorf("write html failed: %w", err)
 	}
+	junitPath, err := report.WriteJUnit(outDir, result, cfg.JUnit.WarnAs)
+	if err != nil {
+		return nil, fmt.Errorf("write junit failed: %w", err)
+	}
+	metricsPath, err := report.WriteOpenMetrics(outDir, result)
+	if err != nil {
+		return nil, fmt.Errorf("write metrics failed: %w", err)
+	}
+	csvPaths, err := report.WriteCSV(outDir, result)
+	if err != nil {
+		return nil, fmt.Errorf("write csv failed: %w", err)
+	}
+	return append([]string{xmlPath, htmlPath, junitPath, metricsPath}, csvPaths...), nil
+}
 
-	logger.Println("Outputs generated:")
-	logger.Printf("- %s\n- %s\n- %s", jsonPath, xmlPath, htmlPath)
-	logger.Printf("Summary: %s", report.FormatSummary(result))
-	logger.Println("Done.")
-
-	fmt.Println()
+// exportTimeseries writes the configured time-series files and pushes to the
+// HTTP sink. A failed push is logged rather than failing the run, since the
+// files in outDir already hold the data.
+func exportTimeseries(ctx context.Context, cfg config.Config, outDir string, result model.Result, logger *log.Logger) ([]string, error) {
+	if len(cfg.Timeseries.Formats) == 0 && cfg.Timeseries.Push.URL == "" {
+		return nil, nil
+	}
+	points := timeseries.Collect(result)
+	var paths []string
+	for _, name := range cfg.Timeseries.Formats {
+		format, err := timeseries.ParseFormat(name)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(outDir, "timeseries"+format.Extension())
+		if err := (timeseries.FileSink{Path: path, Format: format}).Write(ctx, points); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	if push := cfg.Timeseries.Push; push.URL != "" {
+		format := timeseries.FormatInflux
+		if push.Format != "" {
+			parsed, err := timeseries.ParseFormat(push.Format)
+			if err != nil {
+				return nil, err
+			}
+			format = parsed
+		}
+		sink := timeseries.HTTPSink{URL: push.URL, Format: format, Token: push.Token, BatchSize: push.BatchSize, Retries: push.Retries}
+		if err := sink.Write(ctx, points); err != nil {
+			logger.Println(i18n.T("cli.timeseries.push_failed", err))
+		} else {
+			logger.Println(i18n.T("cli.timeseries.pushed", len(points), push.URL))
+		}
+	}
+	return paths, nil
 }
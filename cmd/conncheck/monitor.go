@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"conncheck/internal/history"
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
+	"conncheck/internal/report"
+)
+
+// runMonitor runs the suite every -interval, each cycle in its own run
+// folder, and prints one summary line per cycle. With -count it stops after
+// that many cycles and exits with the worst status of the last one.
+func runMonitor(args []string) {
+	var (
+		global    globalFlags
+		selection selectionFlags
+		interval  time.Duration
+		count     int
+	)
+	fs := newFlagSet("monitor")
+	global.register(fs)
+	selection.register(fs)
+	fs.DurationVar(&interval, "interval", 15*time.Minute, "Time between the start of two cycles")
+	fs.IntVar(&count, "count", 0, "Stop after this many cycles (0 = run until interrupted)")
+	parseFlags(fs, args)
+
+	logger := log.New(os.Stdout, "conncheck: ", log.LstdFlags)
+	cfg, err := global.load()
+	if err != nil {
+		fatalf(logger, "config load failed: %v", err)
+	}
+	sel, err := selection.parse()
+	if err != nil {
+		fatalf(logger, "%v", err)
+	}
+	if interval <= 0 {
+		fatalf(logger, "-interval must be positive")
+	}
+	logger.Println(i18n.T("cli.exporter.monitor", interval))
+
+	last := ""
+	monitorLoop(context.Background(), suite{cfg: cfg, sel: sel, logger: logger}, global.outputs, interval, count, func(result model.Result) {
+		last = report.WorstStatus(result)
+		logger.Println(i18n.T("cli.summary", report.FormatSummary(result)))
+	})
+	os.Exit(exitCode(last))
+}
+
+// monitorLoop runs the suite every interval, count times or forever when
+// count is 0, and hands each completed result to done. A failed cycle is
+// logged and the loop goes on.
+func monitorLoop(ctx context.Context, run suite, outputs string, interval time.Duration, count int, done func(model.Result)) {
+	logger := run.logger
+	for cycle := 1; count == 0 || cycle <= count; cycle++ {
+		started := time.Now()
+		outDir := history.NewRunDir(outputs)
+		if err := os.MkdirAll(filepath.Join(outDir, "raw_logs"), 0o755); err != nil {
+			logger.Printf("%s", i18n.T("cli.exporter.cycle_failed", err))
+		} else if result, _, err := run.run(ctx, outDir); err != nil {
+			logger.Printf("%s", i18n.T("cli.exporter.cycle_failed", err))
+		} else {
+			done(result)
+		}
+		if count != 0 && cycle == count {
+			return
+		}
+		if wait := interval - time.Since(started); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"conncheck/internal/config"
+	"conncheck/internal/history"
+	"conncheck/internal/i18n"
+)
+
+// runReport rebuilds the reports of a run from its results.json, e.g. in
+// another language or after an upgrade. The run's effective_config.yaml is
+// used unless -config is given.
+func runReport(args []string) {
+	var global globalFlags
+	fs := newFlagSet("report")
+	global.register(fs)
+	parseFlags(fs, args)
+
+	logger := log.New(os.Stdout, "conncheck: ", log.LstdFlags)
+	dir, err := runArg(fs.Arg(0), global.outputs)
+	if err != nil {
+		fatalf(logger, "%v", err)
+	}
+	result, err := history.Load(dir)
+	if err != nil {
+		fatalf(logger, "%v", err)
+	}
+	if global.configPath == "" {
+		effective := filepath.Join(dir, config.EffectiveFilename)
+		if _, err := os.Stat(effective); err == nil {
+			global.configPath = effective
+		}
+	}
+	cfg, err := global.load()
+	if err != nil {
+		fatalf(logger, "config load failed: %v", err)
+	}
+	paths, err := writeReports(dir, result, cfg)
+	if err != nil {
+		fatalf(logger, "%v", err)
+	}
+	logger.Println(i18n.T("cli.outputs"))
+	for _, path := range paths {
+		logger.Printf("- %s", path)
+	}
+}
+
+// runDiff compares two runs: test statuses, findings and numeric metrics.
+// With one argument the run is compared with the latest one; with none the
+// latest two runs are compared.
+func runDiff(args []string) {
+	var (
+		global    globalFlags
+		minChange float64
+		all       bool
+	)
+	fs := newFlagSet("diff")
+	global.register(fs)
+	fs.Float64Var(&minChange, "min-change", 10, "Hide metrics that moved by less than this percentage")
+	fs.BoolVar(&all, "all", false, "Show every numeric metric, changed or not")
+	parseFlags(fs, args)
+	if err := global.setLanguage(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+
+	oldDir, newDir, err := diffArgs(fs.Args(), global.outputs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	oldResult, err := history.Load(oldDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	newResult, err := history.Load(newDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	if all {
+		minChange = 0
+	}
+
+	c := history.Compare(oldResult, newResult, minChange)
+	fmt.Println(i18n.T("cli.diff.header", oldDir, newDir))
+	if len(c.Statuses) == 0 && len(c.Added) == 0 && len(c.Resolved) == 0 && len(c.Metrics) == 0 {
+		fmt.Println(i18n.T("cli.diff.none"))
+		return
+	}
+	if len(c.Statuses) > 0 {
+		fmt.Println()
+		fmt.Println(i18n.T("cli.diff.statuses"))
+		for _, change := range c.Statuses {
+			fmt.Printf("  %-14s %s -> %s\n", change.Test, statusOrNotRun(change.Old), statusOrNotRun(change.New))
+		}
+	}
+	if len(c.Added) > 0 {
+		fmt.Println()
+		fmt.Println(i18n.T("cli.diff.added"))
+		for _, finding := range c.Added {
+			fmt.Printf("  %s: %s — %s\n", finding.Severity, finding.Title, finding.Detail)
+		}
+	}
+	if len(c.Resolved) > 0 {
+		fmt.Println()
+		fmt.Println(i18n.T("cli.diff.resolved"))
+		for _, finding := range c.Resolved {
+			fmt.Printf("  %s: %s\n", finding.Severity, finding.Title)
+		}
+	}
+	if len(c.Metrics) > 0 {
+		fmt.Println()
+		fmt.Println(i18n.T("cli.diff.metrics", minChange))
+		for _, change := range c.Metrics {
+			fmt.Printf("  %-14s %-40s %12s -> %-12s %s\n", change.Test, change.Key, metricOrDash(change.Old, change.HasOld), metricOrDash(change.New, change.HasNew), deltaLabel(change))
+		}
+	}
+}
+
+// runArg resolves an optional run argument: a run folder, a results.json,
+// the name of a folder under outputs, or the latest run when empty.
+func runArg(arg, outputs string) (string, error) {
+	if arg == "" {
+		run, err := history.Latest(outputs)
+		return run.Dir, err
+	}
+	if _, err := os.Stat(arg); err != nil {
+		if _, nameErr := os.Stat(filepath.Join(outputs, arg)); nameErr == nil {
+			return filepath.Join(outputs, arg), nil
+		}
+		return "", err
+	}
+	return history.RunDir(arg), nil
+}
+
+func diffArgs(args []string, outputs string) (string, string, error) {
+	switch len(args) {
+	case 0:
+		runs, err := history.List(outputs)
+		if err != nil {
+			return "", "", err
+		}
+		if len(runs) < 2 {
+			return "", "", fmt.Errorf("%s: need two completed runs to compare", outputs)
+		}
+		return runs[1].Dir, runs[0].Dir, nil
+	case 1:
+		oldDir, err := runArg(args[0], outputs)
+		if err != nil {
+			return "", "", err
+		}
+		newDir, err := runArg("", outputs)
+		return oldDir, newDir, err
+	default:
+		oldDir, err := runArg(args[0], outputs)
+		if err != nil {
+			return "", "", err
+		}
+		newDir, err := runArg(args[1], outputs)
+		return oldDir, newDir, err
+	}
+}
+
+func statusOrNotRun(status string) string {
+	if status == "" {
+		return i18n.T("cli.diff.not_run")
+	}
+	return status
+}
+
+func metricOrDash(value float64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func deltaLabel(change history.MetricChange) string {
+	if !change.HasOld || !change.HasNew {
+		return ""
+	}
+	delta := change.DeltaPct()
+	if math.IsInf(delta, 0) {
+		return "(was 0)"
+	}
+	return fmt.Sprintf("(%+.0f%%)", delta)
+}
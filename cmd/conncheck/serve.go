@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"conncheck/internal/engine"
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
+	"conncheck/internal/webui"
+)
+
+const defaultServeListen = "127.0.0.1:8765"
+
+// runServe serves the web app: start runs, follow them live and browse the
+// run folders. It listens on loopback only unless -allow-remote is given,
+// since run folders hold addresses and logs and the app can start runs.
+func runServe(args []string) {
+	var (
+		global      globalFlags
+		listen      string
+		allowRemote bool
+	)
+	fs := newFlagSet("serve")
+	global.register(fs)
+	fs.StringVar(&listen, "listen", defaultServeListen, "Listen address")
+	fs.BoolVar(&allowRemote, "allow-remote", false, "Allow a listen address reachable from other machines")
+	parseFlags(fs, args)
+
+	logger := log.New(os.Stdout, "conncheck: ", log.LstdFlags)
+	if _, err := global.load(); err != nil {
+		fatalf(logger, "config load failed: %v", err)
+	}
+	if !allowRemote && !webui.IsLoopback(listen) {
+		fatalf(logger, "%s", i18n.T("cli.serve.not_loopback", listen))
+	}
+
+	app, err := webui.New(global.outputs, func(ctx context.Context, dir string, req webui.RunRequest, events func(model.Event)) (model.Result, error) {
+		return serveRun(ctx, global, dir, req, events, logger)
+	})
+	if err != nil {
+		fatalf(logger, "serve: %v", err)
+	}
+	app.AllowRemote = allowRemote
+
+	logger.Println(i18n.T("cli.serve.listening", listen, global.outputs))
+	server := &http.Server{
+		Addr:              listen,
+		Handler:           app.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err := server.ListenAndServe(); err != nil {
+		fatalf(logger, "serve: %v", err)
+	}
+}
+
+// serveRun is a run started from the web app. The configuration is loaded
+// again with the chosen mode as a -set layer, so effective_config.yaml
+// records where it came from.
+func serveRun(ctx context.Context, global globalFlags, dir string, req webui.RunRequest, events func(model.Event), logger *log.Logger) (model.Result, error) {
+	sets := append(setFlags{}, global.sets...)
+	sets = append(sets, "mode="+req.Mode)
+	global.sets = sets
+	cfg, err := global.load()
+	if err != nil {
+		return model.Result{}, err
+	}
+	sel, err := engine.ParseSelection(req.Only, req.Skip)
+	if err != nil {
+		return model.Result{}, err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "raw_logs"), 0o755); err != nil {
+		return model.Result{}, err
+	}
+	logger.Println(i18n.T("cli.serve.run_started", dir))
+	run := suite{cfg: cfg, sel: sel, logger: logger, events: events}
+	result, paths, err := run.run(ctx, dir)
+	if err != nil {
+		return result, err
+	}
+	logOutputs(logger, result, paths)
+	return result, nil
+}
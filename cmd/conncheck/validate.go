@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"conncheck/internal/config"
+	"conncheck/internal/i18n"
+)
+
+// runValidate checks the config file and every override layer, and prints
+// each issue with its position. It exits 0 when the result is valid and
+// exitError otherwise.
+func runValidate(args []string) {
+	var global globalFlags
+	fs := newFlagSet("validate")
+	global.register(fs)
+	parseFlags(fs, args)
+	if err := global.setLanguage(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+
+	name := global.configPath
+	if name == "" {
+		name = config.DefaultConfigFilename
+	}
+	_, err := loadConfig(global.configPath, global.sets)
+	var invalid *config.ValidationError
+	if errors.As(err, &invalid) {
+		for _, issue := range invalid.Issues {
+			fmt.Println(issue.String())
+		}
+		fmt.Println(i18n.T("cli.validate.issues", len(invalid.Issues)))
+		os.Exit(exitError)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(exitError)
+	}
+	fmt.Println(i18n.T("cli.validate.ok", name))
+}
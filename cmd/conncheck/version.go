@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"conncheck/internal/model"
+)
+
+// runVersion prints the result format version and, when the binary was
+// built from a git checkout, the commit it was built from.
+func runVersion(args []string) {
+	fs := newFlagSet("version")
+	parseFlags(fs, args)
+
+	build := ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		var revision, modified string
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				if setting.Value == "true" {
+					modified = "+dirty"
+				}
+			}
+		}
+		if len(revision) > 12 {
+			revision = revision[:12]
+		}
+		if revision != "" {
+			build = " (" + revision + modified + ")"
+		}
+	}
+	fmt.Printf("conncheck %s%s %s %s/%s\n", model.Version, build, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
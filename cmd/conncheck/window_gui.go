@@ -0,0 +1,53 @@
+//go:build gui
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2/app"
+
+	"conncheck/internal/gui"
+	"conncheck/internal/report"
+)
+
+// guiAvailable is true in builds with the "gui" tag, which need cgo for the
+// Fyne desktop driver.
+const guiAvailable = true
+
+// runWithWindow runs the suite behind the status window and returns the exit
+// code once the window is closed. Closing it during the run cancels the run,
+// and the window waits for the run to stop so that no file is left half
+// written. The log goes to the window and to run.log, not to the console.
+func runWithWindow(run suite, outDir string) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	window := gui.New(app.NewWithID("conncheck"))
+	run.logger.SetOutput(window)
+	if logFile, err := os.Create(filepath.Join(outDir, "run.log")); err == nil {
+		defer logFile.Close()
+		run.logger.SetOutput(io.MultiWriter(window, logFile))
+	}
+	run.events = window.Handle
+
+	codes := make(chan int, 1)
+	go func() {
+		result, paths, err := run.run(ctx, outDir)
+		if err != nil {
+			run.logger.Printf("%v", err)
+			window.Finish(outDir, "", err)
+			codes <- exitError
+			return
+		}
+		logOutputs(run.logger, result, paths)
+		window.Finish(outDir, report.FormatSummary(result), nil)
+		codes <- exitCode(report.WorstStatus(result))
+	}()
+	window.Window().ShowAndRun()
+	cancel()
+	return <-codes
+}
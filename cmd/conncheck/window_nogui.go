@@ -0,0 +1,11 @@
+//go:build !gui
+
+package main
+
+// guiAvailable is false in builds without the "gui" tag: they have no
+// status window and show progress in the terminal instead.
+const guiAvailable = false
+
+func runWithWindow(run suite, outDir string) int {
+	panic("conncheck: built without the gui tag")
+}
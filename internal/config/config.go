@@ -1,18 +1,24 @@
 package config
 
 import (
-	"fmt"
 	"os"
+)
+
+const (
+	DefaultConfigFilename = "conncheck.yaml"
+	EffectiveFilename     = "effective_config.yaml"
 
-	"gopkg.in/yaml.v3"
+	redacted = "<redacted>"
 )
 
-const DefaultConfigFilename = "conncheck.yaml"
+// Modes lists the run depths offered for mode.
+var Modes = []string{"quick", "standard", "deep"}
 
 // Config holds user-tunable settings.
 type Config struct {
 	Mode        string        `yaml:"mode"`
 	Privacy     string        `yaml:"privacy"`
+	Language    string        `yaml:"language"`
 	OutputDir   string        `yaml:"output_dir"`
 	Tests       TestsConfig   `yaml:"tests"`
 	Targets     TargetsConfig `yaml:"targets"`
@@ -20,10 +26,29 @@ type Config struct {
 	Speedtest   Speedtest     `yaml:"speedtest"`
 	SpeedtestUI SpeedtestUI   `yaml:"speedtest_ui"`
 	HTTP        HTTPChecks    `yaml:"http"`
+	Gateway     Gateway       `yaml:"gateway"`
+	WiFi        WiFi          `yaml:"wifi"`
 	Bufferbloat Bufferbloat   `yaml:"bufferbloat"`
+	Exporter    Exporter      `yaml:"exporter"`
+	Timeseries  Timeseries    `yaml:"timeseries"`
+	JUnit       JUnit         `yaml:"junit"`
+	Plan        Plan          `yaml:"plan"`
+
+	// Extends names a base file this one is layered on; see LoadLayered.
+	Extends string `yaml:"extends,omitempty"`
+
+	// Source is the file the config was loaded from; empty means defaults only.
+	Source string `yaml:"-"`
+	// Origins maps dotted key paths to the layer that set them. Keys missing
+	// from the map keep their defaults.
+	Origins map[string]Origin `yaml:"-"`
 }
 
+// TargetsConfig lists what each test probes. Entries in ping_targets,
+// traceroute_targets, mtu_targets and http.endpoints are either literal
+// addresses or references into Catalog; see Resolve.
 type TargetsConfig struct {
+	Catalog             []Target `yaml:"catalog"`
 	PingTargets         []string `yaml:"ping_targets"`
 	DNSServers          []string `yaml:"dns_servers"`
 	DNSDomains          []string `yaml:"dns_domains"`
@@ -43,6 +68,9 @@ type TestsConfig struct {
 	Speedtest    *bool `yaml:"speedtest"`
 	Traceroute   *bool `yaml:"traceroute"`
 	HTTPCheck    *bool `yaml:"http_check"`
+	Neighbors    *bool `yaml:"neighbors"`
+	Interfaces   *bool `yaml:"interfaces"`
+	WiFi         *bool `yaml:"wifi"`
 }
 
 func (t TestsConfig) WithDefaults() TestsConfig {
@@ -57,6 +85,9 @@ func (t TestsConfig) WithDefaults() TestsConfig {
 		Speedtest:    boolPtrOr(t.Speedtest, true),
 		Traceroute:   boolPtrOr(t.Traceroute, true),
 		HTTPCheck:    boolPtrOr(t.HTTPCheck, true),
+		Neighbors:    boolPtrOr(t.Neighbors, true),
+		Interfaces:   boolPtrOr(t.Interfaces, true),
+		WiFi:         boolPtrOr(t.WiFi, true),
 	}
 }
 
@@ -82,6 +113,12 @@ func (t TestsConfig) IsEnabled(testName string) bool {
 		return boolValue(t.Traceroute, true)
 	case "http_check":
 		return boolValue(t.HTTPCheck, true)
+	case "neighbors":
+		return boolValue(t.Neighbors, true)
+	case "interfaces":
+		return boolValue(t.Interfaces, true)
+	case "wifi":
+		return boolValue(t.WiFi, true)
 	default:
 		return true
 	}
@@ -94,6 +131,12 @@ type Thresholds struct {
 	PacketLossFailPct int `yaml:"packet_loss_fail_pct"`
 	BufferbloatWarnMs int `yaml:"bufferbloat_warn_ms"`
 	BufferbloatFailMs int `yaml:"bufferbloat_fail_ms"`
+	// Gateway thresholds decide whether the LAN/Wi-Fi is the likely
+	// bottleneck; gateway loss uses the packet_loss thresholds.
+	GatewayP95WarnMs    int `yaml:"gateway_p95_warn_ms"`
+	GatewayP95FailMs    int `yaml:"gateway_p95_fail_ms"`
+	GatewayJitterWarnMs int `yaml:"gateway_jitter_warn_ms"`
+	GatewayJitterFailMs int `yaml:"gateway_jitter_fail_ms"`
 }
 
 type Speedtest struct {
@@ -115,11 +158,14 @@ type SpeedtestUI struct {
 	Comparisons   SpeedtestCompare `yaml:"comparisons"`
 }
 
+// SpeedtestScale describes one band of the speedtest scale. Key refers to a
+// message catalog entry used when Label/Description are left empty.
 type SpeedtestScale struct {
 	MinMbps     float64 `yaml:"min_mbps"`
 	MaxMbps     float64 `yaml:"max_mbps"`
-	Label       string  `yaml:"label"`
-	Description string  `yaml:"description"`
+	Key         string  `yaml:"key,omitempty"`
+	Label       string  `yaml:"label,omitempty"`
+	Description string  `yaml:"description,omitempty"`
 }
 
 type SpeedtestCompare struct {
@@ -132,15 +178,69 @@ type HTTPChecks struct {
 	Endpoints []string `yaml:"endpoints"`
 }
 
+// Gateway configures how lan_health samples the default gateway: Samples
+// echo requests, one every IntervalMs.
+type Gateway struct {
+	Samples    int `yaml:"samples"`
+	IntervalMs int `yaml:"interval_ms"`
+}
+
+// WiFi configures the wifi test. Scan also lists the networks around to
+// score how crowded the current channel is; it is off by default because a
+// scan pauses traffic on some adapters for a few seconds.
+type WiFi struct {
+	Scan bool `yaml:"scan"`
+}
+
 type Bufferbloat struct {
 	DownloadURL string `yaml:"download_url"`
 	UploadURL   string `yaml:"upload_url"`
 }
 
+// Exporter configures metrics export. TextfilePath, when set, receives a
+// copy of metrics.prom after every run (e.g. the node_exporter textfile
+// collector directory); Listen is the default address of `conncheck exporter`.
+type Exporter struct {
+	TextfilePath string `yaml:"textfile_path"`
+	Listen       string `yaml:"listen"`
+}
+
+// Timeseries configures the per-sample writers. Formats lists the files
+// written into the output folder (influx, ndjson); Push sends the same points
+// to an HTTP endpoint such as the InfluxDB v2 write API.
+type Timeseries struct {
+	Formats []string       `yaml:"formats"`
+	Push    TimeseriesPush `yaml:"push"`
+}
+
+// TimeseriesPush is the HTTP push sink. Retries 0 uses the default (3); a
+// negative value disables retries.
+type TimeseriesPush struct {
+	URL       string `yaml:"url"`
+	Format    string `yaml:"format"`
+	Token     string `yaml:"token"`
+	BatchSize int    `yaml:"batch_size"`
+	Retries   int    `yaml:"retries"`
+}
+
+// JUnit configures junit.xml. WarnAs maps WARN tests to pass, failure or
+// skipped.
+type JUnit struct {
+	WarnAs string `yaml:"warn_as"`
+}
+
+// Plan is the subscribed line speed. Zero disables the comparison with the
+// speedtest results.
+type Plan struct {
+	DownloadMbps float64 `yaml:"download_mbps"`
+	UploadMbps   float64 `yaml:"upload_mbps"`
+}
+
 func Default() Config {
 	return Config{
 		Mode:      "standard",
 		Privacy:   "standard",
+		Language:  "en",
 		OutputDir: "",
 		Tests: TestsConfig{
 			Preflight:    boolPtr(true),
@@ -153,6 +253,9 @@ func Default() Config {
 			Speedtest:    boolPtr(true),
 			Traceroute:   boolPtr(true),
 			HTTPCheck:    boolPtr(true),
+			Neighbors:    boolPtr(true),
+			Interfaces:   boolPtr(true),
+			WiFi:         boolPtr(true),
 		},
 		Targets: TargetsConfig{
 			PingTargets:         []string{"1.1.1.1", "8.8.8.8"},
@@ -163,12 +266,16 @@ func Default() Config {
 			MTUTargets:          []string{"1.1.1.1"},
 		},
 		Thresholds: Thresholds{
-			PingWarnMs:        50,
-			PingFailMs:        100,
-			PacketLossWarnPct: 2,
-			PacketLossFailPct: 5,
-			BufferbloatWarnMs: 40,
-			BufferbloatFailMs: 80,
+			PingWarnMs:          50,
+			PingFailMs:          100,
+			PacketLossWarnPct:   2,
+			PacketLossFailPct:   5,
+			BufferbloatWarnMs:   40,
+			BufferbloatFailMs:   80,
+			GatewayP95WarnMs:    10,
+			GatewayP95FailMs:    30,
+			GatewayJitterWarnMs: 5,
+			GatewayJitterFailMs: 15,
 		},
 		Speedtest: Speedtest{
 			Local: SpeedtestCategory{
@@ -195,90 +302,76 @@ func Default() Config {
 		SpeedtestUI: SpeedtestUI{
 			DownloadScale: []SpeedtestScale{
 				{
-					MinMbps:     0,
-					MaxMbps:     20,
-					Label:       "Sofferenza 🐢",
-					Description: "ADSL / 3G / sat base / FWA molto distante",
+					MinMbps: 0,
+					MaxMbps: 20,
+					Key:     "speedtest.scale.download.suffering",
 				},
 				{
-					MinMbps:     20,
-					MaxMbps:     200,
-					Label:       "Si vive 🚶‍♂️",
-					Description: "VDSL / 4G medio / FWA ok / sat decente",
+					MinMbps: 20,
+					MaxMbps: 200,
+					Key:     "speedtest.scale.download.livable",
 				},
 				{
-					MinMbps:     200,
-					MaxMbps:     600,
-					Label:       "IDEALE ⭐🚀",
-					Description: "Uso normale + streaming + call + gaming senza pensieri",
+					MinMbps: 200,
+					MaxMbps: 600,
+					Key:     "speedtest.scale.download.ideal",
 				},
 				{
-					MinMbps:     600,
-					MaxMbps:     1000,
-					Label:       "Scheggia ⚡",
-					Description: "5G serio / FTTH buona",
+					MinMbps: 600,
+					MaxMbps: 1000,
+					Key:     "speedtest.scale.download.fast",
 				},
 				{
-					MinMbps:     1000,
-					MaxMbps:     2300,
-					Label:       "Fibra Ottima 👑",
-					Description: "FTTH 1–2.5G",
+					MinMbps: 1000,
+					MaxMbps: 2300,
+					Key:     "speedtest.scale.download.fibre_great",
 				},
 				{
-					MinMbps:     2300,
-					MaxMbps:     0,
-					Label:       "Fibra Divina 🔱✨",
-					Description: "FTTH 2.5G+ / roba da datacenter a casa",
+					MinMbps: 2300,
+					MaxMbps: 0,
+					Key:     "speedtest.scale.download.fibre_divine",
 				},
 			},
 			UploadScale: []SpeedtestScale{
 				{
-					MinMbps:     0,
-					MaxMbps:     2,
-					Label:       "Sofferenza 🐢",
-					Description: "ADSL / sat base / 4G scarso / FWA lontana",
+					MinMbps: 0,
+					MaxMbps: 2,
+					Key:     "speedtest.scale.upload.suffering",
 				},
 				{
-					MinMbps:     2,
-					MaxMbps:     20,
-					Label:       "Si vive 🚶‍♂️",
-					Description: "VDSL / 4G medio / FWA ok / sat decente",
+					MinMbps: 2,
+					MaxMbps: 20,
+					Key:     "speedtest.scale.upload.livable",
 				},
 				{
-					MinMbps:     20,
-					MaxMbps:     100,
-					Label:       "Spinge 🚗💨",
-					Description: "4G top / 5G meh / Starlink / FWA buona",
+					MinMbps: 20,
+					MaxMbps: 100,
+					Key:     "speedtest.scale.upload.pushing",
 				},
 				{
-					MinMbps:     100,
-					MaxMbps:     300,
-					Label:       "IDEALE ⭐🚀",
-					Description: "Upload adatto ad ogni uso",
+					MinMbps: 100,
+					MaxMbps: 300,
+					Key:     "speedtest.scale.upload.ideal",
 				},
 				{
-					MinMbps:     300,
-					MaxMbps:     500,
-					Label:       "FTTH Buona ⚡",
-					Description: "Profili fibra moderni (300/500 up)",
+					MinMbps: 300,
+					MaxMbps: 500,
+					Key:     "speedtest.scale.upload.ftth_good",
 				},
 				{
-					MinMbps:     500,
-					MaxMbps:     1000,
-					Label:       "FTTH Ottima 👑",
-					Description: "500/1000 up",
+					MinMbps: 500,
+					MaxMbps: 1000,
+					Key:     "speedtest.scale.upload.ftth_great",
 				},
 				{
-					MinMbps:     1000,
-					MaxMbps:     2500,
-					Label:       "FTTH Esagerata 🦾",
-					Description: "1–2.5G up",
+					MinMbps: 1000,
+					MaxMbps: 2500,
+					Key:     "speedtest.scale.upload.ftth_extreme",
 				},
 				{
-					MinMbps:     2500,
-					MaxMbps:     0,
-					Label:       "FTTH Divina 🔱✨",
-					Description: "10G (fino a ~10.000 up)",
+					MinMbps: 2500,
+					MaxMbps: 0,
+					Key:     "speedtest.scale.upload.ftth_divine",
 				},
 			},
 			Comparisons: SpeedtestCompare{
@@ -290,30 +383,57 @@ func Default() Config {
 		HTTP: HTTPChecks{
 			Endpoints: []string{"https://www.cloudflare.com"},
 		},
+		Gateway: Gateway{
+			Samples:    200,
+			IntervalMs: 100,
+		},
 		Bufferbloat: Bufferbloat{
 			DownloadURL: "https://speed.hetzner.de/100MB.bin",
 			UploadURL:   "",
 		},
+		Exporter: Exporter{
+			TextfilePath: "",
+			Listen:       "127.0.0.1:9469",
+		},
+		Timeseries: Timeseries{
+			Formats: []string{},
+			Push: TimeseriesPush{
+				Format:    "influx",
+				BatchSize: 5000,
+				Retries:   3,
+			},
+		},
+		JUnit: JUnit{
+			WarnAs: "pass",
+		},
 	}
 }
 
+// Load returns Default() with the YAML file at path and the CONNCHECK_*
+// environment merged over it; see LoadLayered. When path is empty and
+// conncheck.yaml does not exist only the defaults and the environment are
+// used; an explicit path that does not exist is an error.
 func Load(path string) (Config, error) {
-	if path == "" {
-		path = DefaultConfigFilename
-	}
-	data, err := os.ReadFile(path)
+	return LoadLayered(Sources{Path: path, Env: os.Environ()})
+}
+
+// WriteEffective writes cfg as YAML to path, with secrets redacted and the
+// origin of every value as a comment, so a run folder records exactly which
+// settings produced it.
+func WriteEffective(path string, cfg Config) error {
+	data, err := Show(cfg)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return Config{}, fmt.Errorf("config file not found: %s", path)
-		}
-		return Config{}, err
+		return err
 	}
-	cfg := Config{}
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return Config{}, err
+	header := "# Effective configuration: built-in defaults merged with " + sourceLabel(cfg.Source) + ".\n"
+	return os.WriteFile(path, append([]byte(header), data...), 0o644)
+}
+
+func sourceLabel(source string) string {
+	if source == "" {
+		return "no config file"
 	}
-	cfg.Tests = cfg.Tests.WithDefaults()
-	return cfg, nil
+	return source
 }
 
 func boolPtr(value bool) *bool {
@@ -0,0 +1,377 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// EnvPrefix marks environment variables that override config keys, e.g.
+	// CONNCHECK_THRESHOLDS_PING_WARN_MS=80.
+	EnvPrefix = "CONNCHECK_"
+
+	maxExtendsDepth = 8
+)
+
+// Origin says which layer set an effective value.
+type Origin struct {
+	Kind   string // default, file, env or set
+	Name   string // file path, variable name or key path
+	Line   int
+	Column int
+}
+
+func (o Origin) String() string {
+	switch o.Kind {
+	case "file":
+		return fmt.Sprintf("%s:%d:%d", o.Name, o.Line, o.Column)
+	case "env":
+		return "env " + o.Name
+	case "set":
+		return "--set " + o.Name
+	default:
+		return "default"
+	}
+}
+
+// Sources lists the layers merged over Default(), lowest priority first:
+// the config file and the files it extends, then Env, then Set.
+type Sources struct {
+	Path string   // config file; empty looks for conncheck.yaml
+	Env  []string // KEY=value pairs, normally os.Environ()
+	Set  []string // path.to.key=value overrides from --set
+}
+
+type layer struct {
+	name   string
+	node   *yaml.Node
+	origin func(*yaml.Node) Origin
+}
+
+// LoadLayered merges the layers in src over Default(). A config file may name
+// a base file with `extends:` (relative to itself); the base is loaded first
+// and the file only needs the keys that differ. Every layer is validated on
+// its own so issues point at the file, variable or flag that caused them.
+func LoadLayered(src Sources) (Config, error) {
+	var layers []layer
+	var issues []Issue
+
+	source := ""
+	path := src.Path
+	if path == "" {
+		if _, err := os.Stat(DefaultConfigFilename); err == nil {
+			path = DefaultConfigFilename
+		}
+	} else if _, err := os.Stat(path); os.IsNotExist(err) {
+		return Config{}, fmt.Errorf("config file not found: %s", path)
+	}
+	if path != "" {
+		fileLayers, fileIssues, err := loadFileChain(path, nil)
+		if err != nil {
+			return Config{}, err
+		}
+		layers = append(layers, fileLayers...)
+		issues = append(issues, fileIssues...)
+		source = path
+	}
+
+	for _, entry := range src.Env {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		keyPath, ok := envKeyPath(strings.ToLower(strings.TrimPrefix(name, EnvPrefix)))
+		if !ok {
+			issues = append(issues, Issue{File: "env " + name, Message: "does not match any config key"})
+			continue
+		}
+		env := Origin{Kind: "env", Name: name}
+		l, layerIssues, err := valueLayer("env "+name, keyPath, value, env)
+		if err != nil {
+			return Config{}, err
+		}
+		layers = append(layers, l)
+		issues = append(issues, layerIssues...)
+	}
+
+	for _, entry := range src.Set {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			issues = append(issues, Issue{File: "--set " + entry, Message: "expected path.to.key=value"})
+			continue
+		}
+		keyPath := strings.Split(strings.TrimSpace(key), ".")
+		if err := checkKeyPath(keyPath); err != nil {
+			issues = append(issues, Issue{File: "--set " + key, Message: err.Error()})
+			continue
+		}
+		set := Origin{Kind: "set", Name: key}
+		l, layerIssues, err := valueLayer("--set "+key, keyPath, value, set)
+		if err != nil {
+			return Config{}, err
+		}
+		layers = append(layers, l)
+		issues = append(issues, layerIssues...)
+	}
+
+	// The layers are merged even when some have issues, so target
+	// references are checked in the same pass and every problem is
+	// reported at once. A value that failed to decode is already an issue.
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	origins := map[string]Origin{}
+	for _, l := range layers {
+		mergeNodes(merged, l.node, "", l.origin, origins)
+	}
+	cfg := Default()
+	if err := merged.Decode(&cfg); err != nil && len(issues) == 0 {
+		return Config{}, err
+	}
+	cfg.Tests = cfg.Tests.WithDefaults()
+	cfg.Extends = ""
+	cfg.Source = source
+	cfg.Origins = origins
+	issues = append(issues, referenceIssues(cfg)...)
+	if len(issues) > 0 {
+		return Config{}, &ValidationError{Issues: issues}
+	}
+	return cfg, nil
+}
+
+// loadFileChain returns the layers for path and the files it extends, base
+// first. seen guards against cycles.
+func loadFileChain(path string, seen []string) ([]layer, []Issue, error) {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, previous := range seen {
+		if previous == absolute {
+			return nil, nil, fmt.Errorf("config files extend each other in a loop: %s", strings.Join(append(seen, absolute), " -> "))
+		}
+	}
+	if len(seen) >= maxExtendsDepth {
+		return nil, nil, fmt.Errorf("%s: too many levels of extends", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, nil, nil
+	}
+	node := root.Content[0]
+	issues, err := validateNode(path, node, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var layers []layer
+	if base := mappingValue(node, "extends"); base != nil && base.Value != "" {
+		basePath := base.Value
+		if !filepath.IsAbs(basePath) {
+			basePath = filepath.Join(filepath.Dir(path), basePath)
+		}
+		if _, err := os.Stat(basePath); err != nil {
+			issues = append(issues, Issue{File: path, Line: base.Line, Column: base.Column, Path: "extends", Message: fmt.Sprintf("base file %s: %v", basePath, err)})
+		} else {
+			baseLayers, baseIssues, err := loadFileChain(basePath, append(seen, absolute))
+			if err != nil {
+				return nil, nil, err
+			}
+			layers = append(layers, baseLayers...)
+			issues = append(issues, baseIssues...)
+		}
+	}
+	file := path
+	layers = append(layers, layer{name: path, node: node, origin: func(n *yaml.Node) Origin {
+		return Origin{Kind: "file", Name: file, Line: n.Line, Column: n.Column}
+	}})
+	return layers, issues, nil
+}
+
+// valueLayer turns one override into a layer. value is parsed as YAML, so
+// lists can be written as [a, b]; an empty value is an empty string.
+func valueLayer(name string, keyPath []string, value string, origin Origin) (layer, []Issue, error) {
+	valueNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: ""}
+	if strings.TrimSpace(value) != "" {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+			return layer{}, []Issue{{File: name, Message: fmt.Sprintf("cannot parse value: %v", err)}}, nil
+		}
+		if len(doc.Content) > 0 {
+			valueNode = doc.Content[0]
+		}
+	}
+	node := valueNode
+	for i := len(keyPath) - 1; i >= 0; i-- {
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: keyPath[i]},
+			node,
+		}}
+	}
+	issues, err := validateNode(name, node, false)
+	if err != nil {
+		return layer{}, nil, err
+	}
+	// Positions inside a one-line value mean nothing to the user.
+	for i := range issues {
+		issues[i].Line, issues[i].Column = 0, 0
+	}
+	return layer{name: name, node: node, origin: func(*yaml.Node) Origin { return origin }}, issues, nil
+}
+
+// mergeNodes overlays src on dst. Mappings merge key by key; any other value
+// (scalars and whole lists) replaces what dst had. origins records the layer
+// of every leaf src sets.
+func mergeNodes(dst, src *yaml.Node, path string, origin func(*yaml.Node) Origin, origins map[string]Origin) {
+	if src.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		childPath := joinPath(path, key.Value)
+		existing := mappingValue(dst, key.Value)
+		if existing != nil && existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode {
+			mergeNodes(existing, value, childPath, origin, origins)
+			continue
+		}
+		if existing != nil {
+			*existing = *value
+		} else {
+			dst.Content = append(dst.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.Value}, value)
+		}
+		for known := range origins {
+			if strings.HasPrefix(known, childPath+".") || strings.HasPrefix(known, childPath+"[") {
+				delete(origins, known)
+			}
+		}
+		recordOrigins(value, childPath, origin, origins)
+	}
+}
+
+// recordOrigins records the origin of every leaf under node. The items of a
+// list are also recorded one by one, as path[i], so an issue about one item
+// points at its own line.
+func recordOrigins(node *yaml.Node, path string, origin func(*yaml.Node) Origin, origins map[string]Origin) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			recordOrigins(node.Content[i+1], joinPath(path, node.Content[i].Value), origin, origins)
+		}
+		return
+	}
+	origins[path] = origin(node)
+	if node.Kind == yaml.SequenceNode {
+		for i, item := range node.Content {
+			origins[fmt.Sprintf("%s[%d]", path, i)] = origin(item)
+		}
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// envKeyPath maps the part of a variable name after the prefix to a key path.
+// Keys contain underscores themselves, so the Config type decides where one
+// key ends: thresholds_ping_warn_ms becomes thresholds.ping_warn_ms.
+func envKeyPath(name string) ([]string, bool) {
+	return matchKeyPath(reflect.TypeOf(Config{}), name)
+}
+
+func matchKeyPath(t reflect.Type, rest string) ([]string, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	fields := yamlFields(t)
+	names := sortedKeys(fields)
+	// Longest names first so speedtest_ui wins over speedtest.
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	for _, name := range names {
+		if name == "extends" {
+			continue
+		}
+		if rest == name {
+			return []string{name}, true
+		}
+		if strings.HasPrefix(rest, name+"_") {
+			if tail, ok := matchKeyPath(fields[name].Type, strings.TrimPrefix(rest, name+"_")); ok {
+				return append([]string{name}, tail...), true
+			}
+		}
+	}
+	return nil, false
+}
+
+func checkKeyPath(keyPath []string) error {
+	t := reflect.TypeOf(Config{})
+	for i, key := range keyPath {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return fmt.Errorf("%s is not a section", strings.Join(keyPath[:i], "."))
+		}
+		fields := yamlFields(t)
+		field, ok := fields[key]
+		if !ok || key == "extends" {
+			return fmt.Errorf("unknown key %s%s", strings.Join(keyPath[:i+1], "."), suggestion(key, fields))
+		}
+		t = field.Type
+	}
+	return nil
+}
+
+// Show renders cfg as YAML with the origin of every value as a line comment.
+// Secrets are redacted.
+func Show(cfg Config) ([]byte, error) {
+	if cfg.Timeseries.Push.Token != "" {
+		cfg.Timeseries.Push.Token = redacted
+	}
+	var node yaml.Node
+	if err := node.Encode(cfg); err != nil {
+		return nil, err
+	}
+	annotate(&node, "", cfg.Origins)
+	return yaml.Marshal(&node)
+}
+
+func annotate(node *yaml.Node, path string, origins map[string]Origin) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		childPath := joinPath(path, key.Value)
+		if value.Kind == yaml.MappingNode {
+			annotate(value, childPath, origins)
+			continue
+		}
+		label := Origin{}.String()
+		if origin, ok := origins[childPath]; ok {
+			label = origin.String()
+		}
+		if value.Kind == yaml.SequenceNode && len(value.Content) > 0 {
+			key.LineComment = label
+		} else {
+			value.LineComment = label
+		}
+	}
+}
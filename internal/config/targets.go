@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// TagPrefix marks a target list entry that selects every catalog target with
+// that tag or region, e.g. "tag:eu".
+const TagPrefix = "tag:"
+
+// Target is a named entry of targets.catalog. Stack, when set, restricts
+// probes to ipv4 or ipv6; URL is what http_check fetches and defaults to
+// https://<address>.
+type Target struct {
+	Name    string   `yaml:"name"`
+	Address string   `yaml:"address"`
+	Region  string   `yaml:"region,omitempty"`
+	Stack   string   `yaml:"stack,omitempty"`
+	Tags    []string `yaml:"tags,omitempty"`
+	URL     string   `yaml:"url,omitempty"`
+}
+
+// Lookup returns the catalog target called name.
+func (t TargetsConfig) Lookup(name string) (Target, bool) {
+	for _, target := range t.Catalog {
+		if target.Name == name {
+			return target, true
+		}
+	}
+	return Target{}, false
+}
+
+// Tagged returns the catalog targets whose tags or region include tag.
+func (t TargetsConfig) Tagged(tag string) []Target {
+	var out []Target
+	for _, target := range t.Catalog {
+		if strings.EqualFold(target.Region, tag) || containsFold(target.Tags, tag) {
+			out = append(out, target)
+		}
+	}
+	return out
+}
+
+// Resolve expands refs into targets, in order and without duplicates. A ref
+// is a catalog name, "tag:<tag>", or a literal address, which becomes an
+// unnamed target.
+func (t TargetsConfig) Resolve(refs []string) []Target {
+	var out []Target
+	seen := map[string]bool{}
+	add := func(target Target) {
+		if seen[target.Address] {
+			return
+		}
+		seen[target.Address] = true
+		out = append(out, target)
+	}
+	for _, ref := range refs {
+		if tag, ok := strings.CutPrefix(ref, TagPrefix); ok {
+			for _, target := range t.Tagged(tag) {
+				add(target)
+			}
+			continue
+		}
+		if target, ok := t.Lookup(ref); ok {
+			add(target)
+			continue
+		}
+		add(Target{Address: ref})
+	}
+	return out
+}
+
+// ResolveEndpoints is Resolve for http.endpoints: each target's Address is
+// replaced by the URL to fetch.
+func (t TargetsConfig) ResolveEndpoints(refs []string) []Target {
+	targets := t.Resolve(refs)
+	for i, target := range targets {
+		if target.Name == "" {
+			continue
+		}
+		url := target.URL
+		if url == "" {
+			host := target.Address
+			if addr, err := netip.ParseAddr(host); err == nil && addr.Is6() {
+				host = "[" + host + "]"
+			}
+			url = "https://" + host
+		}
+		targets[i].Address = url
+	}
+	return targets
+}
+
+// Addresses returns the Address of each target.
+func Addresses(targets []Target) []string {
+	out := make([]string, 0, len(targets))
+	for _, target := range targets {
+		out = append(out, target.Address)
+	}
+	return out
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// targetLists are the settings whose entries may reference the catalog.
+var targetLists = []string{"targets.ping_targets", "targets.traceroute_targets", "targets.mtu_targets", "http.endpoints"}
+
+func targetList(cfg Config, path string) []string {
+	switch path {
+	case "targets.ping_targets":
+		return cfg.Targets.PingTargets
+	case "targets.traceroute_targets":
+		return cfg.Targets.Traceroute
+	case "targets.mtu_targets":
+		return cfg.Targets.MTUTargets
+	default:
+		return cfg.HTTP.Endpoints
+	}
+}
+
+type referenceProblem struct {
+	list    string // the setting, e.g. targets.ping_targets
+	index   int
+	message string
+}
+
+// referenceProblems checks the target lists against the catalog. It runs on
+// the merged config, since a file may use names its base file defines.
+func referenceProblems(cfg Config) []referenceProblem {
+	var problems []referenceProblem
+	for _, list := range targetLists {
+		for i, ref := range targetList(cfg, list) {
+			if tag, ok := strings.CutPrefix(ref, TagPrefix); ok {
+				if len(cfg.Targets.Tagged(tag)) == 0 {
+					problems = append(problems, referenceProblem{list, i, fmt.Sprintf("no catalog target has tag or region %q", tag)})
+				}
+				continue
+			}
+			if _, ok := cfg.Targets.Lookup(ref); ok {
+				continue
+			}
+			if list == "http.endpoints" {
+				if !validURL(ref) {
+					problems = append(problems, referenceProblem{list, i, fmt.Sprintf("%q is neither a catalog target nor an http(s) URL", ref)})
+				}
+				continue
+			}
+			if _, err := netip.ParseAddr(ref); err != nil && !validHostname(ref) {
+				problems = append(problems, referenceProblem{list, i, fmt.Sprintf("%q is neither a catalog target, an IP address nor a hostname", ref)})
+			}
+		}
+	}
+	return problems
+}
+
+// referenceIssues reports referenceProblems at the item that caused each, or
+// at the layer that set the list when the item has no recorded origin.
+func referenceIssues(cfg Config) []Issue {
+	var issues []Issue
+	for _, problem := range referenceProblems(cfg) {
+		path := fmt.Sprintf("%s[%d]", problem.list, problem.index)
+		issue := Issue{Path: path, Message: problem.message}
+		origin, ok := cfg.Origins[path]
+		if !ok {
+			origin = cfg.Origins[problem.list]
+		}
+		switch origin.Kind {
+		case "file":
+			issue.File, issue.Line, issue.Column = origin.Name, origin.Line, origin.Column
+		default:
+			issue.File = origin.String()
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
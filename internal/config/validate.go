@@ -0,0 +1,484 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"conncheck/internal/i18n"
+)
+
+// Issue is one validation problem. Line and Column are 1-based and zero when
+// the problem is not tied to a position in the file.
+type Issue struct {
+	File    string
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (i Issue) String() string {
+	location := i.File
+	if i.Line > 0 {
+		location = fmt.Sprintf("%s:%d:%d", i.File, i.Line, i.Column)
+	}
+	if i.Path == "" {
+		return fmt.Sprintf("%s: %s", location, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", location, i.Path, i.Message)
+}
+
+// ValidationError is returned by Load when the file has issues.
+type ValidationError struct {
+	Issues []Issue
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Issues)+1)
+	lines = append(lines, fmt.Sprintf("invalid configuration (%d issue(s)):", len(e.Issues)))
+	for _, issue := range e.Issues {
+		lines = append(lines, "  "+issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValidateData checks data, reporting issues against file. It looks for keys
+// the Config struct does not know, values that fail to decode, and settings
+// that decode but make no sense (bad addresses, negative counts, overlapping
+// speedtest bands).
+func ValidateData(file string, data []byte) ([]Issue, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+	return validateNode(file, root.Content[0], true)
+}
+
+// validateNode checks one layer. references also checks the target lists
+// against the catalog, which only makes sense for a complete file.
+func validateNode(file string, node *yaml.Node, references bool) ([]Issue, error) {
+	v := &validator{file: file, nodes: map[string]*yaml.Node{}}
+	v.walk(node, reflect.TypeOf(Config{}), "")
+
+	cfg := Default()
+	if err := node.Decode(&cfg); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, err
+		}
+		for _, message := range typeErr.Errors {
+			v.addDecodeError(message)
+		}
+	}
+	v.check(cfg)
+	if references {
+		for _, problem := range referenceProblems(cfg) {
+			v.at(fmt.Sprintf("%s[%d]", problem.list, problem.index), "%s", problem.message)
+		}
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		if v.issues[i].Line != v.issues[j].Line {
+			return v.issues[i].Line < v.issues[j].Line
+		}
+		return v.issues[i].Column < v.issues[j].Column
+	})
+	return v.issues, nil
+}
+
+type validator struct {
+	file   string
+	nodes  map[string]*yaml.Node
+	issues []Issue
+}
+
+func (v *validator) add(path string, node *yaml.Node, format string, args ...any) {
+	issue := Issue{File: v.file, Path: path, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		issue.Line, issue.Column = node.Line, node.Column
+	}
+	v.issues = append(v.issues, issue)
+}
+
+// at reports an issue for path only when the user file set it; values that
+// came from the defaults are not the user's mistake.
+func (v *validator) at(path string, format string, args ...any) {
+	node, ok := v.nodes[path]
+	if !ok {
+		return
+	}
+	v.add(path, node, format, args...)
+}
+
+var decodeLine = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+func (v *validator) addDecodeError(message string) {
+	if match := decodeLine.FindStringSubmatch(message); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		issue := Issue{File: v.file, Line: line, Column: 1, Message: match[2]}
+		// yaml.v3 only reports the line; recover the key path and column
+		// from the scalar that sits on it.
+		for _, path := range sortedKeys(v.nodes) {
+			node := v.nodes[path]
+			if node.Kind == yaml.ScalarNode && node.Line == line {
+				issue.Path, issue.Column = path, node.Column
+				break
+			}
+		}
+		v.issues = append(v.issues, issue)
+		return
+	}
+	v.issues = append(v.issues, Issue{File: v.file, Message: message})
+}
+
+// walk records the node of every known key and reports unknown ones.
+func (v *validator) walk(node *yaml.Node, t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			if !isNull(node) {
+				v.add(path, node, "expected a mapping")
+			}
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			childPath := joinPath(path, key.Value)
+			field, ok := fields[key.Value]
+			if !ok {
+				if t == reflect.TypeOf(TestsConfig{}) {
+					v.add(childPath, key, "unknown test name (known: %s)", strings.Join(sortedKeys(fields), ", "))
+				} else {
+					v.add(childPath, key, "unknown key%s", suggestion(key.Value, fields))
+				}
+				continue
+			}
+			v.nodes[childPath] = value
+			v.walk(value, field.Type, childPath)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			if !isNull(node) {
+				v.add(path, node, "expected a list")
+			}
+			return
+		}
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			v.nodes[itemPath] = item
+			v.walk(item, t.Elem(), itemPath)
+		}
+	}
+}
+
+func (v *validator) check(cfg Config) {
+	if cfg.Language != "" && !contains(i18n.Languages(), i18n.Normalize(cfg.Language)) {
+		v.at("language", "unsupported language %q (available: %s)", cfg.Language, strings.Join(i18n.Languages(), ", "))
+	}
+
+	targets := cfg.Targets
+	v.catalog(targets.Catalog)
+	for i, server := range targets.DNSServers {
+		if _, err := netip.ParseAddr(server); err != nil {
+			v.at(fmt.Sprintf("targets.dns_servers[%d]", i), "%q is not a valid IP address", server)
+		}
+	}
+	for i, domain := range targets.DNSDomains {
+		if !validHostname(domain) {
+			v.at(fmt.Sprintf("targets.dns_domains[%d]", i), "%q is not a valid hostname", domain)
+		}
+	}
+	if targets.DNSQueriesPerDomain < 0 {
+		v.at("targets.dns_queries_per_domain", "must not be negative")
+	}
+
+	thresholds := []struct {
+		name       string
+		warn, fail int
+	}{
+		{"ping", cfg.Thresholds.PingWarnMs, cfg.Thresholds.PingFailMs},
+		{"packet_loss", cfg.Thresholds.PacketLossWarnPct, cfg.Thresholds.PacketLossFailPct},
+		{"bufferbloat", cfg.Thresholds.BufferbloatWarnMs, cfg.Thresholds.BufferbloatFailMs},
+		{"gateway_p95", cfg.Thresholds.GatewayP95WarnMs, cfg.Thresholds.GatewayP95FailMs},
+		{"gateway_jitter", cfg.Thresholds.GatewayJitterWarnMs, cfg.Thresholds.GatewayJitterFailMs},
+	}
+	for _, threshold := range thresholds {
+		warnKey, failKey := thresholdKeys(threshold.name)
+		if threshold.warn < 0 {
+			v.at(warnKey, "must not be negative")
+		}
+		if threshold.fail < 0 {
+			v.at(failKey, "must not be negative")
+		}
+		if threshold.warn > 0 && threshold.fail > 0 && threshold.warn > threshold.fail {
+			key := warnKey
+			if _, ok := v.nodes[key]; !ok {
+				key = failKey
+			}
+			v.at(key, "warning threshold %d is above the failure threshold %d", threshold.warn, threshold.fail)
+		}
+	}
+
+	categories := []struct {
+		name string
+		cfg  SpeedtestCategory
+	}{
+		{"local", cfg.Speedtest.Local},
+		{"national", cfg.Speedtest.National},
+		{"eu", cfg.Speedtest.EU},
+		{"us", cfg.Speedtest.US},
+	}
+	for _, category := range categories {
+		path := "speedtest." + category.name
+		if category.cfg.Runs < 0 {
+			v.at(path+".runs", "must not be negative")
+		}
+		if category.cfg.Weight < 0 {
+			v.at(path+".weight", "must not be negative")
+		}
+		for i, id := range category.cfg.ServerIDs {
+			if id < 0 {
+				v.at(fmt.Sprintf("%s.server_ids[%d]", path, i), "server id %d must not be negative", id)
+			}
+		}
+	}
+	if cfg.Plan.DownloadMbps < 0 {
+		v.at("plan.download_mbps", "must not be negative")
+	}
+	if cfg.Plan.UploadMbps < 0 {
+		v.at("plan.upload_mbps", "must not be negative")
+	}
+	v.scale("speedtest_ui.download_scale", cfg.SpeedtestUI.DownloadScale)
+	v.scale("speedtest_ui.upload_scale", cfg.SpeedtestUI.UploadScale)
+
+	if cfg.Gateway.Samples < 1 {
+		v.at("gateway.samples", "must be at least 1")
+	}
+	if cfg.Gateway.IntervalMs < 10 {
+		v.at("gateway.interval_ms", "must be at least 10")
+	}
+
+	v.url("bufferbloat.download_url", cfg.Bufferbloat.DownloadURL)
+	v.url("bufferbloat.upload_url", cfg.Bufferbloat.UploadURL)
+	v.url("timeseries.push.url", cfg.Timeseries.Push.URL)
+
+	if cfg.Exporter.Listen != "" {
+		if _, _, err := net.SplitHostPort(cfg.Exporter.Listen); err != nil {
+			v.at("exporter.listen", "%q is not a host:port address", cfg.Exporter.Listen)
+		}
+	}
+	for i, format := range cfg.Timeseries.Formats {
+		if format != "influx" && format != "ndjson" {
+			v.at(fmt.Sprintf("timeseries.formats[%d]", i), "unknown format %q (use influx or ndjson)", format)
+		}
+	}
+	if format := cfg.Timeseries.Push.Format; format != "" && format != "influx" && format != "ndjson" {
+		v.at("timeseries.push.format", "unknown format %q (use influx or ndjson)", format)
+	}
+	if cfg.Timeseries.Push.BatchSize < 0 {
+		v.at("timeseries.push.batch_size", "must not be negative")
+	}
+	switch cfg.JUnit.WarnAs {
+	case "", "pass", "failure", "skipped":
+	default:
+		v.at("junit.warn_as", "unknown value %q (use pass, failure or skipped)", cfg.JUnit.WarnAs)
+	}
+}
+
+// catalog checks each target on its own; references to the catalog are
+// checked once the layers are merged (see referenceProblems).
+func (v *validator) catalog(targets []Target) {
+	names := map[string]int{}
+	for i, target := range targets {
+		path := fmt.Sprintf("targets.catalog[%d]", i)
+		switch {
+		case target.Name == "":
+			v.at(path, "name is required")
+		case strings.HasPrefix(target.Name, TagPrefix):
+			v.at(path+".name", "name must not start with %q", TagPrefix)
+		default:
+			if first, ok := names[target.Name]; ok {
+				v.at(path+".name", "duplicate name %q (also targets.catalog[%d])", target.Name, first)
+			}
+			names[target.Name] = i
+		}
+		addr, addrErr := netip.ParseAddr(target.Address)
+		switch {
+		case target.Address == "":
+			v.at(path, "address is required")
+		case addrErr != nil && !validHostname(target.Address):
+			v.at(path+".address", "%q is neither an IP address nor a hostname", target.Address)
+		}
+		switch target.Stack {
+		case "":
+		case "ipv4", "ipv6":
+			if addrErr == nil && (target.Stack == "ipv4") != addr.Is4() {
+				v.at(path+".stack", "address %s is not %s", target.Address, target.Stack)
+			}
+		default:
+			v.at(path+".stack", "unknown stack %q (use ipv4 or ipv6)", target.Stack)
+		}
+		v.url(path+".url", target.URL)
+	}
+}
+
+func (v *validator) url(path, value string) {
+	if value != "" && !validURL(value) {
+		v.at(path, "%q is not an http(s) URL", value)
+	}
+}
+
+func validURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// scale checks that bands are ordered, do not overlap and that only the last
+// band is open-ended (max_mbps: 0).
+func (v *validator) scale(path string, bands []SpeedtestScale) {
+	for i, band := range bands {
+		bandPath := fmt.Sprintf("%s[%d]", path, i)
+		if band.MinMbps < 0 || band.MaxMbps < 0 {
+			v.at(bandPath, "band limits must not be negative")
+			continue
+		}
+		if band.MaxMbps == 0 && i != len(bands)-1 {
+			v.at(bandPath+".max_mbps", "only the last band may be open-ended (max_mbps: 0)")
+		}
+		if band.MaxMbps != 0 && band.MaxMbps <= band.MinMbps {
+			v.at(bandPath+".max_mbps", "max_mbps %g must be above min_mbps %g", band.MaxMbps, band.MinMbps)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := bands[i-1]
+		switch {
+		case band.MinMbps < previous.MinMbps:
+			v.at(bandPath+".min_mbps", "bands must be ordered by min_mbps (%g comes after %g)", band.MinMbps, previous.MinMbps)
+		case previous.MaxMbps != 0 && band.MinMbps < previous.MaxMbps:
+			v.at(bandPath+".min_mbps", "overlaps the previous band (%g-%g)", previous.MinMbps, previous.MaxMbps)
+		}
+	}
+}
+
+func thresholdKeys(name string) (string, string) {
+	unit := "ms"
+	if name == "packet_loss" {
+		unit = "pct"
+	}
+	return fmt.Sprintf("thresholds.%s_warn_%s", name, unit), fmt.Sprintf("thresholds.%s_fail_%s", name, unit)
+}
+
+func validHostname(value string) bool {
+	value = strings.TrimSuffix(value, ".")
+	if value == "" || len(value) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(value, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// suggestion proposes a known key that differs from key by a plural "s" or
+// a single edit, which covers the usual typos.
+func suggestion(key string, fields map[string]reflect.StructField) string {
+	for _, known := range sortedKeys(fields) {
+		if editDistance(key, known) <= 1 || key+"s" == known || key == known+"s" {
+			return fmt.Sprintf(" (did you mean %q?)", known)
+		}
+	}
+	return ""
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func joinPath(base, key string) string {
+	if base == "" {
+		return key
+	}
+	return base + "." + key
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,386 @@
+// Package dashboard renders the engine's progress events on a terminal: a
+// line per test with a spinner and progress bar, RTT sparklines while
+// latency runs and throughput while speedtest runs. When the output is not
+// a terminal it prints plain progress lines instead.
+package dashboard
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
+)
+
+const (
+	redrawInterval = 120 * time.Millisecond
+	sparkWidth     = 30
+	barWidth       = 20
+	nameWidth      = 22
+)
+
+var (
+	spinner    = []string{"|", "/", "-", "\\"}
+	sparkTicks = []rune("▁▂▃▄▅▆▇█")
+)
+
+// Dashboard consumes engine events. Use it as the engine's Events listener
+// and as the logger's output, so log lines print above the live area.
+type Dashboard struct {
+	mu      sync.Mutex
+	out     io.Writer
+	live    bool
+	color   bool
+	width   int
+	started time.Time
+	total   int
+	tests   []*testState
+	byName  map[string]*testState
+	drawn   int
+	frame   int
+	closed  bool
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+type testState struct {
+	name       string
+	index      int
+	status     string
+	started    time.Time
+	ended      time.Time
+	fraction   float64
+	targets    []*rttSeries
+	server     string
+	downBps    float64
+	upBps      float64
+	lastDecile int
+}
+
+type rttSeries struct {
+	target  string
+	samples []float64 // NaN marks a lost probe
+	last    float64
+	lost    int
+	count   int
+}
+
+// New returns a dashboard writing to out. With live set it redraws in place
+// using ANSI escapes; otherwise it prints one line per step.
+func New(out io.Writer, live bool) *Dashboard {
+	d := &Dashboard{
+		out:     out,
+		live:    live,
+		color:   live && os.Getenv("NO_COLOR") == "",
+		width:   terminalWidth(),
+		started: time.Now(),
+		byName:  map[string]*testState{},
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if live {
+		fmt.Fprint(out, "\x1b[?25l")
+		go d.animate()
+	} else {
+		close(d.stopped)
+	}
+	return d
+}
+
+// IsTerminal reports whether f is an interactive terminal that accepts ANSI
+// escapes.
+func IsTerminal(f *os.File) bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	return enableVT(f)
+}
+
+// Handle updates the dashboard with one engine event.
+func (d *Dashboard) Handle(event model.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch event.Type {
+	case "run_start":
+		d.total = event.Total
+	case "test_start":
+		state := &testState{name: event.Test, index: event.Index, started: event.Time}
+		d.tests = append(d.tests, state)
+		d.byName[event.Test] = state
+	case "test_progress":
+		state := d.byName[event.Test]
+		if state == nil || event.Progress == nil {
+			return
+		}
+		state.update(*event.Progress)
+		if decile := int(state.fraction * 10); decile > state.lastDecile && decile < 10 {
+			state.lastDecile = decile
+			d.plainf("[%d/%d] %s %3.0f%% %s", state.index, d.total, i18n.Label("test", state.name), state.fraction*100, state.detail())
+		}
+	case "test_end":
+		state := d.byName[event.Test]
+		if state == nil {
+			return
+		}
+		state.status = event.Status
+		state.ended = event.Time
+		state.fraction = 1
+		d.plainf("[%d/%d] %s: %s (%s)", state.index, d.total, i18n.Label("test", state.name), i18n.Label("status", event.Status), state.ended.Sub(state.started).Round(100*time.Millisecond))
+	}
+}
+
+// Write prints log output above the live area.
+func (d *Dashboard) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.live || d.closed {
+		return d.out.Write(p)
+	}
+	d.clear()
+	n, err := d.out.Write(p)
+	d.draw()
+	return n, err
+}
+
+// Close stops the animation and leaves the final state on screen; later
+// writes go straight to the output.
+func (d *Dashboard) Close() {
+	d.mu.Lock()
+	if !d.live || d.closed {
+		d.mu.Unlock()
+		return
+	}
+	d.closed = true
+	d.mu.Unlock()
+	close(d.stop)
+	<-d.stopped
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.clear()
+	d.draw()
+	d.drawn = 0
+	fmt.Fprint(d.out, "\x1b[?25h")
+}
+
+func (d *Dashboard) animate() {
+	defer close(d.stopped)
+	ticker := time.NewTicker(redrawInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			d.frame++
+			d.clear()
+			d.draw()
+			d.mu.Unlock()
+		}
+	}
+}
+
+func (d *Dashboard) plainf(format string, args ...any) {
+	if d.live {
+		return
+	}
+	fmt.Fprintf(d.out, format+"\n", args...)
+}
+
+// clear moves the cursor back over the live area and erases it.
+func (d *Dashboard) clear() {
+	if d.drawn > 0 {
+		fmt.Fprintf(d.out, "\x1b[%dF\x1b[J", d.drawn)
+	}
+	d.drawn = 0
+}
+
+func (d *Dashboard) draw() {
+	lines := d.lines()
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(fit(line, d.width-1))
+		b.WriteString("\n")
+	}
+	fmt.Fprint(d.out, b.String())
+	d.drawn = len(lines)
+}
+
+func (d *Dashboard) lines() []string {
+	done := 0
+	for _, state := range d.tests {
+		if !state.ended.IsZero() {
+			done++
+		}
+	}
+	lines := []string{fmt.Sprintf("conncheck  %d/%d  %s", done, d.total, clock(time.Since(d.started)))}
+	for _, state := range d.tests {
+		label := pad(i18n.Label("test", state.name), nameWidth)
+		if !state.ended.IsZero() {
+			lines = append(lines, fmt.Sprintf("  %s %s %s", label, d.paint(state.status, pad(i18n.Label("status", state.status), 8)), state.ended.Sub(state.started).Round(100*time.Millisecond)))
+			continue
+		}
+		elapsed := clock(time.Since(state.started))
+		lines = append(lines, fmt.Sprintf("%s %s %s %3.0f%%  %s", spinner[d.frame%len(spinner)], label, bar(state.fraction), state.fraction*100, elapsed))
+		for _, series := range state.targets {
+			lines = append(lines, fmt.Sprintf("    %s %s %s  %s", pad(series.target, nameWidth-2), spark(series.samples), rtt(series.last), i18n.T("dashboard.loss", series.lost, series.count)))
+		}
+		if state.server != "" {
+			lines = append(lines, "    "+state.detail())
+		}
+	}
+	return lines
+}
+
+func (d *Dashboard) paint(status, text string) string {
+	if !d.color {
+		return text
+	}
+	codes := map[string]string{"OK": "32", "WARN": "33", "FAIL": "31", "SKIPPED": "90"}
+	code, ok := codes[status]
+	if !ok {
+		return text
+	}
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+func (s *testState) update(progress model.Progress) {
+	if progress.Fraction > s.fraction {
+		s.fraction = progress.Fraction
+	}
+	if progress.DownBps > 0 || progress.UpBps > 0 {
+		s.server, s.downBps, s.upBps = progress.Target, progress.DownBps, progress.UpBps
+		return
+	}
+	if progress.Target == "" {
+		return
+	}
+	var series *rttSeries
+	for _, existing := range s.targets {
+		if existing.target == progress.Target {
+			series = existing
+		}
+	}
+	if series == nil {
+		series = &rttSeries{target: progress.Target}
+		s.targets = append(s.targets, series)
+	}
+	value := progress.RTTMs
+	series.count++
+	if progress.Lost {
+		value = math.NaN()
+		series.lost++
+	} else {
+		series.last = value
+	}
+	series.samples = append(series.samples, value)
+	if len(series.samples) > sparkWidth {
+		series.samples = series.samples[len(series.samples)-sparkWidth:]
+	}
+}
+
+// detail is the one-line measurement shown next to plain progress lines.
+func (s *testState) detail() string {
+	if s.server != "" {
+		return i18n.T("dashboard.throughput", s.server, s.downBps/1e6, s.upBps/1e6)
+	}
+	var parts []string
+	for _, series := range s.targets {
+		parts = append(parts, series.target+" "+rtt(series.last))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func bar(fraction float64) string {
+	filled := int(math.Round(fraction * barWidth))
+	filled = min(max(filled, 0), barWidth)
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", barWidth-filled) + "]"
+}
+
+// spark draws samples scaled between their minimum and maximum; lost
+// probes are drawn as x.
+func spark(samples []float64) string {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range samples {
+		if !math.IsNaN(v) {
+			low, high = math.Min(low, v), math.Max(high, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range samples {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune('x')
+		case high <= low:
+			b.WriteRune(sparkTicks[0])
+		default:
+			b.WriteRune(sparkTicks[int((v-low)/(high-low)*float64(len(sparkTicks)-1))])
+		}
+	}
+	return pad(b.String(), sparkWidth)
+}
+
+func rtt(ms float64) string {
+	if ms <= 0 {
+		return "   - ms"
+	}
+	return fmt.Sprintf("%4.0f ms", ms)
+}
+
+func clock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func pad(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
+
+// fit cuts line to width visible characters, skipping ANSI escapes, so a
+// wrapped line never throws off the cursor arithmetic of clear.
+func fit(line string, width int) string {
+	var b strings.Builder
+	visible := 0
+	escape := false
+	for _, r := range line {
+		switch {
+		case escape:
+			b.WriteRune(r)
+			escape = r != 'm'
+		case r == '\x1b':
+			b.WriteRune(r)
+			escape = true
+		case visible < width:
+			b.WriteRune(r)
+			visible++
+		}
+	}
+	if strings.Contains(line, "\x1b[") {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// terminalWidth reads COLUMNS, which most shells export, and falls back to
+// the classic 80 columns.
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 20 {
+		return columns
+	}
+	return 80
+}
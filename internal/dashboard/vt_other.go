@@ -0,0 +1,10 @@
+//go:build !windows
+
+package dashboard
+
+import "os"
+
+// enableVT is a no-op: Unix terminals handle ANSI escapes.
+func enableVT(f *os.File) bool {
+	return true
+}
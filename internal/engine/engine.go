@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"conncheck/internal/config"
+	"conncheck/internal/i18n"
 	"conncheck/internal/model"
 	"conncheck/internal/tests"
 )
@@ -15,9 +16,12 @@ type Logger interface {
 }
 
 type Engine struct {
-	Cfg    config.Config
-	Logger Logger
-	OutDir string
+	Cfg       config.Config
+	Selection Selection
+	Logger    Logger
+	OutDir    string
+	// Events, when set, receives progress events as the suite runs.
+	Events func(model.Event)
 }
 
 func (e *Engine) Run(ctx context.Context) (model.Result, error) {
@@ -29,39 +33,56 @@ func (e *Engine) Run(ctx context.Context) (model.Result, error) {
 		},
 	}
 
-	testList := []tests.Runner{
-		tests.NewPreflight(e.OutDir),
-		tests.NewLAN(e.OutDir),
-		tests.NewDualStack(e.OutDir),
-		tests.NewDNSBench(e.OutDir, e.Cfg),
-		tests.NewMTU(e.OutDir, e.Cfg),
-		tests.NewLatency(e.OutDir, e.Cfg),
-		tests.NewBufferbloat(e.OutDir, e.Cfg),
-		tests.NewSpeedtest(e.OutDir, e.Cfg),
-		tests.NewTraceroute(e.OutDir, e.Cfg),
-		tests.NewHTTPCheck(e.OutDir, e.Cfg),
+	// The link is read once, before any traffic: the interfaces watcher
+	// reports its duplex and speed against the plan, and the speedtest
+	// compares its throughput with it.
+	result.Environment = tests.CollectEnvironment(e.OutDir)
+	runners := e.runners(result.Environment.Link)
+	total := 0
+	for _, test := range runners {
+		if _, excluded := e.Exclusion(test.Name()); !excluded {
+			total++
+		}
+	}
+	e.emit(model.Event{Type: "run_start", Total: total})
+	for _, test := range runners {
+		if watcher, ok := test.(tests.Watcher); ok {
+			if _, excluded := e.Exclusion(test.Name()); !excluded {
+				watcher.Start(ctx)
+			}
+		}
 	}
 
-	for _, test := range testList {
+	index := 0
+	for _, test := range runners {
 		select {
 		case <-ctx.Done():
 			return result, ctx.Err()
 		default:
 		}
 
-		if !e.Cfg.Tests.IsEnabled(test.Name()) {
-			e.log("Skipping %s (disabled in config).", test.Name())
+		if excluded, ok := e.Exclusion(test.Name()); ok {
+			if excluded.Reason == "config" {
+				e.log("%s", i18n.T("engine.skipping", test.Name()))
+			} else {
+				e.log("%s", i18n.T("engine.excluded", test.Name(), excluded.Reason, excluded.Pattern))
+			}
+			result.Excluded = append(result.Excluded, excluded)
+			e.emit(model.Event{Type: "test_excluded", Test: test.Name(), Excluded: &excluded})
 			continue
 		}
 
-		e.log("Running %s...", test.Name())
-		res := test.Run(ctx)
+		index++
+		e.log("%s", i18n.T("engine.running", test.Name()))
+		e.emit(model.Event{Type: "test_start", Test: test.Name(), Index: index, Total: total})
+		res := test.Run(e.progressContext(ctx, test.Name(), index, total))
+		e.emit(model.Event{Type: "test_end", Test: test.Name(), Index: index, Total: total, Status: res.Status, Result: &res})
 		result.Tests = append(result.Tests, res)
 		result.Summary.StatusCounts[res.Status] = result.Summary.StatusCounts[res.Status] + 1
 		result.Findings = append(result.Findings, res.Findings...)
 	}
 
-	result.Environment = tests.CollectEnvironment()
+	result.Targets = catalogTargets(e.Cfg.Targets)
 	result.FinishedAt = time.Now()
 
 	if len(result.Tests) == 0 {
@@ -71,6 +92,72 @@ func (e *Engine) Run(ctx context.Context) (model.Result, error) {
 	return result, nil
 }
 
+// runners returns the suite in execution order. Watchers come last so
+// their observation spans the other tests. link is the state of the
+// interface carrying the default route, nil when unknown.
+func (e *Engine) runners(link *model.Link) []tests.Runner {
+	return []tests.Runner{
+		tests.NewPreflight(e.OutDir),
+		tests.NewLAN(e.OutDir, e.Cfg),
+		tests.NewWiFi(e.OutDir, e.Cfg),
+		tests.NewDualStack(e.OutDir),
+		tests.NewDNSBench(e.OutDir, e.Cfg),
+		tests.NewMTU(e.OutDir, e.Cfg),
+		tests.NewLatency(e.OutDir, e.Cfg),
+		tests.NewBufferbloat(e.OutDir, e.Cfg),
+		tests.NewSpeedtest(e.OutDir, e.Cfg, link),
+		tests.NewTraceroute(e.OutDir, e.Cfg),
+		tests.NewHTTPCheck(e.OutDir, e.Cfg),
+		tests.NewNeighbors(e.OutDir),
+		tests.NewInterfaces(e.OutDir, e.Cfg.Plan, link),
+	}
+}
+
+// TestNames lists the tests of the suite in execution order.
+func TestNames() []string {
+	var names []string
+	for _, test := range (&Engine{}).runners(nil) {
+		names = append(names, test.Name())
+	}
+	return names
+}
+
+// catalogTargets copies the catalog into the result, with the URL http_check
+// fetches for each target, so reports can map metrics back to regions.
+func catalogTargets(targets config.TargetsConfig) []model.Target {
+	var out []model.Target
+	for _, target := range targets.Catalog {
+		out = append(out, model.Target{
+			Name:    target.Name,
+			Address: target.Address,
+			URL:     targets.ResolveEndpoints([]string{target.Name})[0].Address,
+			Region:  target.Region,
+			Stack:   target.Stack,
+			Tags:    target.Tags,
+		})
+	}
+	return out
+}
+
+// progressContext forwards the progress a test reports as test_progress
+// events.
+func (e *Engine) progressContext(ctx context.Context, name string, index, total int) context.Context {
+	if e.Events == nil {
+		return ctx
+	}
+	return tests.WithProgress(ctx, func(progress model.Progress) {
+		e.emit(model.Event{Type: "test_progress", Test: name, Index: index, Total: total, Progress: &progress})
+	})
+}
+
+func (e *Engine) emit(event model.Event) {
+	if e.Events == nil {
+		return
+	}
+	event.Time = time.Now()
+	e.Events(event)
+}
+
 func (e *Engine) log(format string, args ...any) {
 	if e.Logger == nil {
 		return
@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"conncheck/internal/model"
+)
+
+// categories groups the tests so a selection can name a family of them.
+var categories = map[string]string{
+	"preflight":     "local",
+	"lan_health":    "local",
+	"dualstack":     "local",
+	"neighbors":     "local",
+	"interfaces":    "local",
+	"wifi":          "local",
+	"dns_benchmark": "dns",
+	"mtu_pmtu":      "path",
+	"traceroute":    "path",
+	"latency":       "quality",
+	"bufferbloat":   "quality",
+	"speedtest":     "speed",
+	"http_check":    "web",
+}
+
+// Category returns the category of a test.
+func Category(test string) string {
+	return categories[test]
+}
+
+// Categories lists the test categories in alphabetical order.
+func Categories() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, category := range categories {
+		if !seen[category] {
+			seen[category] = true
+			out = append(out, category)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Selection narrows the suite on top of the configuration. Each term is a
+// test name, a category or a glob matched against both, e.g. "speed*".
+// Empty Only keeps every test; Skip wins over Only.
+type Selection struct {
+	Only []string
+	Skip []string
+}
+
+// ParseSelection splits comma-separated -only and -skip values and rejects
+// terms that match no test, so a typo does not silently run everything.
+func ParseSelection(only, skip []string) (Selection, error) {
+	var sel Selection
+	var err error
+	if sel.Only, err = parseTerms("only", only); err != nil {
+		return Selection{}, err
+	}
+	if sel.Skip, err = parseTerms("skip", skip); err != nil {
+		return Selection{}, err
+	}
+	return sel, nil
+}
+
+func parseTerms(flag string, values []string) ([]string, error) {
+	var terms []string
+	for _, value := range values {
+		for _, term := range strings.Split(value, ",") {
+			term = strings.TrimSpace(term)
+			if term == "" {
+				continue
+			}
+			if _, err := path.Match(term, ""); err != nil {
+				return nil, fmt.Errorf("-%s %q: %w", flag, term, err)
+			}
+			if !matchesAny(term) {
+				return nil, fmt.Errorf("-%s %q matches no test or category (tests: %s; categories: %s)",
+					flag, term, strings.Join(TestNames(), ", "), strings.Join(Categories(), ", "))
+			}
+			terms = append(terms, term)
+		}
+	}
+	return terms, nil
+}
+
+func matchesAny(term string) bool {
+	for _, name := range TestNames() {
+		if matchTerm(term, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTerm reports whether term selects test by name, category or glob.
+func matchTerm(term, test string) bool {
+	if term == test || term == Category(test) {
+		return true
+	}
+	if ok, _ := path.Match(term, test); ok {
+		return true
+	}
+	ok, _ := path.Match(term, Category(test))
+	return ok
+}
+
+// Exclude reports whether the selection leaves test out, and why.
+func (s Selection) Exclude(test string) (model.Exclusion, bool) {
+	for _, term := range s.Skip {
+		if matchTerm(term, test) {
+			return model.Exclusion{Test: test, Reason: "skip", Pattern: term}, true
+		}
+	}
+	if len(s.Only) == 0 {
+		return model.Exclusion{}, false
+	}
+	for _, term := range s.Only {
+		if matchTerm(term, test) {
+			return model.Exclusion{}, false
+		}
+	}
+	return model.Exclusion{Test: test, Reason: "only", Pattern: strings.Join(s.Only, ",")}, true
+}
+
+// Exclusion combines the configuration and the selection: a test runs only
+// when the configuration enables it and the selection keeps it.
+func (e *Engine) Exclusion(test string) (model.Exclusion, bool) {
+	if !e.Cfg.Tests.IsEnabled(test) {
+		return model.Exclusion{Test: test, Reason: "config"}, true
+	}
+	return e.Selection.Exclude(test)
+}
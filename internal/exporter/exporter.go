@@ -0,0 +1,123 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"conncheck/internal/model"
+	"conncheck/internal/report"
+)
+
+// ErrNoRun is returned by a Source that has no completed run yet.
+var ErrNoRun = errors.New("no completed run available")
+
+// Source provides the run whose metrics are exposed.
+type Source interface {
+	Latest() (model.Result, error)
+}
+
+// DirSource serves the newest results.json found in Root or in its direct
+// subdirectories (the default outputs/<timestamp> layout).
+type DirSource struct {
+	Root string
+}
+
+func (d DirSource) Latest() (model.Result, error) {
+	candidates, err := filepath.Glob(filepath.Join(d.Root, "*", "results.json"))
+	if err != nil {
+		return model.Result{}, err
+	}
+	candidates = append(candidates, filepath.Join(d.Root, "results.json"))
+
+	var newest string
+	var newestInfo os.FileInfo
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil {
+			continue
+		}
+		if newestInfo == nil || info.ModTime().After(newestInfo.ModTime()) {
+			newest, newestInfo = candidate, info
+		}
+	}
+	if newest == "" {
+		return model.Result{}, ErrNoRun
+	}
+	data, err := os.ReadFile(newest)
+	if err != nil {
+		return model.Result{}, err
+	}
+	var result model.Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return model.Result{}, err
+	}
+	return result, nil
+}
+
+// Store holds the most recent run in memory; monitor mode updates it after
+// every cycle.
+type Store struct {
+	mu     sync.RWMutex
+	result model.Result
+	ok     bool
+}
+
+func (s *Store) Set(result model.Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result = result
+	s.ok = true
+}
+
+func (s *Store) Latest() (model.Result, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.ok {
+		return model.Result{}, ErrNoRun
+	}
+	return s.result, nil
+}
+
+// Handler serves /metrics from source. Scrapers that ask for OpenMetrics get
+// that content type; everything else gets the Prometheus text format, which
+// parses the same body.
+func Handler(source Source) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		result, err := source.Latest()
+		if errors.Is(err, ErrNoRun) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var buf bytes.Buffer
+		if err := report.FormatOpenMetrics(&buf, result); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		contentType := report.PrometheusContentType
+		if strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+			contentType = report.OpenMetricsContentType
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(buf.Bytes())
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(`<html><body><a href="/metrics">/metrics</a></body></html>`))
+	})
+	return mux
+}
@@ -0,0 +1,178 @@
+// Package gui is the minimal status window shown when conncheck is started
+// by double-clicking: the current step, a progress bar, the last log lines
+// and buttons to open the report, open the output folder and copy the
+// summary. The desktop driver needs cgo, so only builds with the "gui" tag
+// create a window; this package itself only uses widgets and runs under
+// Fyne's headless test driver.
+package gui
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
+)
+
+// MaxLogLines is how many log lines the window keeps.
+const MaxLogLines = 50
+
+// Window is the status window. Handle and Write may be called from any
+// goroutine; widget updates are handed to the Fyne main loop.
+type Window struct {
+	app fyne.App
+	win fyne.Window
+
+	Step        *widget.Label
+	Progress    *widget.ProgressBar
+	Log         *widget.List
+	OpenReport  *widget.Button
+	OpenFolder  *widget.Button
+	CopySummary *widget.Button
+
+	mu      sync.Mutex
+	lines   []string
+	partial string
+	outDir  string
+	summary string
+}
+
+// New builds the window on app. Buttons stay disabled until Finish.
+func New(app fyne.App) *Window {
+	w := &Window{app: app}
+	w.win = app.NewWindow(i18n.T("gui.title"))
+	w.Step = widget.NewLabel(i18n.T("gui.starting"))
+	w.Progress = widget.NewProgressBar()
+	w.Log = widget.NewList(w.lineCount, func() fyne.CanvasObject {
+		return widget.NewLabel("")
+	}, func(id widget.ListItemID, item fyne.CanvasObject) {
+		item.(*widget.Label).SetText(w.line(id))
+	})
+	w.OpenReport = widget.NewButton(i18n.T("gui.open_report"), func() {
+		w.open(filepath.Join(w.outDir, "report.html"))
+	})
+	w.OpenFolder = widget.NewButton(i18n.T("gui.open_folder"), func() {
+		w.open(w.outDir)
+	})
+	w.CopySummary = widget.NewButton(i18n.T("gui.copy_summary"), func() {
+		w.app.Clipboard().SetContent(w.summary)
+	})
+	for _, button := range []*widget.Button{w.OpenReport, w.OpenFolder, w.CopySummary} {
+		button.Disable()
+	}
+
+	top := container.NewVBox(w.Step, w.Progress)
+	buttons := container.NewHBox(w.OpenReport, w.OpenFolder, w.CopySummary)
+	w.win.SetContent(container.NewBorder(top, buttons, nil, nil, w.Log))
+	w.win.Resize(fyne.NewSize(640, 420))
+	return w
+}
+
+// Window returns the Fyne window, e.g. to show it.
+func (w *Window) Window() fyne.Window {
+	return w.win
+}
+
+// Handle updates the step text and progress bar from an engine event.
+func (w *Window) Handle(event model.Event) {
+	var step string
+	progress := -1.0
+	switch event.Type {
+	case "test_start":
+		step = i18n.T("gui.step", event.Index, event.Total, i18n.Label("test", event.Test))
+		progress = float64(event.Index-1) / float64(max(event.Total, 1))
+	case "test_progress":
+		if event.Progress != nil {
+			progress = (float64(event.Index-1) + event.Progress.Fraction) / float64(max(event.Total, 1))
+		}
+	case "test_end":
+		progress = float64(event.Index) / float64(max(event.Total, 1))
+	default:
+		return
+	}
+	fyne.Do(func() {
+		if step != "" {
+			w.Step.SetText(step)
+		}
+		if progress >= 0 {
+			w.Progress.SetValue(progress)
+		}
+	})
+}
+
+// Write appends log output, keeping the last MaxLogLines lines.
+func (w *Window) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	text := w.partial + string(p)
+	parts := strings.Split(text, "\n")
+	w.partial = parts[len(parts)-1]
+	w.lines = append(w.lines, parts[:len(parts)-1]...)
+	if len(w.lines) > MaxLogLines {
+		w.lines = w.lines[len(w.lines)-MaxLogLines:]
+	}
+	w.mu.Unlock()
+	fyne.Do(func() {
+		w.Log.Refresh()
+		w.Log.ScrollToBottom()
+	})
+	return len(p), nil
+}
+
+// Finish shows the outcome and enables the buttons. With err set only the
+// error is shown, since there is no report to open.
+func (w *Window) Finish(outDir, summary string, err error) {
+	w.mu.Lock()
+	w.outDir, w.summary = outDir, summary
+	w.mu.Unlock()
+	fyne.Do(func() {
+		if err != nil {
+			w.Step.SetText(i18n.T("gui.failed", err))
+			return
+		}
+		w.Step.SetText(i18n.T("gui.done", summary))
+		w.Progress.SetValue(1)
+		for _, button := range []*widget.Button{w.OpenReport, w.OpenFolder, w.CopySummary} {
+			button.Enable()
+		}
+	})
+}
+
+// LogLines returns a copy of the lines the log list shows.
+func (w *Window) LogLines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.lines...)
+}
+
+func (w *Window) lineCount() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.lines)
+}
+
+func (w *Window) line(id widget.ListItemID) string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if id < 0 || id >= len(w.lines) {
+		return ""
+	}
+	return w.lines[id]
+}
+
+func (w *Window) open(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	target, err := url.Parse(storage.NewFileURI(path).String())
+	if err != nil {
+		return
+	}
+	_ = w.app.OpenURL(target)
+}
@@ -0,0 +1,194 @@
+//go:build gui
+
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
+)
+
+// recordingApp is the headless test app with the last opened URL recorded.
+type recordingApp struct {
+	fyne.App
+	opened *url.URL
+}
+
+func (a *recordingApp) OpenURL(target *url.URL) error {
+	a.opened = target
+	return nil
+}
+
+func newTestWindow(t *testing.T) (*Window, *recordingApp) {
+	t.Helper()
+	if err := i18n.SetLanguage(i18n.English); err != nil {
+		t.Fatal(err)
+	}
+	app := &recordingApp{App: test.NewTempApp(t)}
+	return New(app), app
+}
+
+func TestHandleStepAndProgress(t *testing.T) {
+	w, _ := newTestWindow(t)
+
+	tests := []struct {
+		name         string
+		event        model.Event
+		wantStep     string
+		wantProgress float64
+	}{
+		{
+			name:         "first test starts",
+			event:        model.Event{Type: "test_start", Test: "preflight", Index: 1, Total: 4},
+			wantStep:     i18n.T("gui.step", 1, 4, i18n.Label("test", "preflight")),
+			wantProgress: 0,
+		},
+		{
+			name:         "first test half done",
+			event:        model.Event{Type: "test_progress", Test: "preflight", Index: 1, Total: 4, Progress: &model.Progress{Fraction: 0.5}},
+			wantStep:     i18n.T("gui.step", 1, 4, i18n.Label("test", "preflight")),
+			wantProgress: 0.125,
+		},
+		{
+			name:         "progress without a measurement keeps the bar",
+			event:        model.Event{Type: "test_progress", Test: "preflight", Index: 1, Total: 4},
+			wantStep:     i18n.T("gui.step", 1, 4, i18n.Label("test", "preflight")),
+			wantProgress: 0.125,
+		},
+		{
+			name:         "first test ends",
+			event:        model.Event{Type: "test_end", Test: "preflight", Index: 1, Total: 4, Status: "OK"},
+			wantStep:     i18n.T("gui.step", 1, 4, i18n.Label("test", "preflight")),
+			wantProgress: 0.25,
+		},
+		{
+			name:         "third test starts",
+			event:        model.Event{Type: "test_start", Test: "latency", Index: 3, Total: 4},
+			wantStep:     i18n.T("gui.step", 3, 4, i18n.Label("test", "latency")),
+			wantProgress: 0.5,
+		},
+		{
+			name:         "other events are ignored",
+			event:        model.Event{Type: "test_excluded", Test: "speedtest"},
+			wantStep:     i18n.T("gui.step", 3, 4, i18n.Label("test", "latency")),
+			wantProgress: 0.5,
+		},
+		{
+			name:         "last test ends",
+			event:        model.Event{Type: "test_end", Test: "http_check", Index: 4, Total: 4, Status: "WARN"},
+			wantStep:     i18n.T("gui.step", 3, 4, i18n.Label("test", "latency")),
+			wantProgress: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w.Handle(tt.event)
+			if got := w.Step.Text; got != tt.wantStep {
+				t.Errorf("step = %q, want %q", got, tt.wantStep)
+			}
+			if got := w.Progress.Value; got != tt.wantProgress {
+				t.Errorf("progress = %v, want %v", got, tt.wantProgress)
+			}
+		})
+	}
+}
+
+func TestLogKeepsLastLines(t *testing.T) {
+	w, _ := newTestWindow(t)
+
+	for i := 1; i <= MaxLogLines+20; i++ {
+		fmt.Fprintf(w, "line %d\n", i)
+	}
+	// A line written in pieces shows once it is complete.
+	fmt.Fprint(w, "partial ")
+	if got := w.LogLines(); got[len(got)-1] != fmt.Sprintf("line %d", MaxLogLines+20) {
+		t.Errorf("last line = %q before the newline", got[len(got)-1])
+	}
+	fmt.Fprint(w, "line\nfirst\nsecond\n")
+
+	lines := w.LogLines()
+	if len(lines) != MaxLogLines {
+		t.Fatalf("kept %d lines, want %d", len(lines), MaxLogLines)
+	}
+	want := []string{"partial line", "first", "second"}
+	if tail := lines[len(lines)-3:]; strings.Join(tail, "|") != strings.Join(want, "|") {
+		t.Errorf("last lines = %q, want %q", tail, want)
+	}
+	// 73 complete lines were written, so the first 23 were dropped.
+	if first := "line 24"; lines[0] != first {
+		t.Errorf("first kept line = %q, want %q", lines[0], first)
+	}
+	if got := w.Log.Length(); got != MaxLogLines {
+		t.Errorf("list shows %d rows, want %d", got, MaxLogLines)
+	}
+}
+
+func TestFinishEnablesButtons(t *testing.T) {
+	w, app := newTestWindow(t)
+	buttons := []*widget.Button{w.OpenReport, w.OpenFolder, w.CopySummary}
+	for _, button := range buttons {
+		if !button.Disabled() {
+			t.Errorf("%q enabled before the run ends", button.Text)
+		}
+	}
+
+	outDir := t.TempDir()
+	summary := "Tests: 4, OK: 3, WARN: 1, FAIL: 0, SKIPPED: 0"
+	w.Finish(outDir, summary, nil)
+
+	for _, button := range buttons {
+		if button.Disabled() {
+			t.Errorf("%q disabled after the run", button.Text)
+		}
+	}
+	if got, want := w.Step.Text, i18n.T("gui.done", summary); got != want {
+		t.Errorf("step = %q, want %q", got, want)
+	}
+	if w.Progress.Value != 1 {
+		t.Errorf("progress = %v, want 1", w.Progress.Value)
+	}
+
+	for _, tt := range []struct {
+		button *widget.Button
+		want   string
+	}{
+		{button: w.OpenReport, want: storage.NewFileURI(filepath.Join(outDir, "report.html")).String()},
+		{button: w.OpenFolder, want: storage.NewFileURI(outDir).String()},
+	} {
+		app.opened = nil
+		test.Tap(tt.button)
+		if app.opened == nil || app.opened.String() != tt.want {
+			t.Errorf("%q opened %v, want %s", tt.button.Text, app.opened, tt.want)
+		}
+	}
+	test.Tap(w.CopySummary)
+	if got := app.Clipboard().Content(); got != summary {
+		t.Errorf("clipboard = %q, want %q", got, summary)
+	}
+}
+
+func TestFinishWithErrorKeepsButtonsDisabled(t *testing.T) {
+	w, _ := newTestWindow(t)
+	err := errors.New("run failed: no route to host")
+	w.Finish(t.TempDir(), "", err)
+
+	if got, want := w.Step.Text, i18n.T("gui.failed", err); got != want {
+		t.Errorf("step = %q, want %q", got, want)
+	}
+	for _, button := range []*widget.Button{w.OpenReport, w.OpenFolder, w.CopySummary} {
+		if !button.Disabled() {
+			t.Errorf("%q enabled after a failed run", button.Text)
+		}
+	}
+}
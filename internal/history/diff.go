@@ -0,0 +1,150 @@
+package history
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"conncheck/internal/model"
+)
+
+// Comparison is what changed between two runs.
+type Comparison struct {
+	Statuses []StatusChange
+	Added    []model.Finding // findings only in the new run
+	Resolved []model.Finding // findings only in the old run
+	Metrics  []MetricChange
+}
+
+// StatusChange is a test whose status differs; an empty side means the test
+// did not run.
+type StatusChange struct {
+	Test     string
+	Old, New string
+}
+
+// MetricChange is a numeric metric present in at least one run.
+type MetricChange struct {
+	Test     string
+	Key      string
+	Old, New float64
+	HasOld   bool
+	HasNew   bool
+}
+
+// DeltaPct is the relative change, or +Inf when the old value is zero.
+func (m MetricChange) DeltaPct() float64 {
+	if m.Old == 0 {
+		if m.New == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return (m.New - m.Old) / math.Abs(m.Old) * 100
+}
+
+// Compare lists the differences from old to new. Numeric metrics are kept
+// when they appear in only one run or moved by at least minChangePct
+// percent; minChangePct <= 0 keeps every numeric metric. Findings are
+// matched by id, since their detail carries the measured numbers.
+func Compare(old, new model.Result, minChangePct float64) Comparison {
+	var c Comparison
+
+	oldTests := testsByName(old)
+	newTests := testsByName(new)
+	for _, name := range unionKeys(oldTests, newTests) {
+		oldStatus, newStatus := statusOf(oldTests[name]), statusOf(newTests[name])
+		if oldStatus != newStatus {
+			c.Statuses = append(c.Statuses, StatusChange{Test: name, Old: oldStatus, New: newStatus})
+		}
+		c.Metrics = append(c.Metrics, compareMetrics(name, oldTests[name], newTests[name], minChangePct)...)
+	}
+
+	oldFindings := findingKeys(old.Findings)
+	newFindings := findingKeys(new.Findings)
+	for _, finding := range new.Findings {
+		if !oldFindings[findingKey(finding)] {
+			c.Added = append(c.Added, finding)
+		}
+	}
+	for _, finding := range old.Findings {
+		if !newFindings[findingKey(finding)] {
+			c.Resolved = append(c.Resolved, finding)
+		}
+	}
+	return c
+}
+
+func compareMetrics(test string, old, new *model.TestResult, minChangePct float64) []MetricChange {
+	oldMetrics, newMetrics := numericMetrics(old), numericMetrics(new)
+	var changes []MetricChange
+	for _, key := range unionKeys(oldMetrics, newMetrics) {
+		oldValue, hasOld := oldMetrics[key]
+		newValue, hasNew := newMetrics[key]
+		change := MetricChange{Test: test, Key: key, Old: oldValue, New: newValue, HasOld: hasOld, HasNew: hasNew}
+		if hasOld && hasNew && minChangePct > 0 && math.Abs(change.DeltaPct()) < minChangePct {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func numericMetrics(test *model.TestResult) map[string]float64 {
+	values := map[string]float64{}
+	if test == nil {
+		return values
+	}
+	for key, raw := range test.Metrics {
+		if value, err := strconv.ParseFloat(raw, 64); err == nil && !math.IsNaN(value) && !math.IsInf(value, 0) {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+func testsByName(result model.Result) map[string]*model.TestResult {
+	tests := map[string]*model.TestResult{}
+	for i := range result.Tests {
+		tests[result.Tests[i].Name] = &result.Tests[i]
+	}
+	return tests
+}
+
+func statusOf(test *model.TestResult) string {
+	if test == nil {
+		return ""
+	}
+	return test.Status
+}
+
+func findingKey(finding model.Finding) string {
+	if finding.ID != "" {
+		return finding.ID
+	}
+	return finding.Title
+}
+
+func findingKeys(findings []model.Finding) map[string]bool {
+	keys := map[string]bool{}
+	for _, finding := range findings {
+		keys[findingKey(finding)] = true
+	}
+	return keys
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := map[string]bool{}
+	for key := range a {
+		seen[key] = true
+	}
+	for key := range b {
+		seen[key] = true
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
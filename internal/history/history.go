@@ -0,0 +1,100 @@
+// Package history reads previous runs from the outputs folder.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"conncheck/internal/model"
+)
+
+// ResultsFile is the run file every other output can be rebuilt from.
+const ResultsFile = "results.json"
+
+// ErrNoRuns is returned when the outputs folder holds no completed run.
+var ErrNoRuns = errors.New("no completed runs found")
+
+// NewRunDir returns a fresh run folder under root, named after the current
+// time so List sorts runs by start.
+func NewRunDir(root string) string {
+	return filepath.Join(root, time.Now().Format("20060102-150405"))
+}
+
+// Run is one run folder.
+type Run struct {
+	Name   string // folder name, normally the start timestamp
+	Dir    string
+	Result model.Result
+}
+
+// List returns the runs directly under root, newest first. Folders without a
+// readable results.json (runs in progress or aborted) are skipped.
+func List(root string) ([]Run, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var runs []Run
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		result, err := Load(dir)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, Run{Name: entry.Name(), Dir: dir, Result: result})
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		if !runs[i].Result.StartedAt.Equal(runs[j].Result.StartedAt) {
+			return runs[i].Result.StartedAt.After(runs[j].Result.StartedAt)
+		}
+		return runs[i].Name > runs[j].Name
+	})
+	return runs, nil
+}
+
+// Latest returns the newest run under root.
+func Latest(root string) (Run, error) {
+	runs, err := List(root)
+	if err != nil {
+		return Run{}, err
+	}
+	if len(runs) == 0 {
+		return Run{}, fmt.Errorf("%s: %w", root, ErrNoRuns)
+	}
+	return runs[0], nil
+}
+
+// Load reads a run given its folder or its results.json.
+func Load(path string) (model.Result, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, ResultsFile)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return model.Result{}, err
+	}
+	var result model.Result
+	if err := json.Unmarshal(data, &result); err != nil {
+		return model.Result{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return result, nil
+}
+
+// RunDir returns the folder of a run given its folder or its results.json.
+func RunDir(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return path
+	}
+	return filepath.Dir(path)
+}
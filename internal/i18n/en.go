@@ -0,0 +1,455 @@
+package i18n
+
+var english = map[string]string{
+	// CLI and engine
+	"cli.outputs":                "Outputs generated:",
+	"cli.summary":                "Summary: %s",
+	"cli.done":                   "Done.",
+	"cli.exporter.listening":     "Serving metrics on http://%s/metrics",
+	"cli.exporter.source_dir":    "Exposing the latest run found in %s",
+	"cli.exporter.monitor":       "Monitor mode: running the suite every %s",
+	"cli.exporter.cycle_failed":  "Monitor cycle failed: %v",
+	"cli.timeseries.pushed":      "Pushed %d time-series points to %s",
+	"cli.timeseries.push_failed": "Time-series push failed (files in the output folder are complete): %v",
+	"cli.config_defaults":        "No conncheck.yaml found; using built-in defaults.",
+	"cli.config_loaded":          "Using configuration %s",
+	"cli.validate.ok":            "%s: configuration is valid.",
+	"cli.validate.issues":        "%d issue(s) found.",
+	"cli.yes":                    "yes",
+	"cli.no":                     "no",
+	"cli.init.exists":            "%s already exists; use -force to overwrite it.",
+	"cli.init.detecting":         "Detecting network settings...",
+	"cli.init.detected":          "Gateway: %s | Resolvers: %s | IPv6: %s",
+	"cli.init.prompt.region":     "Region (%s)",
+	"cli.init.prompt.download":   "Subscribed download speed in Mbps (0 if unknown)",
+	"cli.init.prompt.upload":     "Subscribed upload speed in Mbps (0 if unknown)",
+	"cli.init.invalid_number":    "%q is not a valid number",
+	"cli.init.written":           "Wrote %s. Review it, then run conncheck.",
+	"setup.yaml.header":          "conncheck.yaml generated by \"conncheck init\".\nAnything left out falls back to the built-in defaults; run\n\"conncheck validate\" after editing.",
+	"setup.yaml.detected":        "Detected on this machine:",
+	"setup.yaml.gateway":         "  gateway:   %s (lan_health and mtu_pmtu use it automatically)",
+	"setup.yaml.resolvers":       "  resolvers: %s",
+	"setup.yaml.ipv6":            "  IPv6:      %s",
+	"setup.yaml.not_found":       "not found",
+	"setup.yaml.none_found":      "none found",
+	"setup.yaml.available":       "available",
+	"setup.yaml.not_available":   "not available",
+	"setup.yaml.language":        "Language for findings, logs and report.html: en | it",
+	"setup.yaml.plan":            "Subscribed line speed; a wired link negotiated below it is flagged.",
+	"setup.yaml.dns_servers":     "System resolvers first, then public ones for comparison.",
+	"setup.yaml.speedtest":       "Speedtest server ids. With every list empty the Speedtest CLI picks the\nnearest server; add ids listed by \"speedtest -L\" to pin servers.",
+	"setup.yaml.bufferbloat":     "The default 100 MB download. Point download_url at a larger file\nfor a longer load on fast lines.",
+	"cli.remote.verified":        "Remote config %s: signature verified, cached copy updated.",
+	"cli.remote.cached":          "Remote config %s unreachable (%v); using the cached copy from %s.",
+	"cli.remote.keygen":          "Private key written to %s. Public key to embed with -ldflags \"-X conncheck/internal/remoteconfig.PublicKey=...\":\n%s",
+	"cli.remote.signed":          "Signature written to %s.",
+	"cli.diff.header":            "Comparing %s -> %s",
+	"cli.diff.none":              "No differences.",
+	"cli.diff.statuses":          "Test status changes:",
+	"cli.diff.added":             "New findings:",
+	"cli.diff.resolved":          "Resolved findings:",
+	"cli.diff.metrics":           "Metric changes (at least %.0f%%):",
+	"cli.diff.not_run":           "not run",
+	"cli.history.none":           "No completed runs in %s.",
+	"cli.history.header":         "RUN\tSTATUS\tOK/WARN/FAIL/SKIP\tDOWN Mbps\tUP Mbps\tPING ms",
+	"cli.list_tests.header":      "TEST\tNAME\tCATEGORY\tRUNS",
+	"cli.serve.listening":        "Serving the runs in %[2]s on http://%[1]s/",
+	"cli.serve.not_loopback":     "%s is reachable from other machines; use -allow-remote to listen on it anyway",
+	"dashboard.loss":             "loss %d/%d",
+	"dashboard.throughput":       "%s: down %.1f Mbps, up %.1f Mbps",
+	"gui.title":                  "conncheck",
+	"gui.starting":               "Starting...",
+	"gui.step":                   "Step %d of %d: %s...",
+	"gui.done":                   "Done. %s",
+	"gui.failed":                 "The run could not complete: %v",
+	"gui.open_report":            "Open report",
+	"gui.open_folder":            "Open output folder",
+	"gui.copy_summary":           "Copy summary",
+	"cli.serve.run_started":      "Run started from the web app: %s",
+	"engine.skipping":            "Skipping %s (disabled in config).",
+	"engine.excluded":            "Skipping %s (excluded by -%s %s).",
+	"engine.running":             "Running %s...",
+
+	// Test display names
+	"test.preflight":     "Preflight",
+	"test.lan_health":    "LAN health",
+	"test.dualstack":     "Dual-stack IPv4/IPv6",
+	"test.dns_benchmark": "DNS benchmark",
+	"test.mtu_pmtu":      "MTU / PMTU",
+	"test.latency":       "Latency",
+	"test.bufferbloat":   "Bufferbloat",
+	"test.speedtest":     "Speedtest",
+	"test.traceroute":    "Traceroute",
+	"test.http_check":    "HTTP timing",
+	"test.neighbors":     "Neighbour table",
+	"test.interfaces":    "Interface counters",
+	"test.wifi":          "Wi-Fi link",
+
+	// Status labels
+	"status.OK":      "OK",
+	"status.WARN":    "Warning",
+	"status.FAIL":    "Failed",
+	"status.SKIPPED": "Skipped",
+
+	// LAN findings
+	"lan.gateway_detection_failed.title":  "Gateway detection failed",
+	"lan.gateway_detection_failed.detail": "%s",
+	"lan.gateway_not_found.title":         "Gateway not found",
+	"lan.gateway_not_found.detail":        "Unable to locate default gateway from routing table.",
+	"lan.gateway_unreachable.title":       "Gateway did not answer",
+	"lan.gateway_unreachable.detail":      "The gateway %s did not answer any of %d echo requests; it may drop ICMP.",
+	"lan.bottleneck.title":                "LAN/Wi-Fi is the likely bottleneck",
+	"lan.bottleneck.detail":               "The link to the gateway %s is already degraded: %s. Problems further out may start on the local network.",
+	"lan.loss_burst.title":                "Burst of lost gateway replies",
+	"lan.loss_burst.detail":               "%d consecutive echo requests to the gateway were lost (about %.1f s without replies), typical of Wi-Fi interference or roaming.",
+	"lan.reason.loss":                     "loss %.1f%% (threshold %d%%)",
+	"lan.reason.p95":                      "p95 RTT %.1f ms (threshold %d ms)",
+	"lan.reason.jitter":                   "jitter %.1f ms (threshold %d ms)",
+	"lan.icmp_rate_limited.title":         "Gateway rate-limits ping",
+	"lan.icmp_rate_limited.detail":        "The gateway %s dropped %.0f%% of pings but %s probes lost only %.0f%%, so the ping loss is ICMP rate limiting, not real loss. The gateway figures come from those probes.",
+	"lan.icmp_blocked.title":              "Gateway does not answer ping",
+	"lan.icmp_blocked.detail":             "The gateway %s dropped %.0f%% of pings but answered %s probes (%.0f%% lost), so it filters ICMP. The gateway figures come from those probes.",
+	"lan.no_link_timing.title":            "Gateway has no link-layer timing",
+	"lan.no_link_timing.detail":           "The gateway %s is reached over IPv6, and there is no NDP probe to time it at the link layer, so only a TCP connect can stand in for the lost pings.",
+	"lan.gateway_link_only.title":         "Gateway present but not measurable",
+	"lan.gateway_link_only.detail":        "The gateway %s answers neither ping, ARP nor TCP on ports 80/443/53, but the neighbour table lists it at %s (%s), so it is on the link. Its latency could not be measured.",
+	"lan.method.icmp":                     "ICMP echo",
+	"lan.method.tcp":                      "TCP connect",
+	"lan.method.arp":                      "ARP",
+	"lan.method.neighbor":                 "neighbour table",
+
+	// Neighbour table findings
+	"neighbors.table_failed.title":         "Neighbour table unavailable",
+	"neighbors.table_failed.detail":        "The ARP/NDP neighbour table could not be read (ip neigh / arp -a).",
+	"neighbors.gateway_mac_changed.title":  "Gateway MAC address changed",
+	"neighbors.gateway_mac_changed.detail": "The gateway %s answered from more than one MAC during the run (%s). Another device may be answering for the gateway (ARP spoofing or a rogue router), unless a router failover happened.",
+	"neighbors.ip_multiple_macs.title":     "Address used by several devices",
+	"neighbors.ip_multiple_macs.detail":    "%s was seen at several MACs during the run (%s): two devices probably share this IP address.",
+	"neighbors.duplicate_address.title":    "Duplicate of our own address",
+	"neighbors.duplicate_address.detail":   "Another device answers for our address %s (%s). Both hosts will see intermittent drops; check static addresses and the DHCP server.",
+
+	// Interface counter findings
+	"interfaces.unavailable.title":     "Interface counters unavailable",
+	"interfaces.unavailable.detail":    "Interface counters could not be read at both the start and the end of the run on %s.",
+	"interfaces.no_active.title":       "No active interface",
+	"interfaces.no_active.detail":      "No interface carried the default route or moved traffic during the run.",
+	"interfaces.machine_wide.title":    "Counters cover every adapter",
+	"interfaces.machine_wide.detail":   "Per-adapter counters were not available, so these figures are the machine-wide totals of netstat -e, summed over every adapter rather than those of the active interface.",
+	"interfaces.counters_reset.title":  "Interface counters reset",
+	"interfaces.counters_reset.detail": "The counters of %s went backwards during the run: the driver reset them, usually because the link dropped and came back.",
+	"interfaces.errors.title":          "Interface errors during the run",
+	"interfaces.errors.detail":         "%s counted errors while the tests ran (%s), %.3f%% of %d packets. Check the cable and switch port, or the Wi-Fi signal and driver.",
+	"interfaces.drops.title":           "Interface drops during the run",
+	"interfaces.drops.detail":          "%s discarded packets while the tests ran (%s). Drops can be harmless (unknown protocols, VLAN tags) but also a sign of full buffers.",
+
+	// Link findings
+	"link.half_duplex.title":       "Half-duplex link",
+	"link.half_duplex.detail":      "%s negotiated half duplex at %.0f Mbps: sending and receiving take turns and collisions cut throughput. Usually a duplex mismatch with a port forced to a fixed speed, or a faulty cable.",
+	"link.autoneg_off.title":       "Autonegotiation disabled",
+	"link.autoneg_off.detail":      "%s has a forced speed and duplex. If the switch or router port autonegotiates, the two ends can end up in a duplex mismatch.",
+	"link.below_plan.title":        "Link slower than the plan",
+	"link.below_plan.detail":       "%s negotiated %.0f Mbps, less than the %s speed of the plan (%.0f Mbps): the local link cannot deliver the subscribed speed. Check the cable (category, damaged pairs) and the ports.",
+	"link.speedtest_capped.title":  "Speedtest capped by the local link",
+	"link.speedtest_capped.detail": "The best speedtest result (%.0f Mbps) reached the %s link speed (%.0f Mbps, %.0f%%): the link, not the ISP, limits the measurement.",
+
+	// Wi-Fi findings
+	"wifi.unavailable.title":        "Wi-Fi state unavailable",
+	"wifi.unavailable.detail":       "The Wi-Fi link could not be read (%s).",
+	"wifi.no_adapter.title":         "No Wi-Fi adapter",
+	"wifi.no_adapter.detail":        "No wireless adapter was found; the Wi-Fi checks were skipped.",
+	"wifi.not_active.title":         "Wi-Fi not in use",
+	"wifi.not_active.detail":        "The wireless adapter %s does not carry the default route (%s does); the Wi-Fi checks were skipped.",
+	"wifi.not_connected.title":      "Wi-Fi not connected",
+	"wifi.not_connected.detail":     "No connected wireless interface was found (%s).",
+	"wifi.signal_poor.title":        "Poor Wi-Fi signal",
+	"wifi.signal_poor.detail":       "The signal is %d dBm: at this level the link uses the lowest rates and disconnects. Move closer to the access point or add one.",
+	"wifi.signal_weak.title":        "Weak Wi-Fi signal",
+	"wifi.signal_weak.detail":       "The signal is %d dBm on %s GHz, below the -67 dBm needed for stable calls and video. Move closer to the access point or remove obstacles.",
+	"wifi.signal_weak_24.title":     "2.4 GHz with weak signal",
+	"wifi.signal_weak_24.detail":    "The signal is %d dBm on 2.4 GHz channel %d. The 2.4 GHz band is slow and crowded, and a weak signal there usually means the device is too far for 5 GHz: move closer to the access point or add one.",
+	"wifi.band_24.title":            "Connected on 2.4 GHz",
+	"wifi.band_24.detail":           "The link uses 2.4 GHz channel %d, a slower and more crowded band. If the access point also offers 5 GHz, prefer it.",
+	"wifi.low_snr.title":            "Low Wi-Fi signal-to-noise ratio",
+	"wifi.low_snr.detail":           "The signal-to-noise ratio is %d dB (signal %d dBm, noise %d dBm), below 20 dB: interference keeps the link on low rates.",
+	"wifi.low_rate.title":           "Low Wi-Fi link rate",
+	"wifi.low_rate.detail":          "The transmit rate is %.1f Mbps (receive %.1f Mbps): the link runs on low modulation rates because of distance or interference.",
+	"wifi.rate_below_plan.title":    "Wi-Fi rate below the plan",
+	"wifi.rate_below_plan.detail":   "The receive rate is %.1f Mbps, about %.0f Mbps of actual throughput, less than the %.0f Mbps plan: speedtests over this Wi-Fi link cannot reach the subscribed speed.",
+	"wifi.scan_empty.title":         "Wi-Fi scan found no networks",
+	"wifi.scan_empty.detail":        "The scan returned no access points: it may need administrator rights (iw scan) or location access (netsh), so channel congestion was not scored.",
+	"wifi.channel_congested.title":  "Congested Wi-Fi channel",
+	"wifi.channel_congested.detail": "Channel %d overlaps %d other networks (congestion score %d/100): they share its airtime. Less used channels: %s.",
+	"wifi.band_congested.title":     "Congested Wi-Fi band",
+	"wifi.band_congested.detail":    "Channel %d overlaps %d other networks (congestion score %d/100) and no channel of the %s GHz band is clearly quieter. Prefer another band, or a narrower channel width.",
+	"wifi.better_channel.title":     "Quieter Wi-Fi channels available",
+	"wifi.better_channel.detail":    "Channel %d overlaps %d other networks (congestion score %d/100). These channels are less used: %s.",
+
+	// Dual-stack findings
+	"dualstack.ipv6_absent.title":  "IPv6 not detected",
+	"dualstack.ipv6_absent.detail": "No IPv6 addresses were found on active interfaces.",
+	"dualstack.ipv6_broken.title":  "IPv6 appears broken",
+	"dualstack.ipv6_broken.detail": "IPv6 is present but connectivity tests failed.",
+
+	// DNS findings
+	"dns.system_servers_unreadable.title":  "Unable to read system DNS servers",
+	"dns.system_servers_unreadable.detail": "%s",
+	"dns.no_servers.title":                 "No DNS servers available",
+	"dns.no_servers.detail":                "Provide DNS servers in config or ensure DHCP provides resolvers.",
+	"dns.all_failed.title":                 "DNS benchmark failed",
+	"dns.all_failed.detail":                "All DNS queries failed for every server.",
+	"dns.partial_failures.title":           "Partial DNS failures detected",
+	"dns.partial_failures.detail":          "%d queries failed out of %d.",
+
+	// MTU findings
+	"mtu.no_targets.title":         "No MTU targets",
+	"mtu.no_targets.detail":        "No targets configured for PMTU checks.",
+	"mtu.check_failed.title":       "PMTU check failed",
+	"mtu.check_failed.detail":      "PMTU check for %s (%s) failed: %s",
+	"mtu.not_detected.title":       "PMTU not detected",
+	"mtu.not_detected.detail":      "No PMTU values could be measured from the configured targets.",
+	"mtu.below_interface.title":    "PMTU lower than interface MTU",
+	"mtu.below_interface.detail":   "Detected PMTU %d while local MTU is %d. Possible clamping/PPPoE or tunnel overhead.",
+	"mtu.blackhole.title":          "Possible blackhole MTU",
+	"mtu.blackhole.detail":         "Targets with DF loss and no ICMP fragmentation replies: %s.",
+	"mtu.mss_failed.title":         "MSS observation failed",
+	"mtu.mss_failed.detail":        "%s",
+	"mtu.mss_detected.title":       "MSS clamping detected",
+	"mtu.mss_detected.detail":      "Observed MSS %d (%s).",
+	"mtu.health_warning.title":     "MTU health warning",
+	"mtu.health_warning.detail":    "MTU health rated %s. PMTU min %d, blackhole=%s, MSS=%s.",
+	"mtu.suggested.title":          "Suggested MTU",
+	"mtu.suggested.detail":         "Suggested effective MTU: %d (based on PMTU min).",
+	"mtu.icmp_blocking.title":      "Possible ICMP blocking",
+	"mtu.icmp_blocking.detail":     "Some paths likely block ICMP fragmentation-needed replies (blackhole MTU). This can cause pages not loading or unstable VPNs.",
+	"mtu.pppoe_suspected.title":    "PPPoE overhead suspected",
+	"mtu.pppoe_suspected.detail":   "Observed MSS suggests PPPoE/overhead (MTU ~1492).",
+	"mtu.mss_clamping.title":       "MSS clamping",
+	"mtu.mss_clamping.detail":      "Observed MSS is lower than expected; a router or upstream network is clamping MSS.",
+	"mtu.health.OK":                "OK",
+	"mtu.health.Warning":           "Warning",
+	"mtu.health.Bad":               "Bad",
+	"mtu.blackhole_state.probable": "probable",
+	"mtu.blackhole_state.no":       "no",
+	"mss.class.none":               "no clamping",
+	"mss.class.pppoe_suspected":    "PPPoE suspected",
+	"mss.class.low":                "low",
+	"mss.class.aggressive":         "aggressive",
+
+	// Latency findings
+	"latency.no_targets.title":       "No ping targets",
+	"latency.no_targets.detail":      "No targets configured for latency checks.",
+	"latency.sampling_failed.title":  "Latency sampling failed",
+	"latency.sampling_failed.detail": "Latency sampling for %s failed: %s",
+
+	// Bufferbloat findings
+	"bufferbloat.pending.title":  "Bufferbloat test pending",
+	"bufferbloat.pending.detail": "Base version includes configuration for load tests; measurements will be added later.",
+	"bufferbloat.phase.idle":     "idle",
+	"bufferbloat.phase.download": "download",
+	"bufferbloat.phase.upload":   "upload",
+
+	// HTTP findings
+	"http.pending.title":  "HTTP timing checks pending",
+	"http.pending.detail": "Endpoints are configured; timing probes will be added in a future version.",
+
+	// Traceroute findings
+	"traceroute.no_targets.title":  "No traceroute targets",
+	"traceroute.no_targets.detail": "No traceroute targets are configured.",
+	"traceroute.failed.title":      "Traceroute failed",
+	"traceroute.failed.detail":     "Traceroute to %s failed: %s",
+
+	// Speedtest findings
+	"speedtest.binary_missing.title":  "speedtest.exe not found",
+	"speedtest.binary_missing.detail": "Place Ookla Speedtest CLI (speedtest.exe) next to the tool or in PATH.",
+	"speedtest.failed.title":          "Speedtest failed",
+	"speedtest.failed.detail":         "%s server %d run %d: %s",
+	"speedtest.direction.download":    "download",
+	"speedtest.direction.upload":      "upload",
+	"speedtest.run_note":              "%s server %d run %d",
+	"speedtest.category.local":        "Local",
+	"speedtest.category.national":     "National",
+	"speedtest.category.eu":           "EU",
+	"speedtest.category.us":           "US",
+
+	// Speedtest scale defaults
+	"speedtest.scale.download.suffering":         "Suffering 🐢",
+	"speedtest.scale.download.suffering.desc":    "ADSL / 3G / basic satellite / very distant FWA",
+	"speedtest.scale.download.livable":           "Livable 🚶‍♂️",
+	"speedtest.scale.download.livable.desc":      "VDSL / average 4G / decent FWA / decent satellite",
+	"speedtest.scale.download.ideal":             "IDEAL ⭐🚀",
+	"speedtest.scale.download.ideal.desc":        "Everyday use + streaming + calls + gaming without worries",
+	"speedtest.scale.download.fast":              "Lightning ⚡",
+	"speedtest.scale.download.fast.desc":         "Serious 5G / good FTTH",
+	"speedtest.scale.download.fibre_great":       "Great Fibre 👑",
+	"speedtest.scale.download.fibre_great.desc":  "FTTH 1–2.5G",
+	"speedtest.scale.download.fibre_divine":      "Divine Fibre 🔱✨",
+	"speedtest.scale.download.fibre_divine.desc": "FTTH 2.5G+ / a datacenter at home",
+	"speedtest.scale.upload.suffering":           "Suffering 🐢",
+	"speedtest.scale.upload.suffering.desc":      "ADSL / basic satellite / poor 4G / distant FWA",
+	"speedtest.scale.upload.livable":             "Livable 🚶‍♂️",
+	"speedtest.scale.upload.livable.desc":        "VDSL / average 4G / decent FWA / decent satellite",
+	"speedtest.scale.upload.pushing":             "Pushing 🚗💨",
+	"speedtest.scale.upload.pushing.desc":        "Top 4G / so-so 5G / Starlink / good FWA",
+	"speedtest.scale.upload.ideal":               "IDEAL ⭐🚀",
+	"speedtest.scale.upload.ideal.desc":          "Upload fit for any use",
+	"speedtest.scale.upload.ftth_good":           "Good FTTH ⚡",
+	"speedtest.scale.upload.ftth_good.desc":      "Modern fibre profiles (300/500 up)",
+	"speedtest.scale.upload.ftth_great":          "Great FTTH 👑",
+	"speedtest.scale.upload.ftth_great.desc":     "500/1000 up",
+	"speedtest.scale.upload.ftth_extreme":        "Extreme FTTH 🦾",
+	"speedtest.scale.upload.ftth_extreme.desc":   "1–2.5G up",
+	"speedtest.scale.upload.ftth_divine":         "Divine FTTH 🔱✨",
+	"speedtest.scale.upload.ftth_divine.desc":    "10G (up to ~10,000 up)",
+
+	// Report
+	"report.title":                          "Conncheck Report",
+	"report.version":                        "Version %s",
+	"report.environment":                    "Environment",
+	"report.env.os":                         "OS:",
+	"report.env.hostname":                   "Hostname:",
+	"report.env.timezone":                   "Timezone:",
+	"report.env.link":                       "Link:",
+	"report.env.media.ethernet":             "Ethernet",
+	"report.env.media.wireless":             "Wi-Fi",
+	"report.env.duplex.full":                "full duplex",
+	"report.env.duplex.half":                "half duplex",
+	"report.env.autoneg.on":                 "autonegotiation on",
+	"report.env.autoneg.off":                "autonegotiation off",
+	"report.env.speed":                      "%.0f Mbps",
+	"report.env.speed_unknown":              "unknown speed",
+	"report.findings":                       "Findings",
+	"report.findings.none":                  "No findings were recorded.",
+	"report.speedtest.title":                "Speedtest scale (local server averages)",
+	"report.speedtest.download":             "Average download",
+	"report.speedtest.upload":               "Average upload",
+	"report.speedtest.comparisons":          "Variation compared to local servers",
+	"report.speedtest.comparison":           "%.0f%% (%.0f Mbps, loss %.0f%%)",
+	"report.speedtest.cmp.national":         "National",
+	"report.speedtest.cmp.eu":               "European",
+	"report.speedtest.cmp.us":               "USA",
+	"report.speedtest.unavailable":          "Speedtest unavailable or without local data.",
+	"report.dns.title":                      "DNS Benchmark",
+	"report.dns.domains":                    "Domains:",
+	"report.dns.bar":                        "%.1f ms (%d ok, %d fail)",
+	"report.dns.unavailable":                "DNS benchmark unavailable or without enough data.",
+	"report.dns.summary.best":               "Well done: you already have the best DNS server configured.",
+	"report.dns.summary.slower":             "The currently configured DNS is on average %.0f%% slower than these measured alternatives: %s.",
+	"report.latency.title":                  "Latency monitor (1 minute, 100ms interval)",
+	"report.latency.loss":                   "Loss",
+	"report.latency.replay":                 "Replay animation",
+	"report.latency.note":                   "The line shows latency variation; red dots mark packet loss.",
+	"report.latency.avg":                    "Average:",
+	"report.latency.min":                    "Min:",
+	"report.latency.max":                    "Max:",
+	"report.latency.loss_label":             "Loss:",
+	"report.latency.unavailable":            "Latency monitor unavailable or without enough data.",
+	"report.mtu.title":                      "MTU & PMTU",
+	"report.mtu.status":                     "MTU status",
+	"report.mtu.health":                     "Health: %s",
+	"report.mtu.blackhole":                  "Blackhole: %s",
+	"report.mtu.mss":                        "MSS: %s",
+	"report.mtu.targets_tested":             "Targets tested:",
+	"report.mtu.values":                     "Key values",
+	"report.mtu.local":                      "Local MTU: %d",
+	"report.mtu.pmtu_min":                   "Minimum PMTU: %d",
+	"report.mtu.suggested":                  "Suggested: %d",
+	"report.mtu.details":                    "PMTU per target",
+	"report.mtu.unavailable":                "MTU unavailable or without enough data.",
+	"report.regions.title":                  "Results by region",
+	"report.regions.region":                 "Region",
+	"report.regions.targets":                "Targets",
+	"report.regions.latency":                "Avg latency",
+	"report.regions.loss":                   "Worst loss",
+	"report.regions.pmtu":                   "Lowest PMTU",
+	"report.regions.hops":                   "Avg hops",
+	"report.regions.http":                   "Avg HTTP total",
+	"report.regions.unassigned":             "unassigned",
+	"report.regions.note":                   "Regions come from targets.catalog; targets outside the catalog are listed as unassigned.",
+	"report.gateway.title":                  "Gateway quality",
+	"report.gateway.address":                "Gateway:",
+	"report.gateway.range":                  "Min–max RTT range with the average marked",
+	"report.gateway.samples":                "%d samples, longest loss burst %d",
+	"report.gateway.jitter":                 "Jitter (RFC 3550):",
+	"report.gateway.stddev":                 "Std dev:",
+	"report.gateway.method":                 "Measured with:",
+	"report.gateway.icmp_loss.rate_limited": "ping rate-limited (%s%% lost)",
+	"report.gateway.icmp_loss.blocked":      "ping blocked",
+	"report.gateway.icmp_loss.real":         "ping loss confirmed by %s",
+	"report.gateway.no_link_timing":         "link-layer timing unavailable over IPv6",
+	"report.gateway.unavailable":            "Gateway measurements unavailable.",
+	"report.wifi.title":                     "Wi-Fi link",
+	"report.wifi.network":                   "Network:",
+	"report.wifi.hidden":                    "not recorded (privacy: minimal)",
+	"report.wifi.interface":                 "Interface:",
+	"report.wifi.channel":                   "Channel:",
+	"report.wifi.signal":                    "Signal:",
+	"report.wifi.noise":                     "Noise:",
+	"report.wifi.rates":                     "PHY rate:",
+	"report.wifi.congestion":                "Congestion:",
+	"report.wifi.overlapping":               "%s overlapping of %s networks found",
+	"report.wifi.recommended":               "Less used channels:",
+	"report.wifi.channel_header":            "Channel",
+	"report.wifi.networks":                  "Networks on the channel",
+	"report.wifi.overlapping_header":        "Overlapping networks",
+	"report.wifi.score":                     "Score",
+	"report.wifi.current":                   "current",
+	"report.dualstack.title":                "Dual-stack reachability",
+	"report.dualstack.address":              "Address",
+	"report.dualstack.reachability":         "Reachability",
+	"report.dualstack.ipv4":                 "IPv4",
+	"report.dualstack.ipv6":                 "IPv6",
+	"report.dualstack.dual":                 "Dual-stack name",
+	"report.dualstack.state.ok":             "Working",
+	"report.dualstack.state.fail":           "Failing",
+	"report.dualstack.state.unknown":        "Not measured",
+	"report.traceroute.title":               "Traceroute",
+	"report.traceroute.note":                "Highlighted hops add 30 ms or more over the previous hop. Per-hop loss is not shown because routers often rate-limit ICMP.",
+	"report.traceroute.hop":                 "Hop",
+	"report.traceroute.host":                "Host",
+	"report.traceroute.rtt":                 "Avg RTT",
+	"report.traceroute.timeout":             "no reply",
+	"report.http.title":                     "HTTP timing",
+	"report.http.summary":                   "status %s, total %.0f ms",
+	"report.http.phase.dns":                 "DNS",
+	"report.http.phase.connect":             "TCP connect",
+	"report.http.phase.tls":                 "TLS handshake",
+	"report.http.phase.ttfb":                "Time to first byte",
+	"report.http.phase.download":            "Download",
+	"report.http.unavailable":               "HTTP timing unavailable.",
+	"report.bufferbloat.title":              "Bufferbloat: idle vs loaded latency",
+	"report.bufferbloat.target":             "Latency measured towards %s.",
+	"report.bufferbloat.avg":                "Average",
+	"report.bufferbloat.unavailable":        "Bufferbloat measurements unavailable.",
+	"report.tests":                          "Test Results",
+	"report.excluded.title":                 "Excluded tests",
+	"report.excluded.config":                "disabled in the configuration",
+	"report.excluded.only":                  "not selected by -only %s",
+	"report.excluded.skip":                  "excluded by -skip %s",
+	"report.evidence":                       "Evidence",
+	"report.generated":                      "Generated at %s",
+	"report.summary":                        "Tests: %d, OK: %d, WARN: %d, FAIL: %d, SKIPPED: %d",
+
+	// Web UI
+	"web.title":          "conncheck runs",
+	"web.root":           "Runs in %s",
+	"web.run":            "Run",
+	"web.started":        "Started",
+	"web.status":         "Status",
+	"web.summary":        "Summary",
+	"web.report":         "Report",
+	"web.files":          "Files",
+	"web.running":        "A run is in progress: %s.",
+	"web.follow":         "Follow it",
+	"web.mode":           "Mode",
+	"web.only":           "Only",
+	"web.skip":           "Skip",
+	"web.start":          "Start run",
+	"web.selection_help": "Names, categories or globs, comma-separated. Tests: ",
+	"web.previous":       "Previous runs",
+	"web.back":           "All runs",
+	"web.cancel":         "Cancel run",
+	"web.test":           "Test",
+	"web.detail":         "Progress",
+	"web.excluded":       "excluded",
+	"web.none":           "No completed runs yet.",
+}
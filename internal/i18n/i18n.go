@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	English = "en"
+	Italian = "it"
+
+	DefaultLanguage = English
+)
+
+var catalogs = map[string]map[string]string{
+	English: english,
+	Italian: italian,
+}
+
+var (
+	mu       sync.RWMutex
+	language = DefaultLanguage
+)
+
+// SetLanguage selects the catalog used by T. An empty value selects the default language.
+func SetLanguage(lang string) error {
+	lang = Normalize(lang)
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	if _, ok := catalogs[lang]; !ok {
+		return fmt.Errorf("unsupported language %q (available: %s)", lang, strings.Join(Languages(), ", "))
+	}
+	mu.Lock()
+	language = lang
+	mu.Unlock()
+	return nil
+}
+
+// Language returns the active language code.
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// Languages lists the available language codes.
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Normalize reduces locale strings like "it_IT.UTF-8" or "en-US" to a catalog code.
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if idx := strings.IndexAny(lang, "_-."); idx >= 0 {
+		lang = lang[:idx]
+	}
+	return lang
+}
+
+// T formats the message identified by key in the active language. Missing keys
+// fall back to English and then to the key itself.
+func T(key string, args ...any) string {
+	return Lookup(Language(), key, args...)
+}
+
+// Lookup formats the message identified by key in the given language.
+func Lookup(lang, key string, args ...any) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Has reports whether key exists in the default catalog.
+func Has(key string) bool {
+	_, ok := catalogs[DefaultLanguage][key]
+	return ok
+}
+
+// Label localizes a language-neutral code stored in results (for example an
+// MSS class) using the catalog entry "<prefix>.<code>". Unknown codes are
+// returned unchanged so user-provided values still render.
+func Label(prefix, code string) string {
+	if code == "" {
+		return ""
+	}
+	key := prefix + "." + code
+	if !Has(key) {
+		return code
+	}
+	return T(key)
+}
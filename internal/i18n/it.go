@@ -0,0 +1,455 @@
+package i18n
+
+var italian = map[string]string{
+	// CLI and engine
+	"cli.outputs":                "Output generati:",
+	"cli.summary":                "Riepilogo: %s",
+	"cli.done":                   "Fatto.",
+	"cli.exporter.listening":     "Metriche disponibili su http://%s/metrics",
+	"cli.exporter.source_dir":    "Espongo l'ultima esecuzione trovata in %s",
+	"cli.exporter.monitor":       "Modalità monitor: eseguo i test ogni %s",
+	"cli.exporter.cycle_failed":  "Ciclo di monitoraggio fallito: %v",
+	"cli.timeseries.pushed":      "Inviati %d punti della serie temporale a %s",
+	"cli.timeseries.push_failed": "Invio della serie temporale fallito (i file nella cartella di output sono completi): %v",
+	"cli.config_defaults":        "Nessun conncheck.yaml trovato; uso le impostazioni predefinite.",
+	"cli.config_loaded":          "Uso la configurazione %s",
+	"cli.validate.ok":            "%s: la configurazione è valida.",
+	"cli.validate.issues":        "Trovati %d problemi.",
+	"cli.yes":                    "sì",
+	"cli.no":                     "no",
+	"cli.init.exists":            "%s esiste già; usa -force per sovrascriverlo.",
+	"cli.init.detecting":         "Rilevo le impostazioni di rete...",
+	"cli.init.detected":          "Gateway: %s | Resolver: %s | IPv6: %s",
+	"cli.init.prompt.region":     "Regione (%s)",
+	"cli.init.prompt.download":   "Velocità di download dell'abbonamento in Mbps (0 se non nota)",
+	"cli.init.prompt.upload":     "Velocità di upload dell'abbonamento in Mbps (0 se non nota)",
+	"cli.init.invalid_number":    "%q non è un numero valido",
+	"cli.init.written":           "Scritto %s. Controllalo, poi avvia conncheck.",
+	"setup.yaml.header":          "conncheck.yaml generato da \"conncheck init\".\nCiò che manca usa i valori predefiniti; esegui\n\"conncheck validate\" dopo averlo modificato.",
+	"setup.yaml.detected":        "Rilevato su questo computer:",
+	"setup.yaml.gateway":         "  gateway:  %s (lan_health e mtu_pmtu lo usano automaticamente)",
+	"setup.yaml.resolvers":       "  resolver: %s",
+	"setup.yaml.ipv6":            "  IPv6:     %s",
+	"setup.yaml.not_found":       "non trovato",
+	"setup.yaml.none_found":      "nessuno trovato",
+	"setup.yaml.available":       "disponibile",
+	"setup.yaml.not_available":   "non disponibile",
+	"setup.yaml.language":        "Lingua di finding, log e report.html: en | it",
+	"setup.yaml.plan":            "Velocità dell'abbonamento; un collegamento cablato negoziato a meno viene segnalato.",
+	"setup.yaml.dns_servers":     "Prima i resolver di sistema, poi quelli pubblici per confronto.",
+	"setup.yaml.speedtest":       "Id dei server Speedtest. Con tutte le liste vuote la Speedtest CLI sceglie\nil server più vicino; aggiungi gli id elencati da \"speedtest -L\" per fissarli.",
+	"setup.yaml.bufferbloat":     "Il download predefinito da 100 MB. Indica in download_url un file più grande\nper un carico più lungo sulle linee veloci.",
+	"cli.remote.verified":        "Config remota %s: firma verificata, copia in cache aggiornata.",
+	"cli.remote.cached":          "Config remota %s non raggiungibile (%v); uso la copia in cache del %s.",
+	"cli.remote.keygen":          "Chiave privata scritta in %s. Chiave pubblica da incorporare con -ldflags \"-X conncheck/internal/remoteconfig.PublicKey=...\":\n%s",
+	"cli.remote.signed":          "Firma scritta in %s.",
+	"cli.diff.header":            "Confronto %s -> %s",
+	"cli.diff.none":              "Nessuna differenza.",
+	"cli.diff.statuses":          "Cambi di stato dei test:",
+	"cli.diff.added":             "Nuovi rilievi:",
+	"cli.diff.resolved":          "Rilievi risolti:",
+	"cli.diff.metrics":           "Variazioni delle metriche (almeno %.0f%%):",
+	"cli.diff.not_run":           "non eseguito",
+	"cli.history.none":           "Nessuna esecuzione completata in %s.",
+	"cli.history.header":         "ESECUZIONE\tSTATO\tOK/WARN/FAIL/SKIP\tDOWN Mbps\tUP Mbps\tPING ms",
+	"cli.list_tests.header":      "TEST\tNOME\tCATEGORIA\tESEGUITO",
+	"cli.serve.listening":        "Esecuzioni in %[2]s servite su http://%[1]s/",
+	"cli.serve.not_loopback":     "%s è raggiungibile da altre macchine; usa -allow-remote per ascoltare comunque",
+	"dashboard.loss":             "persi %d/%d",
+	"dashboard.throughput":       "%s: download %.1f Mbps, upload %.1f Mbps",
+	"gui.title":                  "conncheck",
+	"gui.starting":               "Avvio...",
+	"gui.step":                   "Passo %d di %d: %s...",
+	"gui.done":                   "Fatto. %s",
+	"gui.failed":                 "L'esecuzione non è stata completata: %v",
+	"gui.open_report":            "Apri report",
+	"gui.open_folder":            "Apri cartella risultati",
+	"gui.copy_summary":           "Copia riepilogo",
+	"cli.serve.run_started":      "Esecuzione avviata dall'app web: %s",
+	"engine.skipping":            "Salto %s (disabilitato nella configurazione).",
+	"engine.excluded":            "Salto %s (escluso da -%s %s).",
+	"engine.running":             "Eseguo %s...",
+
+	// Test display names
+	"test.preflight":     "Verifica preliminare",
+	"test.lan_health":    "Salute LAN",
+	"test.dualstack":     "Dual-stack IPv4/IPv6",
+	"test.dns_benchmark": "Benchmark DNS",
+	"test.mtu_pmtu":      "MTU / PMTU",
+	"test.latency":       "Latenza",
+	"test.bufferbloat":   "Bufferbloat",
+	"test.speedtest":     "Speedtest",
+	"test.traceroute":    "Traceroute",
+	"test.http_check":    "Tempi HTTP",
+	"test.neighbors":     "Tabella dei vicini",
+	"test.interfaces":    "Contatori di interfaccia",
+	"test.wifi":          "Collegamento Wi-Fi",
+
+	// Status labels
+	"status.OK":      "OK",
+	"status.WARN":    "Attenzione",
+	"status.FAIL":    "Fallito",
+	"status.SKIPPED": "Saltato",
+
+	// LAN findings
+	"lan.gateway_detection_failed.title":  "Rilevamento gateway fallito",
+	"lan.gateway_detection_failed.detail": "%s",
+	"lan.gateway_not_found.title":         "Gateway non trovato",
+	"lan.gateway_not_found.detail":        "Impossibile individuare il gateway predefinito dalla tabella di routing.",
+	"lan.gateway_unreachable.title":       "Il gateway non risponde",
+	"lan.gateway_unreachable.detail":      "Il gateway %s non ha risposto a nessuna delle %d richieste echo; potrebbe scartare l'ICMP.",
+	"lan.bottleneck.title":                "Probabile collo di bottiglia LAN/Wi-Fi",
+	"lan.bottleneck.detail":               "Il collegamento al gateway %s è già degradato: %s. I problemi più lontani potrebbero nascere nella rete locale.",
+	"lan.loss_burst.title":                "Raffica di risposte perse dal gateway",
+	"lan.loss_burst.detail":               "%d richieste echo consecutive al gateway sono andate perse (circa %.1f s senza risposta), tipico di interferenze o roaming Wi-Fi.",
+	"lan.reason.loss":                     "perdita %.1f%% (soglia %d%%)",
+	"lan.reason.p95":                      "RTT p95 %.1f ms (soglia %d ms)",
+	"lan.reason.jitter":                   "jitter %.1f ms (soglia %d ms)",
+	"lan.icmp_rate_limited.title":         "Il gateway limita il ping",
+	"lan.icmp_rate_limited.detail":        "Il gateway %s ha scartato il %.0f%% dei ping ma le sonde %s hanno perso solo il %.0f%%: la perdita dei ping è una limitazione dell'ICMP, non una perdita reale. I valori del gateway vengono da quelle sonde.",
+	"lan.icmp_blocked.title":              "Il gateway non risponde al ping",
+	"lan.icmp_blocked.detail":             "Il gateway %s ha scartato il %.0f%% dei ping ma ha risposto alle sonde %s (%.0f%% perse), quindi filtra l'ICMP. I valori del gateway vengono da quelle sonde.",
+	"lan.no_link_timing.title":            "Nessuna misura a livello di collegamento per il gateway",
+	"lan.no_link_timing.detail":           "Il gateway %s è raggiunto via IPv6 e non c'è una sonda NDP per misurarlo a livello di collegamento, quindi solo una connessione TCP può sostituire i ping persi.",
+	"lan.gateway_link_only.title":         "Gateway presente ma non misurabile",
+	"lan.gateway_link_only.detail":        "Il gateway %s non risponde né al ping, né all'ARP, né al TCP sulle porte 80/443/53, ma la tabella dei vicini lo riporta con %s (%s), quindi è sul collegamento. La sua latenza non è misurabile.",
+	"lan.method.icmp":                     "echo ICMP",
+	"lan.method.tcp":                      "connessione TCP",
+	"lan.method.arp":                      "ARP",
+	"lan.method.neighbor":                 "tabella dei vicini",
+
+	// Neighbour table findings
+	"neighbors.table_failed.title":         "Tabella dei vicini non disponibile",
+	"neighbors.table_failed.detail":        "Impossibile leggere la tabella dei vicini ARP/NDP (ip neigh / arp -a).",
+	"neighbors.gateway_mac_changed.title":  "Indirizzo MAC del gateway cambiato",
+	"neighbors.gateway_mac_changed.detail": "Il gateway %s ha risposto da più di un MAC durante l'esecuzione (%s). Un altro dispositivo potrebbe rispondere al posto del gateway (ARP spoofing o router abusivo), salvo un failover del router.",
+	"neighbors.ip_multiple_macs.title":     "Indirizzo usato da più dispositivi",
+	"neighbors.ip_multiple_macs.detail":    "%s è stato visto con più MAC durante l'esecuzione (%s): probabilmente due dispositivi condividono questo indirizzo IP.",
+	"neighbors.duplicate_address.title":    "Duplicato del nostro indirizzo",
+	"neighbors.duplicate_address.detail":   "Un altro dispositivo risponde per il nostro indirizzo %s (%s). Entrambi gli host avranno interruzioni intermittenti; verificare gli indirizzi statici e il server DHCP.",
+
+	// Interface counter findings
+	"interfaces.unavailable.title":     "Contatori di interfaccia non disponibili",
+	"interfaces.unavailable.detail":    "Impossibile leggere i contatori di interfaccia sia all'inizio sia alla fine dell'esecuzione su %s.",
+	"interfaces.no_active.title":       "Nessuna interfaccia attiva",
+	"interfaces.no_active.detail":      "Nessuna interfaccia portava la rotta predefinita o ha trasferito traffico durante l'esecuzione.",
+	"interfaces.machine_wide.title":    "Contatori di tutte le schede",
+	"interfaces.machine_wide.detail":   "I contatori per scheda non erano disponibili, quindi questi valori sono i totali di netstat -e per l'intero computer, sommati su tutte le schede e non solo su quella attiva.",
+	"interfaces.counters_reset.title":  "Contatori di interfaccia azzerati",
+	"interfaces.counters_reset.detail": "I contatori di %s sono diminuiti durante l'esecuzione: il driver li ha azzerati, di solito perché il collegamento è caduto e tornato.",
+	"interfaces.errors.title":          "Errori di interfaccia durante l'esecuzione",
+	"interfaces.errors.detail":         "%s ha contato errori durante i test (%s), il %.3f%% di %d pacchetti. Verificare il cavo e la porta dello switch, oppure il segnale e il driver Wi-Fi.",
+	"interfaces.drops.title":           "Pacchetti scartati dall'interfaccia",
+	"interfaces.drops.detail":          "%s ha scartato pacchetti durante i test (%s). Gli scarti possono essere innocui (protocolli sconosciuti, tag VLAN) ma anche un segno di buffer pieni.",
+
+	// Link findings
+	"link.half_duplex.title":       "Collegamento half-duplex",
+	"link.half_duplex.detail":      "%s ha negoziato half duplex a %.0f Mbps: trasmissione e ricezione si alternano e le collisioni riducono la velocità. Di solito è un duplex mismatch con una porta forzata a velocità fissa, o un cavo difettoso.",
+	"link.autoneg_off.title":       "Autonegoziazione disattivata",
+	"link.autoneg_off.detail":      "%s ha velocità e duplex forzati. Se la porta dello switch o del router autonegozia, i due lati possono finire in duplex mismatch.",
+	"link.below_plan.title":        "Collegamento più lento dell'abbonamento",
+	"link.below_plan.detail":       "%s ha negoziato %.0f Mbps, meno della velocità in %s dell'abbonamento (%.0f Mbps): il collegamento locale non può raggiungere la velocità sottoscritta. Verificare il cavo (categoria, coppie danneggiate) e le porte.",
+	"link.speedtest_capped.title":  "Speedtest limitato dal collegamento locale",
+	"link.speedtest_capped.detail": "Il miglior risultato dello speedtest (%.0f Mbps) ha raggiunto la velocità del collegamento di %s (%.0f Mbps, %.0f%%): è il collegamento, non il provider, a limitare la misura.",
+
+	// Wi-Fi findings
+	"wifi.unavailable.title":        "Stato Wi-Fi non disponibile",
+	"wifi.unavailable.detail":       "Impossibile leggere il collegamento Wi-Fi (%s).",
+	"wifi.no_adapter.title":         "Nessun adattatore Wi-Fi",
+	"wifi.no_adapter.detail":        "Nessun adattatore wireless trovato; i controlli Wi-Fi sono stati saltati.",
+	"wifi.not_active.title":         "Wi-Fi non in uso",
+	"wifi.not_active.detail":        "L'adattatore wireless %s non porta la rotta predefinita (la porta %s); i controlli Wi-Fi sono stati saltati.",
+	"wifi.not_connected.title":      "Wi-Fi non connesso",
+	"wifi.not_connected.detail":     "Nessuna interfaccia wireless connessa trovata (%s).",
+	"wifi.signal_poor.title":        "Segnale Wi-Fi scarso",
+	"wifi.signal_poor.detail":       "Il segnale è di %d dBm: a questo livello il collegamento usa le velocità più basse e si disconnette. Avvicinarsi all'access point o aggiungerne uno.",
+	"wifi.signal_weak.title":        "Segnale Wi-Fi debole",
+	"wifi.signal_weak.detail":       "Il segnale è di %d dBm su %s GHz, sotto i -67 dBm necessari per chiamate e video stabili. Avvicinarsi all'access point o rimuovere gli ostacoli.",
+	"wifi.signal_weak_24.title":     "2,4 GHz con segnale debole",
+	"wifi.signal_weak_24.detail":    "Il segnale è di %d dBm sul canale %d a 2,4 GHz. La banda a 2,4 GHz è lenta e affollata, e un segnale debole di solito indica che il dispositivo è troppo lontano per i 5 GHz: avvicinarsi all'access point o aggiungerne uno.",
+	"wifi.band_24.title":            "Connesso a 2,4 GHz",
+	"wifi.band_24.detail":           "Il collegamento usa il canale %d a 2,4 GHz, una banda più lenta e affollata. Se l'access point offre anche i 5 GHz, preferirli.",
+	"wifi.low_snr.title":            "Rapporto segnale/rumore Wi-Fi basso",
+	"wifi.low_snr.detail":           "Il rapporto segnale/rumore è di %d dB (segnale %d dBm, rumore %d dBm), sotto i 20 dB: le interferenze tengono il collegamento su velocità basse.",
+	"wifi.low_rate.title":           "Velocità del collegamento Wi-Fi bassa",
+	"wifi.low_rate.detail":          "La velocità di trasmissione è di %.1f Mbps (ricezione %.1f Mbps): il collegamento usa modulazioni lente per distanza o interferenze.",
+	"wifi.rate_below_plan.title":    "Velocità Wi-Fi sotto l'abbonamento",
+	"wifi.rate_below_plan.detail":   "La velocità di ricezione è di %.1f Mbps, circa %.0f Mbps effettivi, meno dei %.0f Mbps dell'abbonamento: gli speedtest su questo collegamento Wi-Fi non possono raggiungere la velocità sottoscritta.",
+	"wifi.scan_empty.title":         "La scansione Wi-Fi non ha trovato reti",
+	"wifi.scan_empty.detail":        "La scansione non ha restituito access point: potrebbe richiedere i privilegi di amministratore (iw scan) o l'accesso alla posizione (netsh), quindi la congestione del canale non è stata valutata.",
+	"wifi.channel_congested.title":  "Canale Wi-Fi congestionato",
+	"wifi.channel_congested.detail": "Il canale %d si sovrappone ad altre %d reti (punteggio di congestione %d/100) che ne condividono il tempo di trasmissione. Canali meno usati: %s.",
+	"wifi.band_congested.title":     "Banda Wi-Fi congestionata",
+	"wifi.band_congested.detail":    "Il canale %d si sovrappone ad altre %d reti (punteggio di congestione %d/100) e nessun canale della banda a %s GHz è chiaramente più libero. Preferire un'altra banda o una larghezza di canale minore.",
+	"wifi.better_channel.title":     "Canali Wi-Fi più liberi disponibili",
+	"wifi.better_channel.detail":    "Il canale %d si sovrappone ad altre %d reti (punteggio di congestione %d/100). Questi canali sono meno usati: %s.",
+
+	// Dual-stack findings
+	"dualstack.ipv6_absent.title":  "IPv6 non rilevato",
+	"dualstack.ipv6_absent.detail": "Nessun indirizzo IPv6 trovato sulle interfacce attive.",
+	"dualstack.ipv6_broken.title":  "IPv6 sembra non funzionante",
+	"dualstack.ipv6_broken.detail": "IPv6 è presente ma i test di connettività sono falliti.",
+
+	// DNS findings
+	"dns.system_servers_unreadable.title":  "Impossibile leggere i server DNS di sistema",
+	"dns.system_servers_unreadable.detail": "%s",
+	"dns.no_servers.title":                 "Nessun server DNS disponibile",
+	"dns.no_servers.detail":                "Indica dei server DNS nella configurazione o verifica che il DHCP fornisca dei resolver.",
+	"dns.all_failed.title":                 "Benchmark DNS fallito",
+	"dns.all_failed.detail":                "Tutte le query DNS sono fallite per ogni server.",
+	"dns.partial_failures.title":           "Rilevati errori DNS parziali",
+	"dns.partial_failures.detail":          "%d query fallite su %d.",
+
+	// MTU findings
+	"mtu.no_targets.title":         "Nessun target MTU",
+	"mtu.no_targets.detail":        "Nessun target configurato per i controlli PMTU.",
+	"mtu.check_failed.title":       "Controllo PMTU fallito",
+	"mtu.check_failed.detail":      "Controllo PMTU verso %s (%s) fallito: %s",
+	"mtu.not_detected.title":       "PMTU non rilevato",
+	"mtu.not_detected.detail":      "Non è stato possibile misurare alcun valore PMTU dai target configurati.",
+	"mtu.below_interface.title":    "PMTU inferiore all'MTU dell'interfaccia",
+	"mtu.below_interface.detail":   "Rilevato PMTU %d mentre l'MTU locale è %d. Possibile clamping/PPPoE o overhead di tunnel.",
+	"mtu.blackhole.title":          "Possibile blackhole MTU",
+	"mtu.blackhole.detail":         "Target con perdita DF e senza risposte ICMP di frammentazione: %s.",
+	"mtu.mss_failed.title":         "Osservazione MSS fallita",
+	"mtu.mss_failed.detail":        "%s",
+	"mtu.mss_detected.title":       "Rilevato clamping MSS",
+	"mtu.mss_detected.detail":      "MSS osservato %d (%s).",
+	"mtu.health_warning.title":     "Avviso salute MTU",
+	"mtu.health_warning.detail":    "Salute MTU valutata %s. PMTU min %d, blackhole=%s, MSS=%s.",
+	"mtu.suggested.title":          "MTU suggerito",
+	"mtu.suggested.detail":         "MTU effettivo suggerito: %d (basato sul PMTU minimo).",
+	"mtu.icmp_blocking.title":      "Possibile blocco ICMP",
+	"mtu.icmp_blocking.detail":     "Alcuni percorsi probabilmente bloccano le risposte ICMP fragmentation-needed (blackhole MTU). Può causare pagine che non si caricano o VPN instabili.",
+	"mtu.pppoe_suspected.title":    "Sospetto overhead PPPoE",
+	"mtu.pppoe_suspected.detail":   "L'MSS osservato suggerisce PPPoE/overhead (MTU ~1492).",
+	"mtu.mss_clamping.title":       "Clamping MSS",
+	"mtu.mss_clamping.detail":      "L'MSS osservato è più basso del previsto; un router o la rete a monte sta limitando l'MSS.",
+	"mtu.health.OK":                "OK",
+	"mtu.health.Warning":           "Attenzione",
+	"mtu.health.Bad":               "Critico",
+	"mtu.blackhole_state.probable": "probabile",
+	"mtu.blackhole_state.no":       "no",
+	"mss.class.none":               "assente",
+	"mss.class.pppoe_suspected":    "PPPoE sospetto",
+	"mss.class.low":                "basso",
+	"mss.class.aggressive":         "aggressivo",
+
+	// Latency findings
+	"latency.no_targets.title":       "Nessun target ping",
+	"latency.no_targets.detail":      "Nessun target configurato per i controlli di latenza.",
+	"latency.sampling_failed.title":  "Campionamento latenza fallito",
+	"latency.sampling_failed.detail": "Campionamento latenza verso %s fallito: %s",
+
+	// Bufferbloat findings
+	"bufferbloat.pending.title":  "Test bufferbloat in sospeso",
+	"bufferbloat.pending.detail": "La versione base include la configurazione per i test sotto carico; le misure verranno aggiunte in seguito.",
+	"bufferbloat.phase.idle":     "riposo",
+	"bufferbloat.phase.download": "download",
+	"bufferbloat.phase.upload":   "upload",
+
+	// HTTP findings
+	"http.pending.title":  "Controlli tempi HTTP in sospeso",
+	"http.pending.detail": "Gli endpoint sono configurati; le sonde di temporizzazione verranno aggiunte in una versione futura.",
+
+	// Traceroute findings
+	"traceroute.no_targets.title":  "Nessun target traceroute",
+	"traceroute.no_targets.detail": "Nessun target traceroute configurato.",
+	"traceroute.failed.title":      "Traceroute fallito",
+	"traceroute.failed.detail":     "Traceroute verso %s fallito: %s",
+
+	// Speedtest findings
+	"speedtest.binary_missing.title":  "speedtest.exe non trovato",
+	"speedtest.binary_missing.detail": "Posiziona Ookla Speedtest CLI (speedtest.exe) accanto allo strumento o nel PATH.",
+	"speedtest.failed.title":          "Speedtest fallito",
+	"speedtest.failed.detail":         "%s server %d esecuzione %d: %s",
+	"speedtest.direction.download":    "download",
+	"speedtest.direction.upload":      "upload",
+	"speedtest.run_note":              "%s server %d esecuzione %d",
+	"speedtest.category.local":        "Locale",
+	"speedtest.category.national":     "Nazionale",
+	"speedtest.category.eu":           "EU",
+	"speedtest.category.us":           "USA",
+
+	// Speedtest scale defaults
+	"speedtest.scale.download.suffering":         "Sofferenza 🐢",
+	"speedtest.scale.download.suffering.desc":    "ADSL / 3G / sat base / FWA molto distante",
+	"speedtest.scale.download.livable":           "Si vive 🚶‍♂️",
+	"speedtest.scale.download.livable.desc":      "VDSL / 4G medio / FWA ok / sat decente",
+	"speedtest.scale.download.ideal":             "IDEALE ⭐🚀",
+	"speedtest.scale.download.ideal.desc":        "Uso normale + streaming + call + gaming senza pensieri",
+	"speedtest.scale.download.fast":              "Scheggia ⚡",
+	"speedtest.scale.download.fast.desc":         "5G serio / FTTH buona",
+	"speedtest.scale.download.fibre_great":       "Fibra Ottima 👑",
+	"speedtest.scale.download.fibre_great.desc":  "FTTH 1–2.5G",
+	"speedtest.scale.download.fibre_divine":      "Fibra Divina 🔱✨",
+	"speedtest.scale.download.fibre_divine.desc": "FTTH 2.5G+ / roba da datacenter a casa",
+	"speedtest.scale.upload.suffering":           "Sofferenza 🐢",
+	"speedtest.scale.upload.suffering.desc":      "ADSL / sat base / 4G scarso / FWA lontana",
+	"speedtest.scale.upload.livable":             "Si vive 🚶‍♂️",
+	"speedtest.scale.upload.livable.desc":        "VDSL / 4G medio / FWA ok / sat decente",
+	"speedtest.scale.upload.pushing":             "Spinge 🚗💨",
+	"speedtest.scale.upload.pushing.desc":        "4G top / 5G meh / Starlink / FWA buona",
+	"speedtest.scale.upload.ideal":               "IDEALE ⭐🚀",
+	"speedtest.scale.upload.ideal.desc":          "Upload adatto ad ogni uso",
+	"speedtest.scale.upload.ftth_good":           "FTTH Buona ⚡",
+	"speedtest.scale.upload.ftth_good.desc":      "Profili fibra moderni (300/500 up)",
+	"speedtest.scale.upload.ftth_great":          "FTTH Ottima 👑",
+	"speedtest.scale.upload.ftth_great.desc":     "500/1000 up",
+	"speedtest.scale.upload.ftth_extreme":        "FTTH Esagerata 🦾",
+	"speedtest.scale.upload.ftth_extreme.desc":   "1–2.5G up",
+	"speedtest.scale.upload.ftth_divine":         "FTTH Divina 🔱✨",
+	"speedtest.scale.upload.ftth_divine.desc":    "10G (fino a ~10.000 up)",
+
+	// Report
+	"report.title":                          "Report Conncheck",
+	"report.version":                        "Versione %s",
+	"report.environment":                    "Ambiente",
+	"report.env.os":                         "Sistema operativo:",
+	"report.env.hostname":                   "Nome host:",
+	"report.env.timezone":                   "Fuso orario:",
+	"report.env.link":                       "Collegamento:",
+	"report.env.media.ethernet":             "Ethernet",
+	"report.env.media.wireless":             "Wi-Fi",
+	"report.env.duplex.full":                "full duplex",
+	"report.env.duplex.half":                "half duplex",
+	"report.env.autoneg.on":                 "autonegoziazione attiva",
+	"report.env.autoneg.off":                "autonegoziazione disattivata",
+	"report.env.speed":                      "%.0f Mbps",
+	"report.env.speed_unknown":              "velocità sconosciuta",
+	"report.findings":                       "Risultati principali",
+	"report.findings.none":                  "Nessun risultato registrato.",
+	"report.speedtest.title":                "Scala Speedtest (medie server locali)",
+	"report.speedtest.download":             "Download medio",
+	"report.speedtest.upload":               "Upload medio",
+	"report.speedtest.comparisons":          "Variazioni rispetto ai locali",
+	"report.speedtest.comparison":           "%.0f%% (%.0f Mbps, perdita %.0f%%)",
+	"report.speedtest.cmp.national":         "Nazionali",
+	"report.speedtest.cmp.eu":               "Europei",
+	"report.speedtest.cmp.us":               "USA",
+	"report.speedtest.unavailable":          "Speedtest non disponibile o senza dati locali.",
+	"report.dns.title":                      "Benchmark DNS",
+	"report.dns.domains":                    "Domini:",
+	"report.dns.bar":                        "%.1f ms (%d ok, %d falliti)",
+	"report.dns.unavailable":                "Benchmark DNS non disponibile o senza dati sufficienti.",
+	"report.dns.summary.best":               "Complimenti: hai il server DNS migliore già configurato.",
+	"report.dns.summary.slower":             "Il DNS configurato attualmente in media è %.0f%% meno veloce di queste alternative misurate: %s.",
+	"report.latency.title":                  "Monitor Latency (1 minuto, intervallo 100ms)",
+	"report.latency.loss":                   "Perdita",
+	"report.latency.replay":                 "Replay animazione",
+	"report.latency.note":                   "La linea mostra la variazione della latenza; i punti rossi indicano packet loss.",
+	"report.latency.avg":                    "Media:",
+	"report.latency.min":                    "Min:",
+	"report.latency.max":                    "Max:",
+	"report.latency.loss_label":             "Perdita:",
+	"report.latency.unavailable":            "Monitor latency non disponibile o senza dati sufficienti.",
+	"report.mtu.title":                      "MTU & PMTU",
+	"report.mtu.status":                     "Stato MTU",
+	"report.mtu.health":                     "Salute: %s",
+	"report.mtu.blackhole":                  "Blackhole: %s",
+	"report.mtu.mss":                        "MSS: %s",
+	"report.mtu.targets_tested":             "Target testati:",
+	"report.mtu.values":                     "Valori principali",
+	"report.mtu.local":                      "MTU locale: %d",
+	"report.mtu.pmtu_min":                   "PMTU minimo: %d",
+	"report.mtu.suggested":                  "Suggerito: %d",
+	"report.mtu.details":                    "Dettaglio PMTU per target",
+	"report.mtu.unavailable":                "MTU non disponibile o senza dati sufficienti.",
+	"report.regions.title":                  "Risultati per regione",
+	"report.regions.region":                 "Regione",
+	"report.regions.targets":                "Target",
+	"report.regions.latency":                "Latenza media",
+	"report.regions.loss":                   "Perdita peggiore",
+	"report.regions.pmtu":                   "PMTU minima",
+	"report.regions.hops":                   "Hop medi",
+	"report.regions.http":                   "Totale HTTP medio",
+	"report.regions.unassigned":             "non assegnati",
+	"report.regions.note":                   "Le regioni provengono da targets.catalog; i target fuori dal catalogo sono elencati come non assegnati.",
+	"report.gateway.title":                  "Qualità del gateway",
+	"report.gateway.address":                "Gateway:",
+	"report.gateway.range":                  "Intervallo RTT min–max con la media evidenziata",
+	"report.gateway.samples":                "%d campioni, raffica di perdite più lunga %d",
+	"report.gateway.jitter":                 "Jitter (RFC 3550):",
+	"report.gateway.stddev":                 "Deviazione standard:",
+	"report.gateway.method":                 "Misurato con:",
+	"report.gateway.icmp_loss.rate_limited": "ping limitato (%s%% persi)",
+	"report.gateway.icmp_loss.blocked":      "ping bloccato",
+	"report.gateway.icmp_loss.real":         "perdita dei ping confermata da %s",
+	"report.gateway.no_link_timing":         "misura a livello di collegamento non disponibile su IPv6",
+	"report.gateway.unavailable":            "Misure del gateway non disponibili.",
+	"report.wifi.title":                     "Collegamento Wi-Fi",
+	"report.wifi.network":                   "Rete:",
+	"report.wifi.hidden":                    "non registrata (privacy: minimal)",
+	"report.wifi.interface":                 "Interfaccia:",
+	"report.wifi.channel":                   "Canale:",
+	"report.wifi.signal":                    "Segnale:",
+	"report.wifi.noise":                     "Rumore:",
+	"report.wifi.rates":                     "Velocità PHY:",
+	"report.wifi.congestion":                "Congestione:",
+	"report.wifi.overlapping":               "%s sovrapposte su %s reti trovate",
+	"report.wifi.recommended":               "Canali meno usati:",
+	"report.wifi.channel_header":            "Canale",
+	"report.wifi.networks":                  "Reti sul canale",
+	"report.wifi.overlapping_header":        "Reti sovrapposte",
+	"report.wifi.score":                     "Punteggio",
+	"report.wifi.current":                   "attuale",
+	"report.dualstack.title":                "Raggiungibilità dual-stack",
+	"report.dualstack.address":              "Indirizzo",
+	"report.dualstack.reachability":         "Raggiungibilità",
+	"report.dualstack.ipv4":                 "IPv4",
+	"report.dualstack.ipv6":                 "IPv6",
+	"report.dualstack.dual":                 "Nome dual-stack",
+	"report.dualstack.state.ok":             "Funzionante",
+	"report.dualstack.state.fail":           "Non funzionante",
+	"report.dualstack.state.unknown":        "Non misurato",
+	"report.traceroute.title":               "Traceroute",
+	"report.traceroute.note":                "Gli hop evidenziati aggiungono 30 ms o più rispetto all'hop precedente. La perdita per hop non è mostrata perché i router spesso limitano l'ICMP.",
+	"report.traceroute.hop":                 "Hop",
+	"report.traceroute.host":                "Host",
+	"report.traceroute.rtt":                 "RTT medio",
+	"report.traceroute.timeout":             "nessuna risposta",
+	"report.http.title":                     "Tempi HTTP",
+	"report.http.summary":                   "stato %s, totale %.0f ms",
+	"report.http.phase.dns":                 "DNS",
+	"report.http.phase.connect":             "Connessione TCP",
+	"report.http.phase.tls":                 "Handshake TLS",
+	"report.http.phase.ttfb":                "Tempo al primo byte",
+	"report.http.phase.download":            "Download",
+	"report.http.unavailable":               "Tempi HTTP non disponibili.",
+	"report.bufferbloat.title":              "Bufferbloat: latenza a riposo e sotto carico",
+	"report.bufferbloat.target":             "Latenza misurata verso %s.",
+	"report.bufferbloat.avg":                "Media",
+	"report.bufferbloat.unavailable":        "Misure bufferbloat non disponibili.",
+	"report.tests":                          "Risultati dei test",
+	"report.excluded.title":                 "Test esclusi",
+	"report.excluded.config":                "disabilitato nella configurazione",
+	"report.excluded.only":                  "non selezionato da -only %s",
+	"report.excluded.skip":                  "escluso da -skip %s",
+	"report.evidence":                       "Evidenze",
+	"report.generated":                      "Generato il %s",
+	"report.summary":                        "Test: %d, OK: %d, WARN: %d, FAIL: %d, SKIPPED: %d",
+
+	// Web UI
+	"web.title":          "Esecuzioni conncheck",
+	"web.root":           "Esecuzioni in %s",
+	"web.run":            "Esecuzione",
+	"web.started":        "Avvio",
+	"web.status":         "Stato",
+	"web.summary":        "Riepilogo",
+	"web.report":         "Report",
+	"web.files":          "File",
+	"web.running":        "Esecuzione in corso: %s.",
+	"web.follow":         "Seguila",
+	"web.mode":           "Modalità",
+	"web.only":           "Solo",
+	"web.skip":           "Escludi",
+	"web.start":          "Avvia",
+	"web.selection_help": "Nomi, categorie o glob separati da virgole. Test: ",
+	"web.previous":       "Esecuzioni precedenti",
+	"web.back":           "Tutte le esecuzioni",
+	"web.cancel":         "Annulla esecuzione",
+	"web.test":           "Test",
+	"web.detail":         "Avanzamento",
+	"web.excluded":       "escluso",
+	"web.none":           "Nessuna esecuzione completata.",
+}
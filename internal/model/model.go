@@ -16,6 +16,58 @@ type Result struct {
 	Findings    []Finding    `json:"findings" xml:"findings>finding"`
 	Tests       []TestResult `json:"tests" xml:"tests>test"`
 	Environment Environment  `json:"environment" xml:"environment"`
+	Targets     []Target     `json:"targets,omitempty" xml:"targets>target,omitempty"`
+	Excluded    []Exclusion  `json:"excluded,omitempty" xml:"excluded>test,omitempty"`
+}
+
+// Exclusion records a test that did not run and why: Reason is "config"
+// when the configuration disables it, or "only" / "skip" when the -only or
+// -skip selection left it out, with Pattern holding the selection.
+type Exclusion struct {
+	Test    string `json:"test" xml:"test"`
+	Reason  string `json:"reason" xml:"reason"`
+	Pattern string `json:"pattern,omitempty" xml:"pattern,omitempty"`
+}
+
+// Target describes a named catalog target so results keyed by address or URL
+// can be grouped by region.
+type Target struct {
+	Name    string   `json:"name" xml:"name"`
+	Address string   `json:"address" xml:"address"`
+	URL     string   `json:"url,omitempty" xml:"url,omitempty"`
+	Region  string   `json:"region,omitempty" xml:"region,omitempty"`
+	Stack   string   `json:"stack,omitempty" xml:"stack,omitempty"`
+	Tags    []string `json:"tags,omitempty" xml:"tags>tag,omitempty"`
+}
+
+// Event is one line of the NDJSON progress stream. Type is one of
+// run_start, test_start, test_progress, test_end, test_excluded, run_end or
+// error; the other fields are set when they apply to the type.
+type Event struct {
+	Type     string      `json:"type"`
+	Time     time.Time   `json:"time"`
+	Test     string      `json:"test,omitempty"`
+	Index    int         `json:"index,omitempty"`
+	Total    int         `json:"total,omitempty"`
+	Status   string      `json:"status,omitempty"`
+	Message  string      `json:"message,omitempty"`
+	Progress *Progress   `json:"progress,omitempty"`
+	Excluded *Exclusion  `json:"excluded,omitempty"`
+	Result   *TestResult `json:"result,omitempty"`
+	Run      *Result     `json:"run,omitempty"`
+	Outputs  []string    `json:"outputs,omitempty"`
+	ExitCode *int        `json:"exit_code,omitempty"`
+}
+
+// Progress is a measurement reported while a test runs: the share of the
+// test done, and the latest RTT sample or throughput where the test has one.
+type Progress struct {
+	Fraction float64 `json:"fraction,omitempty"`
+	Target   string  `json:"target,omitempty"`
+	RTTMs    float64 `json:"rtt_ms,omitempty"`
+	Lost     bool    `json:"lost,omitempty"`
+	DownBps  float64 `json:"down_bps,omitempty"`
+	UpBps    float64 `json:"up_bps,omitempty"`
 }
 
 type Summary struct {
@@ -27,6 +79,20 @@ type Environment struct {
 	Arch     string `json:"arch" xml:"arch"`
 	Hostname string `json:"hostname" xml:"hostname"`
 	Timezone string `json:"timezone" xml:"timezone"`
+	Link     *Link  `json:"link,omitempty" xml:"link,omitempty"`
+}
+
+// Link is the negotiated state of the interface carrying the default route.
+// Media is "ethernet" or "wireless"; Duplex ("full", "half") and Autoneg
+// ("on", "off") are empty when the source does not report them. Source names
+// where the values came from: sysfs, ethtool or netadapter.
+type Link struct {
+	Interface string  `json:"interface" xml:"interface"`
+	Media     string  `json:"media" xml:"media"`
+	SpeedMbps float64 `json:"speed_mbps,omitempty" xml:"speed_mbps,omitempty"`
+	Duplex    string  `json:"duplex,omitempty" xml:"duplex,omitempty"`
+	Autoneg   string  `json:"autoneg,omitempty" xml:"autoneg,omitempty"`
+	Source    string  `json:"source" xml:"source"`
 }
 
 type TestResult struct {
@@ -46,6 +112,7 @@ type Evidence struct {
 }
 
 type Finding struct {
+	ID       string `json:"id,omitempty" xml:"id,omitempty"`
 	Severity string `json:"severity" xml:"severity"`
 	Title    string `json:"title" xml:"title"`
 	Detail   string `json:"detail" xml:"detail"`
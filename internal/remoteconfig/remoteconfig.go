@@ -0,0 +1,271 @@
+// Package remoteconfig fetches a signed conncheck.yaml over HTTPS so a
+// helpdesk can change targets and thresholds without shipping new files.
+//
+// The config at URL is signed with ed25519; the detached signature is served
+// at URL + ".sig" as base64. Only configs signed by PublicKey, which is set at
+// build time, are accepted:
+//
+//	go build -ldflags "-X conncheck/internal/remoteconfig.PublicKey=<base64 key>" ./cmd/conncheck
+//
+// Each verified copy is cached, and the cache is used when the server cannot
+// be reached.
+package remoteconfig
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PublicKey is the base64 ed25519 key that remote configs must be signed
+// with. It is empty unless set with -ldflags -X, and then remote configs are
+// refused.
+var PublicKey string
+
+const (
+	SignatureSuffix = ".sig"
+
+	maxConfigBytes = 1 << 20
+	fetchTimeout   = 15 * time.Second
+	configName     = "config.yaml"
+)
+
+// ErrNoKey is returned when the binary was built without PublicKey.
+var ErrNoKey = errors.New("this build has no config signing key; remote configs are disabled")
+
+// IsURL reports whether a -config value names a remote config.
+func IsURL(value string) bool {
+	return strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://")
+}
+
+// Fetcher downloads and verifies remote configs. The zero value is not
+// usable; see New.
+type Fetcher struct {
+	Key      ed25519.PublicKey
+	CacheDir string
+	Client   *http.Client
+}
+
+// New returns a Fetcher using the embedded PublicKey and the user cache
+// directory.
+func New() (*Fetcher, error) {
+	key, err := ParsePublicKey(PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return &Fetcher{
+		Key:      key,
+		CacheDir: filepath.Join(cacheDir, "conncheck", "remote"),
+		Client:   &http.Client{Timeout: fetchTimeout},
+	}, nil
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key.
+func ParsePublicKey(value string) (ed25519.PublicKey, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, ErrNoKey
+	}
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("config signing key is not a base64 ed25519 public key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// Result says where a fetched config ended up.
+type Result struct {
+	Path      string    // verified local copy, ready for config.LoadLayered
+	FromCache bool      // the server could not be reached
+	FetchedAt time.Time // when the copy at Path was downloaded
+	FetchErr  error     // why the cache was used
+}
+
+// Fetch downloads rawURL and its signature, verifies them and refreshes the
+// cache. When the server cannot be reached or answers with a server error,
+// the cached copy is verified again and returned instead. A config that
+// arrives with a bad signature is an error, never a reason to fall back.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Result, error) {
+	if err := checkURL(rawURL); err != nil {
+		return Result{}, err
+	}
+	dir := filepath.Join(f.CacheDir, cacheKey(rawURL))
+
+	data, signature, err := f.download(ctx, rawURL)
+	if err != nil {
+		var verifyErr *verifyError
+		if errors.As(err, &verifyErr) {
+			return Result{}, err
+		}
+		cached, cacheErr := f.loadCache(dir)
+		if cacheErr != nil {
+			return Result{}, fmt.Errorf("%w (no usable cached copy: %v)", err, cacheErr)
+		}
+		cached.FromCache = true
+		cached.FetchErr = err
+		return cached, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Result{}, err
+	}
+	path := filepath.Join(dir, configName)
+	if err := writeAtomic(path+SignatureSuffix, []byte(base64.StdEncoding.EncodeToString(signature)+"\n")); err != nil {
+		return Result{}, err
+	}
+	if err := writeAtomic(path, data); err != nil {
+		return Result{}, err
+	}
+	if err := writeAtomic(filepath.Join(dir, "source.txt"), []byte(rawURL+"\n")); err != nil {
+		return Result{}, err
+	}
+	return Result{Path: path, FetchedAt: time.Now()}, nil
+}
+
+type verifyError struct {
+	msg string
+}
+
+func (e *verifyError) Error() string {
+	return e.msg
+}
+
+func (f *Fetcher) download(ctx context.Context, rawURL string) ([]byte, []byte, error) {
+	data, err := f.get(ctx, rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	sigData, err := f.get(ctx, rawURL+SignatureSuffix)
+	if err != nil {
+		return nil, nil, fmt.Errorf("signature: %w", err)
+	}
+	signature, err := DecodeSignature(sigData)
+	if err != nil {
+		return nil, nil, &verifyError{fmt.Sprintf("%s: %v", rawURL+SignatureSuffix, err)}
+	}
+	if !ed25519.Verify(f.Key, data, signature) {
+		return nil, nil, &verifyError{fmt.Sprintf("%s: signature does not match the embedded key", rawURL)}
+	}
+	return data, signature, nil
+}
+
+// get returns the body of rawURL. Client errors (4xx) are reported as
+// verification failures so a missing signature is not papered over by the
+// cache.
+func (f *Fetcher) get(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return nil, fmt.Errorf("%s: server answered %s", rawURL, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &verifyError{fmt.Sprintf("%s: server answered %s", rawURL, resp.Status)}
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxConfigBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxConfigBytes {
+		return nil, &verifyError{fmt.Sprintf("%s: larger than %d bytes", rawURL, maxConfigBytes)}
+	}
+	return data, nil
+}
+
+func (f *Fetcher) loadCache(dir string) (Result, error) {
+	path := filepath.Join(dir, configName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Result{}, err
+	}
+	sigData, err := os.ReadFile(path + SignatureSuffix)
+	if err != nil {
+		return Result{}, err
+	}
+	signature, err := DecodeSignature(sigData)
+	if err != nil {
+		return Result{}, err
+	}
+	if !ed25519.Verify(f.Key, data, signature) {
+		return Result{}, fmt.Errorf("cached copy %s does not match the embedded key", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{Path: path, FetchedAt: info.ModTime()}, nil
+}
+
+// DecodeSignature accepts a base64 signature, as written by Sign, or the raw
+// 64 bytes.
+func DecodeSignature(data []byte) ([]byte, error) {
+	if len(data) == ed25519.SignatureSize {
+		return data, nil
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("not an ed25519 signature")
+	}
+	return signature, nil
+}
+
+// Sign returns the base64 signature file contents for data.
+func Sign(key ed25519.PrivateKey, data []byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)) + "\n")
+}
+
+// checkURL allows https everywhere and plain http only on loopback, which is
+// enough for a local stand-in server.
+func checkURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("%q is not a valid config URL", rawURL)
+	}
+	switch parsed.Scheme {
+	case "https":
+		return nil
+	case "http":
+		host := parsed.Hostname()
+		if ip := net.ParseIP(host); (ip != nil && ip.IsLoopback()) || host == "localhost" {
+			return nil
+		}
+		return fmt.Errorf("%s: remote configs must use https (plain http is only allowed on loopback)", rawURL)
+	default:
+		return fmt.Errorf("%q is not an http(s) URL", rawURL)
+	}
+}
+
+func cacheKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:8])
+}
+
+func writeAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
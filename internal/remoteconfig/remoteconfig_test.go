@@ -0,0 +1,212 @@
+package remoteconfig
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const configBody = "targets:\n  ping_targets: [1.1.1.1]\n"
+
+// standIn serves a config and its signature on loopback. Status, when set,
+// replaces every answer.
+type standIn struct {
+	mu        sync.Mutex
+	config    []byte
+	signature []byte
+	status    int
+}
+
+func (s *standIn) set(config, signature []byte, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config, s.signature, s.status = config, signature, status
+}
+
+func (s *standIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+		return
+	}
+	switch r.URL.Path {
+	case "/conncheck.yaml":
+		w.Write(s.config)
+	case "/conncheck.yaml" + SignatureSuffix:
+		w.Write(s.signature)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newFetcher(t *testing.T) (*Fetcher, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Fetcher{Key: public, CacheDir: t.TempDir(), Client: http.DefaultClient}, private
+}
+
+func TestFetchValidSignatureIsCached(t *testing.T) {
+	fetcher, key := newFetcher(t)
+	server := &standIn{}
+	server.set([]byte(configBody), Sign(key, []byte(configBody)), 0)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	result, err := fetcher.Fetch(context.Background(), ts.URL+"/conncheck.yaml")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if result.FromCache {
+		t.Error("FromCache = true for a fresh download")
+	}
+	data, err := os.ReadFile(result.Path)
+	if err != nil {
+		t.Fatalf("reading cached copy: %v", err)
+	}
+	if string(data) != configBody {
+		t.Errorf("cached copy = %q, want %q", data, configBody)
+	}
+	if _, err := os.Stat(result.Path + SignatureSuffix); err != nil {
+		t.Errorf("cached signature: %v", err)
+	}
+}
+
+func TestFetchBadSignatureKeepsCache(t *testing.T) {
+	fetcher, key := newFetcher(t)
+	server := &standIn{}
+	server.set([]byte(configBody), Sign(key, []byte(configBody)), 0)
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	configURL := ts.URL + "/conncheck.yaml"
+
+	first, err := fetcher.Fetch(context.Background(), configURL)
+	if err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := []byte(configBody + "mode: quick\n")
+	tests := []struct {
+		name      string
+		config    []byte
+		signature []byte
+	}{
+		{name: "body changed after signing", config: tampered, signature: Sign(key, []byte(configBody))},
+		{name: "signed by another key", config: tampered, signature: Sign(otherKey, tampered)},
+		{name: "signature not base64", config: tampered, signature: []byte("not a signature")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.set(tt.config, tt.signature, 0)
+			if _, err := fetcher.Fetch(context.Background(), configURL); err == nil {
+				t.Fatal("Fetch accepted a bad signature")
+			}
+			data, err := os.ReadFile(first.Path)
+			if err != nil {
+				t.Fatalf("reading cached copy: %v", err)
+			}
+			if string(data) != configBody {
+				t.Errorf("cache was overwritten with %q", data)
+			}
+		})
+	}
+}
+
+func TestFetchFallsBackToCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		offline func(server *standIn, ts *httptest.Server)
+	}{
+		{name: "server error", offline: func(server *standIn, _ *httptest.Server) { server.set(nil, nil, http.StatusServiceUnavailable) }},
+		{name: "connection refused", offline: func(_ *standIn, ts *httptest.Server) { ts.Close() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher, key := newFetcher(t)
+			server := &standIn{}
+			server.set([]byte(configBody), Sign(key, []byte(configBody)), 0)
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+			configURL := ts.URL + "/conncheck.yaml"
+
+			if _, err := fetcher.Fetch(context.Background(), configURL); err != nil {
+				t.Fatalf("first Fetch: %v", err)
+			}
+			tt.offline(server, ts)
+
+			result, err := fetcher.Fetch(context.Background(), configURL)
+			if err != nil {
+				t.Fatalf("Fetch with the server down: %v", err)
+			}
+			if !result.FromCache || result.FetchErr == nil {
+				t.Errorf("FromCache = %v, FetchErr = %v; want the cached copy and the reason", result.FromCache, result.FetchErr)
+			}
+			data, err := os.ReadFile(result.Path)
+			if err != nil || string(data) != configBody {
+				t.Errorf("cached copy = %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestFetchOfflineWithoutCache(t *testing.T) {
+	fetcher, _ := newFetcher(t)
+	ts := httptest.NewServer(http.NotFoundHandler())
+	configURL := ts.URL + "/conncheck.yaml"
+	ts.Close()
+
+	_, err := fetcher.Fetch(context.Background(), configURL)
+	if err == nil {
+		t.Fatal("Fetch succeeded with no server and no cache")
+	}
+	if !strings.Contains(err.Error(), "no usable cached copy") {
+		t.Errorf("error = %v, want it to mention the missing cache", err)
+	}
+}
+
+func TestFetchRefusesPlainHTTPOffLoopback(t *testing.T) {
+	fetcher, _ := newFetcher(t)
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{url: "http://config.example.com/conncheck.yaml"},
+		{url: "http://192.0.2.10/conncheck.yaml"},
+		{url: "ftp://127.0.0.1/conncheck.yaml"},
+		{url: "https:///conncheck.yaml"},
+		{url: "http://127.0.0.1:1/conncheck.yaml", allowed: true},
+		{url: "http://localhost:1/conncheck.yaml", allowed: true},
+		{url: "http://[::1]:1/conncheck.yaml", allowed: true},
+		{url: "https://config.example.com/conncheck.yaml", allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if err := checkURL(tt.url); (err == nil) != tt.allowed {
+				t.Errorf("checkURL(%q) = %v, allowed %v", tt.url, err, tt.allowed)
+			}
+		})
+	}
+
+	// A refused URL never reaches the network or the cache.
+	_, err := fetcher.Fetch(context.Background(), "http://config.example.com/conncheck.yaml")
+	if err == nil || !strings.Contains(err.Error(), "must use https") {
+		t.Errorf("Fetch error = %v, want an https error", err)
+	}
+	entries, _ := os.ReadDir(fetcher.CacheDir)
+	if len(entries) != 0 {
+		t.Errorf("cache has %d entries after a refused URL", len(entries))
+	}
+}
@@ -0,0 +1,272 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"conncheck/internal/model"
+	"conncheck/internal/timeseries"
+)
+
+// csvTable is one CSV file. Headers are part of the output contract: add
+// columns at the end, never rename or reorder them.
+type csvTable struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+// WriteCSV writes one CSV file per table into outDir/csv and returns their
+// paths. Column names carry their unit (_ms, _bps, _bytes, _pct).
+func WriteCSV(outDir string, result model.Result) ([]string, error) {
+	dir := filepath.Join(outDir, "csv")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	points := timeseries.Collect(result)
+	tables := []csvTable{
+		csvTestSummary(result),
+		csvLatencyTargets(result),
+		csvDNSResolvers(result),
+		csvPMTU(result),
+		csvSpeedtestRuns(points),
+		csvLatencySamples(points),
+	}
+	paths := make([]string, 0, len(tables))
+	for _, table := range tables {
+		path := filepath.Join(dir, table.name+".csv")
+		if err := writeCSVFile(path, table); err != nil {
+			return paths, fmt.Errorf("%s: %w", table.name, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func writeCSVFile(path string, table csvTable) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(file)
+	if err := writer.Write(table.header); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.WriteAll(table.rows); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func csvTestSummary(result model.Result) csvTable {
+	table := csvTable{
+		name:   "tests",
+		header: []string{"test", "status", "started_at", "ended_at", "duration_ms", "findings_info", "findings_warn", "findings_fail", "evidence_count"},
+	}
+	for _, test := range result.Tests {
+		counts := map[string]int{}
+		for _, finding := range test.Findings {
+			counts[finding.Severity]++
+		}
+		table.rows = append(table.rows, []string{
+			test.Name,
+			test.Status,
+			csvTime(test.StartedAt),
+			csvTime(test.EndedAt),
+			strconv.FormatInt(test.EndedAt.Sub(test.StartedAt).Milliseconds(), 10),
+			strconv.Itoa(counts["INFO"]),
+			strconv.Itoa(counts["WARN"]),
+			strconv.Itoa(counts["FAIL"]),
+			strconv.Itoa(len(test.Evidence)),
+		})
+	}
+	return table
+}
+
+func csvLatencyTargets(result model.Result) csvTable {
+	table := csvTable{
+		name:   "latency_targets",
+		header: []string{"target", "min_ms", "avg_ms", "max_ms", "loss_pct", "target_name", "region"},
+	}
+	test := findTest(result, "latency")
+	if test == nil {
+		return table
+	}
+	for _, key := range sortedMetricKeys(test.Metrics, "latency_series.") {
+		target := strings.TrimPrefix(key, "latency_series.")
+		catalog := catalogTarget(result, target)
+		table.rows = append(table.rows, []string{
+			target,
+			test.Metrics[target+"_min_ms"],
+			test.Metrics[target+"_avg_ms"],
+			test.Metrics[target+"_max_ms"],
+			test.Metrics[target+"_loss_pct"],
+			catalog.Name,
+			catalog.Region,
+		})
+	}
+	return table
+}
+
+// catalogTarget returns the catalog entry for an address or URL, or a zero
+// Target when it is not in the catalog.
+func catalogTarget(result model.Result, key string) model.Target {
+	for _, target := range result.Targets {
+		if target.Address == key || target.URL == key {
+			return target
+		}
+	}
+	return model.Target{}
+}
+
+func csvDNSResolvers(result model.Result) csvTable {
+	table := csvTable{
+		name:   "dns_resolvers",
+		header: []string{"resolver", "avg_ms", "success_count", "fail_count"},
+	}
+	test := findTest(result, "dns_benchmark")
+	if test == nil {
+		return table
+	}
+	for _, key := range sortedMetricKeys(test.Metrics, "dns_success.") {
+		resolver := strings.TrimPrefix(key, "dns_success.")
+		table.rows = append(table.rows, []string{
+			resolver,
+			test.Metrics["dns_avg_ms."+resolver],
+			test.Metrics["dns_success."+resolver],
+			test.Metrics["dns_fail."+resolver],
+		})
+	}
+	return table
+}
+
+func csvPMTU(result model.Result) csvTable {
+	table := csvTable{
+		name:   "pmtu",
+		header: []string{"target", "stack", "pmtu_bytes", "frag_needed", "blackhole", "target_name", "region"},
+	}
+	test := findTest(result, "mtu_pmtu")
+	if test == nil {
+		return table
+	}
+	pmtu := map[string]int{}
+	for _, detail := range splitDetails(test.Metrics["pmtu_details"]) {
+		pmtu[detail.Target+"/"+detail.Stack] = detail.Value
+	}
+	for _, tested := range strings.Split(test.Metrics["pmtu_targets_tested"], ",") {
+		target, stack, ok := strings.Cut(strings.TrimSpace(tested), "/")
+		if !ok {
+			continue
+		}
+		prefix := fmt.Sprintf("pmtu_%s_%s", metricKeySafe(target), stack)
+		value := ""
+		if mtu, ok := pmtu[target+"/"+stack]; ok {
+			value = strconv.Itoa(mtu)
+		}
+		blackhole := "no"
+		if test.Metrics[prefix+"_blackhole"] == "probable" {
+			blackhole = "probable"
+		}
+		catalog := catalogTarget(result, target)
+		table.rows = append(table.rows, []string{target, stack, value, test.Metrics[prefix+"_frag_needed"], blackhole, catalog.Name, catalog.Region})
+	}
+	return table
+}
+
+func csvSpeedtestRuns(points []timeseries.Point) csvTable {
+	table := csvTable{
+		name:   "speedtest_runs",
+		header: []string{"started_at", "category", "server_id", "server_name", "run", "ping_ms", "download_bps", "upload_bps"},
+	}
+	for _, point := range points {
+		if point.Measurement != timeseries.MeasurementSpeedtest {
+			continue
+		}
+		table.rows = append(table.rows, []string{
+			csvTime(point.Time),
+			point.Tags["category"],
+			point.Tags["server_id"],
+			point.Tags["server_name"],
+			point.Tags["run"],
+			csvField(point.Fields["ping_ms"]),
+			csvField(point.Fields["download_bps"]),
+			csvField(point.Fields["upload_bps"]),
+		})
+	}
+	return table
+}
+
+// csvLatencySamples lists every latency probe, from the latency test and the
+// bufferbloat phases. Lost probes have an empty rtt_ms.
+func csvLatencySamples(points []timeseries.Point) csvTable {
+	table := csvTable{
+		name:   "latency_samples",
+		header: []string{"timestamp", "test", "target", "phase", "rtt_ms", "loss"},
+	}
+	for _, point := range points {
+		if point.Measurement != timeseries.MeasurementLatency {
+			continue
+		}
+		table.rows = append(table.rows, []string{
+			csvTime(point.Time),
+			point.Tags["test"],
+			point.Tags["target"],
+			point.Tags["phase"],
+			csvField(point.Fields["rtt_ms"]),
+			csvField(point.Fields["loss"]),
+		})
+	}
+	return table
+}
+
+func sortedMetricKeys(metrics model.StringMap, prefix string) []string {
+	var keys []string
+	for key := range metrics {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// metricKeySafe mirrors how the MTU test turns a target into a metric key.
+func metricKeySafe(target string) string {
+	var b strings.Builder
+	for _, r := range target {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteRune('_')
+	}
+	return b.String()
+}
+
+func csvTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format("2006-01-02T15:04:05.000Z")
+}
+
+func csvField(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
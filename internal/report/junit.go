@@ -0,0 +1,192 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"conncheck/internal/model"
+)
+
+// JUnit WARN mappings, selected by junit.warn_as in conncheck.yaml.
+const (
+	WarnAsPass    = "pass"
+	WarnAsFailure = "failure"
+	WarnAsSkipped = "skipped"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Hostname   string          `xml:"hostname,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitText struct {
+	Body string `xml:",cdata"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",cdata"`
+}
+
+// WriteJUnit writes junit.xml into outDir. FAIL maps to <failure>, SKIPPED to
+// <skipped>, and WARN according to warnAs (pass when empty).
+func WriteJUnit(outDir string, result model.Result, warnAs string) (string, error) {
+	suites, err := buildJUnit(result, warnAs)
+	if err != nil {
+		return "", err
+	}
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(outDir, "junit.xml")
+	return path, os.WriteFile(path, append([]byte(xml.Header), data...), 0o644)
+}
+
+func buildJUnit(result model.Result, warnAs string) (junitSuites, error) {
+	switch warnAs {
+	case "":
+		warnAs = WarnAsPass
+	case WarnAsPass, WarnAsFailure, WarnAsSkipped:
+	default:
+		return junitSuites{}, fmt.Errorf("junit.warn_as: unknown value %q (use pass, failure or skipped)", warnAs)
+	}
+
+	suite := junitSuite{
+		Name:     "conncheck",
+		Time:     seconds(result.FinishedAt.Sub(result.StartedAt).Seconds()),
+		Hostname: result.Environment.Hostname,
+		Properties: []junitProperty{
+			{Name: "version", Value: result.Version},
+			{Name: "os", Value: result.Environment.OS},
+			{Name: "arch", Value: result.Environment.Arch},
+			{Name: "warn_as", Value: warnAs},
+		},
+	}
+	if !result.StartedAt.IsZero() {
+		suite.Timestamp = result.StartedAt.Format("2006-01-02T15:04:05")
+	}
+	for _, test := range result.Tests {
+		testCase := junitCase{
+			ClassName: "conncheck",
+			Name:      test.Name,
+			Time:      seconds(test.EndedAt.Sub(test.StartedAt).Seconds()),
+			SystemOut: &junitText{Body: junitSystemOut(test)},
+		}
+		status := test.Status
+		if status == "WARN" {
+			switch warnAs {
+			case WarnAsFailure:
+				status = "FAIL"
+			case WarnAsSkipped:
+				status = "SKIPPED"
+			}
+		}
+		switch status {
+		case "FAIL":
+			testCase.Failure = &junitMessage{Message: junitHeadline(test), Type: test.Status, Body: junitFindings(test)}
+			suite.Failures++
+		case "SKIPPED":
+			testCase.Skipped = &junitMessage{Message: junitHeadline(test)}
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Tests = len(suite.Cases)
+
+	return junitSuites{
+		Name:     "conncheck",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}, nil
+}
+
+// junitHeadline uses the first finding that matches the test status, which
+// is what CI systems show in their summary line.
+func junitHeadline(test model.TestResult) string {
+	for _, finding := range test.Findings {
+		if finding.Severity == test.Status {
+			return finding.Title
+		}
+	}
+	if len(test.Findings) > 0 {
+		return test.Findings[0].Title
+	}
+	return test.Status
+}
+
+func junitFindings(test model.TestResult) string {
+	var lines []string
+	for _, finding := range test.Findings {
+		lines = append(lines, fmt.Sprintf("[%s] %s: %s", finding.Severity, finding.Title, finding.Detail))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func junitSystemOut(test model.TestResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "status: %s\n", test.Status)
+	if findings := junitFindings(test); findings != "" {
+		b.WriteString("findings:\n")
+		b.WriteString(findings)
+		b.WriteByte('\n')
+	}
+	if len(test.Evidence) > 0 {
+		b.WriteString("evidence:\n")
+		for _, evidence := range test.Evidence {
+			line := fmt.Sprintf("%s: %s", evidence.Label, evidence.Path)
+			if evidence.Note != "" {
+				line += " (" + evidence.Note + ")"
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
+
+func seconds(value float64) string {
+	if value < 0 {
+		value = 0
+	}
+	return fmt.Sprintf("%.3f", value)
+}
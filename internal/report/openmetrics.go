@@ -0,0 +1,391 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"conncheck/internal/model"
+)
+
+const (
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	PrometheusContentType  = "text/plain; version=0.0.4; charset=utf-8"
+
+	metricsFilename = "metrics.prom"
+	metricPrefix    = "conncheck_"
+)
+
+// WriteOpenMetrics writes metrics.prom into outDir. The file uses the
+// OpenMetrics text format, which the node_exporter textfile collector also
+// accepts.
+func WriteOpenMetrics(outDir string, result model.Result) (string, error) {
+	path := filepath.Join(outDir, metricsFilename)
+	return path, WriteOpenMetricsFile(path, result)
+}
+
+// WriteOpenMetricsFile writes the metrics to path atomically so a collector
+// never reads a partially written file.
+func WriteOpenMetricsFile(path string, result model.Result) error {
+	var buf bytes.Buffer
+	if err := FormatOpenMetrics(&buf, result); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// FormatOpenMetrics renders result as OpenMetrics text exposition.
+func FormatOpenMetrics(w io.Writer, result model.Result) error {
+	set := newMetricSet()
+	collectRunMetrics(set, result)
+	for _, test := range result.Tests {
+		collectTestMetrics(set, test)
+	}
+	return set.write(w)
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	unit    string
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels [][2]string
+	value  float64
+}
+
+type metricSet struct {
+	families map[string]*metricFamily
+}
+
+func newMetricSet() *metricSet {
+	return &metricSet{families: map[string]*metricFamily{}}
+}
+
+// gauge records a sample. name excludes the conncheck_ prefix; labels are
+// given as alternating key/value pairs.
+func (s *metricSet) gauge(name, unit, help string, value float64, labels ...string) {
+	family, ok := s.families[name]
+	if !ok {
+		family = &metricFamily{name: metricPrefix + name, help: help, unit: unit}
+		s.families[name] = family
+	}
+	sample := metricSample{value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		sample.labels = append(sample.labels, [2]string{labels[i], labels[i+1]})
+	}
+	family.samples = append(family.samples, sample)
+}
+
+func (s *metricSet) write(w io.Writer) error {
+	names := make([]string, 0, len(s.families))
+	for name := range s.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		family := s.families[name]
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", family.name)
+		if family.unit != "" {
+			fmt.Fprintf(&b, "# UNIT %s %s\n", family.name, family.unit)
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n", family.name, escapeHelp(family.help))
+		sort.SliceStable(family.samples, func(i, j int) bool {
+			return labelKey(family.samples[i].labels) < labelKey(family.samples[j].labels)
+		})
+		for _, sample := range family.samples {
+			b.WriteString(family.name)
+			if len(sample.labels) > 0 {
+				b.WriteByte('{')
+				for i, label := range sample.labels {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					fmt.Fprintf(&b, "%s=\"%s\"", label[0], escapeLabel(label[1]))
+				}
+				b.WriteByte('}')
+			}
+			b.WriteByte(' ')
+			b.WriteString(formatMetricValue(sample.value))
+			b.WriteByte('\n')
+		}
+	}
+	b.WriteString("# EOF\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func labelKey(labels [][2]string) string {
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		parts = append(parts, label[0]+"="+label[1])
+	}
+	return strings.Join(parts, ",")
+}
+
+func escapeLabel(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
+
+func escapeHelp(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
+
+func formatMetricValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var testStatuses = []string{"OK", "WARN", "FAIL", "SKIPPED"}
+
+func collectRunMetrics(set *metricSet, result model.Result) {
+	set.gauge("info", "", "Conncheck build information.", 1, "version", result.Version)
+	if !result.FinishedAt.IsZero() {
+		set.gauge("run_timestamp_seconds", "seconds", "Unix time the run finished.", float64(result.FinishedAt.UnixNano())/1e9)
+		set.gauge("run_duration_seconds", "seconds", "Wall-clock duration of the run.", result.FinishedAt.Sub(result.StartedAt).Seconds())
+	}
+	severities := map[string]int{}
+	for _, finding := range result.Findings {
+		severities[finding.Severity]++
+	}
+	for _, severity := range []string{"INFO", "WARN", "FAIL"} {
+		set.gauge("findings", "", "Number of findings by severity.", float64(severities[severity]), "severity", severity)
+	}
+	if link := result.Environment.Link; link != nil && link.SpeedMbps > 0 {
+		set.gauge("link_speed_bits_per_second", "bits_per_second", "Negotiated speed of the interface carrying the default route.", link.SpeedMbps*1_000_000, "interface", link.Interface, "media", link.Media, "duplex", link.Duplex)
+	}
+}
+
+func collectTestMetrics(set *metricSet, test model.TestResult) {
+	for _, status := range testStatuses {
+		value := 0.0
+		if test.Status == status {
+			value = 1
+		}
+		set.gauge("test_status", "", "Test status as a state set; 1 marks the current status.", value, "test", test.Name, "status", status)
+	}
+	if !test.EndedAt.IsZero() {
+		set.gauge("test_duration_seconds", "seconds", "Duration of each test.", test.EndedAt.Sub(test.StartedAt).Seconds(), "test", test.Name)
+	}
+
+	switch test.Name {
+	case "lan_health":
+		collectGatewayMetrics(set, test.Metrics)
+	case "wifi":
+		collectWiFiMetrics(set, test.Metrics)
+	case "dualstack":
+		for _, stack := range []struct{ key, label string }{{"ipv4_reach", "ipv4"}, {"ipv6_reach", "ipv6"}, {"dualstack_reach", "dual"}} {
+			if value, ok := test.Metrics[stack.key]; ok {
+				set.gauge("reachable", "", "Whether the stack's reference target answered (1) or not (0).", boolMetric(value), "stack", stack.label)
+			}
+		}
+	case "dns_benchmark":
+		collectDNSMetrics(set, test.Metrics)
+	case "mtu_pmtu":
+		collectMTUMetrics(set, test.Metrics)
+	case "latency":
+		collectLatencyMetrics(set, test.Metrics)
+	case "bufferbloat":
+		collectBufferbloatMetrics(set, test.Metrics)
+	case "speedtest":
+		collectSpeedtestMetrics(set, test.Metrics)
+	case "traceroute":
+		for key, value := range test.Metrics {
+			if target, ok := strings.CutSuffix(key, "_hops"); ok {
+				if hops, err := strconv.Atoi(value); err == nil {
+					set.gauge("traceroute_hops", "", "Number of hops reported by traceroute.", float64(hops), "target", target)
+				}
+			}
+		}
+	case "http_check":
+		collectHTTPMetrics(set, test.Metrics)
+	}
+}
+
+func collectGatewayMetrics(set *metricSet, metrics model.StringMap) {
+	gateway := metrics["gateway"]
+	if gateway == "" {
+		return
+	}
+	for _, stat := range []string{"min", "avg", "max", "p50", "p95", "p99"} {
+		if value, ok := metricFloat(metrics, stat+"_ms"); ok {
+			set.gauge("gateway_rtt_seconds", "seconds", "Round-trip time to the default gateway.", value/1000, "target", gateway, "stat", stat)
+		}
+	}
+	if value, ok := metricFloat(metrics, "jitter_ms"); ok {
+		set.gauge("gateway_jitter_seconds", "seconds", "RFC 3550 jitter of the round-trip time to the default gateway.", value/1000, "target", gateway)
+	}
+	if value, ok := metricFloat(metrics, "loss_pct"); ok {
+		set.gauge("gateway_loss_ratio", "ratio", "Packet loss towards the default gateway.", value/100, "target", gateway)
+	}
+	if value, ok := metricFloat(metrics, "max_loss_burst"); ok {
+		set.gauge("gateway_loss_burst_packets", "packets", "Longest run of consecutive lost requests to the default gateway.", value, "target", gateway)
+	}
+}
+
+func collectWiFiMetrics(set *metricSet, metrics model.StringMap) {
+	iface := metrics["interface"]
+	if iface == "" {
+		return
+	}
+	if value, ok := metricFloat(metrics, "signal_dbm"); ok {
+		set.gauge("wifi_signal_dbm", "dbm", "Signal level of the Wi-Fi link.", value, "interface", iface, "band", metrics["band_ghz"], "channel", metrics["channel"])
+	}
+	if value, ok := metricFloat(metrics, "noise_dbm"); ok {
+		set.gauge("wifi_noise_dbm", "dbm", "Noise floor of the Wi-Fi channel.", value, "interface", iface)
+	}
+	for _, direction := range []string{"rx", "tx"} {
+		if value, ok := metricFloat(metrics, direction+"_rate_mbps"); ok {
+			set.gauge("wifi_phy_rate_bits_per_second", "bits_per_second", "PHY rate of the Wi-Fi link.", value*1_000_000, "interface", iface, "direction", direction)
+		}
+	}
+	if value, ok := metricFloat(metrics, "congestion_score"); ok {
+		set.gauge("wifi_congestion_score", "", "Congestion score (0-100) of the current Wi-Fi channel, from the optional scan.", value, "interface", iface, "channel", metrics["channel"])
+	}
+	if value, ok := metricFloat(metrics, "overlapping_networks"); ok {
+		set.gauge("wifi_overlapping_networks", "", "Networks overlapping the current Wi-Fi channel, from the optional scan.", value, "interface", iface, "channel", metrics["channel"])
+	}
+}
+
+func collectDNSMetrics(set *metricSet, metrics model.StringMap) {
+	for key, value := range metrics {
+		switch {
+		case strings.HasPrefix(key, "dns_avg_ms."):
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				set.gauge("dns_query_duration_seconds", "seconds", "Average DNS query duration per resolver.", parsed/1000, "resolver", strings.TrimPrefix(key, "dns_avg_ms."))
+			}
+		case strings.HasPrefix(key, "dns_success."):
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				set.gauge("dns_queries", "", "DNS queries per resolver and outcome.", parsed, "resolver", strings.TrimPrefix(key, "dns_success."), "result", "success")
+			}
+		case strings.HasPrefix(key, "dns_fail."):
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				set.gauge("dns_queries", "", "DNS queries per resolver and outcome.", parsed, "resolver", strings.TrimPrefix(key, "dns_fail."), "result", "fail")
+			}
+		}
+	}
+}
+
+func collectMTUMetrics(set *metricSet, metrics model.StringMap) {
+	for _, detail := range splitDetails(metrics["pmtu_details"]) {
+		set.gauge("pmtu_bytes", "bytes", "Path MTU discovered per target and stack.", float64(detail.Value), "target", detail.Target, "stack", detail.Stack)
+	}
+	if value, ok := metricFloat(metrics, "local_mtu"); ok {
+		set.gauge("interface_mtu_bytes", "bytes", "MTU of the active interface.", value, "interface", metrics["local_interface"])
+	}
+	if value, ok := metricFloat(metrics, "mss_observed"); ok {
+		set.gauge("tcp_mss_bytes", "bytes", "TCP MSS observed on an outbound connection.", value)
+	}
+	if blackhole, ok := metrics["blackhole_mtu"]; ok {
+		set.gauge("pmtu_blackhole", "", "Whether a PMTU blackhole is probable (1) or not (0).", boolMetric(strconv.FormatBool(blackhole == "probable")))
+	}
+}
+
+func collectLatencyMetrics(set *metricSet, metrics model.StringMap) {
+	for key := range metrics {
+		target, ok := strings.CutPrefix(key, "latency_series.")
+		if !ok {
+			continue
+		}
+		for _, stat := range []string{"min", "avg", "max"} {
+			if value, ok := metricFloat(metrics, fmt.Sprintf("%s_%s_ms", target, stat)); ok {
+				set.gauge("latency_rtt_seconds", "seconds", "Round-trip time per target.", value/1000, "target", target, "stat", stat)
+			}
+		}
+		if value, ok := metricFloat(metrics, target+"_loss_pct"); ok {
+			set.gauge("latency_loss_ratio", "ratio", "Packet loss per target.", value/100, "target", target)
+		}
+		var samples []latencySampleView
+		if err := json.Unmarshal([]byte(metrics[key]), &samples); err == nil {
+			set.gauge("latency_samples", "", "Number of latency probes sent per target.", float64(len(samples)), "target", target)
+		}
+	}
+}
+
+func collectBufferbloatMetrics(set *metricSet, metrics model.StringMap) {
+	target := metrics["target"]
+	for _, phase := range []string{"idle", "download", "upload"} {
+		for _, stat := range []string{"avg", "p95"} {
+			if value, ok := metricFloat(metrics, fmt.Sprintf("%s_%s_ms", phase, stat)); ok {
+				set.gauge("bufferbloat_rtt_seconds", "seconds", "Latency while idle and under load.", value/1000, "target", target, "phase", phase, "stat", stat)
+			}
+		}
+		if value, ok := metricFloat(metrics, phase+"_mbps"); ok {
+			set.gauge("bufferbloat_throughput_bits_per_second", "bits_per_second", "Throughput achieved by the load generator.", value*1_000_000, "phase", phase)
+		}
+	}
+}
+
+func collectSpeedtestMetrics(set *metricSet, metrics model.StringMap) {
+	for _, category := range []string{"local", "national", "eu", "us"} {
+		if value, ok := metricFloat(metrics, category+"_avg_down_bps"); ok {
+			set.gauge("speedtest_download_bits_per_second", "bits_per_second", "Average speedtest download per server category.", value, "category", category)
+		}
+		if value, ok := metricFloat(metrics, category+"_avg_up_bps"); ok {
+			set.gauge("speedtest_upload_bits_per_second", "bits_per_second", "Average speedtest upload per server category.", value, "category", category)
+		}
+		if value, ok := metricFloat(metrics, category+"_avg_ping_ms"); ok {
+			set.gauge("speedtest_latency_seconds", "seconds", "Average speedtest idle latency per server category.", value/1000, "category", category)
+		}
+	}
+	if value, ok := metricFloat(metrics, "score_total_bps"); ok {
+		set.gauge("speedtest_score_bits_per_second", "bits_per_second", "Distance-weighted speedtest score.", value)
+	}
+}
+
+func collectHTTPMetrics(set *metricSet, metrics model.StringMap) {
+	phases := []struct{ prefix, phase string }{
+		{"http_dns_ms.", "dns"},
+		{"http_connect_ms.", "connect"},
+		{"http_tls_ms.", "tls"},
+		{"http_ttfb_ms.", "ttfb"},
+		{"http_total_ms.", "total"},
+	}
+	for key, value := range metrics {
+		for _, phase := range phases {
+			endpoint, ok := strings.CutPrefix(key, phase.prefix)
+			if !ok {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				set.gauge("http_phase_seconds", "seconds", "HTTP request timing per endpoint and phase.", parsed/1000, "endpoint", endpoint, "phase", phase.phase)
+			}
+		}
+		if endpoint, ok := strings.CutPrefix(key, "http_status."); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				set.gauge("http_status_code", "", "HTTP status code per endpoint.", parsed, "endpoint", endpoint)
+			}
+		}
+	}
+}
+
+func boolMetric(value string) float64 {
+	if value == "true" {
+		return 1
+	}
+	return 0
+}
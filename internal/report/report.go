@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"conncheck/internal/config"
+	"conncheck/internal/i18n"
 	"conncheck/internal/model"
 )
 
@@ -39,6 +40,10 @@ func WriteHTML(outDir string, result model.Result, cfg config.Config) (string, e
 		"mulPercent": mulPercent,
 		"safeID":     safeID,
 		"toJSON":     toJSON,
+		"t":          i18n.T,
+		"label":      i18n.Label,
+		"lang":       i18n.Language,
+		"excluded":   ExclusionReason,
 	}).Parse(htmlTemplate))
 	path := filepath.Join(outDir, "report.html")
 	file, err := os.Create(path)
@@ -53,6 +58,13 @@ func WriteHTML(outDir string, result model.Result, cfg config.Config) (string, e
 		DNS:       buildDNSView(result),
 		MTU:       buildMTUView(result),
 		Latency:   buildLatencyView(result),
+		Trace:     buildTracerouteView(result),
+		DualStack: buildDualStackView(result),
+		Gateway:   buildGatewayView(result),
+		WiFi:      buildWiFiView(result),
+		HTTP:      buildHTTPView(result),
+		Bloat:     buildBufferbloatView(result),
+		Regions:   buildRegionView(result),
 	}
 	if err := tpl.Execute(file, view); err != nil {
 		return "", err
@@ -66,6 +78,13 @@ type reportView struct {
 	DNS       *dnsBenchView
 	MTU       *mtuView
 	Latency   *latencyView
+	Trace     *tracerouteView
+	DualStack *dualStackView
+	Gateway   *gatewayView
+	WiFi      *wifiView
+	HTTP      *httpView
+	Bloat     *bufferbloatView
+	Regions   *regionView
 }
 
 type speedtestView struct {
@@ -220,11 +239,19 @@ func metricInt(metrics model.StringMap, key string) (int, bool) {
 func toScaleView(scales []config.SpeedtestScale) []speedtestScaleView {
 	views := make([]speedtestScaleView, 0, len(scales))
 	for _, scale := range scales {
+		label := scale.Label
+		if label == "" && scale.Key != "" {
+			label = i18n.T(scale.Key)
+		}
+		description := scale.Description
+		if description == "" && scale.Key != "" {
+			description = i18n.T(scale.Key + ".desc")
+		}
 		views = append(views, speedtestScaleView{
 			MinMbps:     scale.MinMbps,
 			MaxMbps:     scale.MaxMbps,
-			Label:       scale.Label,
-			Description: scale.Description,
+			Label:       label,
+			Description: description,
 		})
 	}
 	return views
@@ -266,9 +293,9 @@ func buildComparisons(localMbps float64, metrics model.StringMap, cfg config.Spe
 		metricKey   string
 		fallbackPct float64
 	}{
-		{label: "Nazionali", metricKey: "national_avg_down_bps", fallbackPct: cfg.NationalPct},
-		{label: "Europei", metricKey: "eu_avg_down_bps", fallbackPct: cfg.EUPct},
-		{label: "USA", metricKey: "us_avg_down_bps", fallbackPct: cfg.USPct},
+		{label: i18n.T("report.speedtest.cmp.national"), metricKey: "national_avg_down_bps", fallbackPct: cfg.NationalPct},
+		{label: i18n.T("report.speedtest.cmp.eu"), metricKey: "eu_avg_down_bps", fallbackPct: cfg.EUPct},
+		{label: i18n.T("report.speedtest.cmp.us"), metricKey: "us_avg_down_bps", fallbackPct: cfg.USPct},
 	}
 	views := []speedtestComparisonView{}
 	for _, comparison := range comparisons {
@@ -405,7 +432,7 @@ func buildDNSSummary(configuredServers []string, servers []dnsServerView) string
 	}
 
 	if len(faster) == 0 {
-		return "Complimenti: hai il server DNS migliore già configurato."
+		return i18n.T("report.dns.summary.best")
 	}
 
 	sort.Slice(faster, func(i, j int) bool {
@@ -420,11 +447,7 @@ func buildDNSSummary(configuredServers []string, servers []dnsServerView) string
 	if diffPct < 0 {
 		diffPct = 0
 	}
-	return fmt.Sprintf(
-		"Il DNS configurato attualmente in media è %.0f%% meno veloce di queste alternative misurate: %s.",
-		math.Round(diffPct),
-		strings.Join(alternativeNames, ", "),
-	)
+	return i18n.T("report.dns.summary.slower", math.Round(diffPct), strings.Join(alternativeNames, ", "))
 }
 
 func splitList(value string) []string {
@@ -650,10 +673,10 @@ func toJSON(value any) template.JS {
 }
 
 const htmlTemplate = `<!doctype html>
-<html lang="en">
+<html lang="{{ lang }}">
 <head>
 <meta charset="utf-8" />
-<title>Conncheck Report</title>
+<title>{{ t "report.title" }}</title>
 <style>
 body { font-family: "Segoe UI", sans-serif; margin: 24px; background: #f7f9fc; }
 header { display: flex; justify-content: space-between; align-items: center; }
@@ -701,6 +724,14 @@ section { background: #fff; padding: 16px; margin-top: 16px; border-radius: 12px
 .latency-note { color: #6b7280; font-size: 12px; }
 .latency-button { background: #2563eb; border: none; color: #fff; padding: 6px 12px; border-radius: 999px; cursor: pointer; font-size: 12px; }
 .latency-button:disabled { opacity: 0.6; cursor: not-allowed; }
+.svg-chart { display: block; max-width: 100%; height: auto; margin-top: 8px; }
+.svg-chart text { font-family: "Segoe UI", sans-serif; font-size: 12px; fill: #374151; }
+.trace-table { width: 100%; border-collapse: collapse; margin-top: 8px; font-size: 13px; }
+.trace-table th, .trace-table td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #e5e7eb; }
+.trace-table tr.jump td { background: #fef3c7; }
+.trace-timeout { color: #9ca3af; }
+.legend-swatch { display: inline-block; width: 10px; height: 10px; border-radius: 2px; margin-right: 4px; }
+.http-legend { display: flex; gap: 12px; flex-wrap: wrap; font-size: 12px; }
 @keyframes pulse { 0% { transform: scale(1); } 50% { transform: scale(1.02); } 100% { transform: scale(1); } }
 @keyframes fill-bar { to { width: var(--target, 0%); } }
 small { color: #6b7280; }
@@ -708,19 +739,22 @@ small { color: #6b7280; }
 </head>
 <body>
 <header>
-  <h1>Conncheck Report</h1>
-  <div class="badge">Version {{ .Version }}</div>
+  <h1>{{ t "report.title" }}</h1>
+  <div class="badge">{{ t "report.version" .Version }}</div>
 </header>
 <section>
-  <h2>Environment</h2>
+  <h2>{{ t "report.environment" }}</h2>
   <div class="grid">
-    <div class="card"><strong>OS:</strong> {{ .Environment.OS }}<br/><small>{{ .Environment.Arch }}</small></div>
-    <div class="card"><strong>Hostname:</strong> {{ .Environment.Hostname }}</div>
-    <div class="card"><strong>Timezone:</strong> {{ .Environment.Timezone }}</div>
+    <div class="card"><strong>{{ t "report.env.os" }}</strong> {{ .Environment.OS }}<br/><small>{{ .Environment.Arch }}</small></div>
+    <div class="card"><strong>{{ t "report.env.hostname" }}</strong> {{ .Environment.Hostname }}</div>
+    <div class="card"><strong>{{ t "report.env.timezone" }}</strong> {{ .Environment.Timezone }}</div>
+    {{ with .Environment.Link }}
+    <div class="card"><strong>{{ t "report.env.link" }}</strong> {{ .Interface }} ({{ label "report.env.media" .Media }})<br/><small>{{ if .SpeedMbps }}{{ t "report.env.speed" .SpeedMbps }}{{ else }}{{ t "report.env.speed_unknown" }}{{ end }}{{ if .Duplex }}, {{ label "report.env.duplex" .Duplex }}{{ end }}{{ if .Autoneg }}, {{ label "report.env.autoneg" .Autoneg }}{{ end }}</small></div>
+    {{ end }}
   </div>
 </section>
 <section>
-  <h2>Findings</h2>
+  <h2>{{ t "report.findings" }}</h2>
   {{ if .Findings }}
   <ul>
     {{ range .Findings }}
@@ -728,16 +762,36 @@ small { color: #6b7280; }
     {{ end }}
   </ul>
   {{ else }}
-  <p>No findings were recorded.</p>
+  <p>{{ t "report.findings.none" }}</p>
   {{ end }}
 </section>
+{{ if .Regions }}
+<section>
+  <h2>{{ t "report.regions.title" }}</h2>
+  <table class="trace-table">
+    <tr><th>{{ t "report.regions.region" }}</th><th>{{ t "report.regions.targets" }}</th><th>{{ t "report.regions.latency" }}</th><th>{{ t "report.regions.loss" }}</th><th>{{ t "report.regions.pmtu" }}</th><th>{{ t "report.regions.hops" }}</th><th>{{ t "report.regions.http" }}</th></tr>
+    {{ range .Regions.Rows }}
+    <tr>
+      <td><strong>{{ .Region }}</strong></td>
+      <td>{{ range $index, $name := .Targets }}{{ if $index }}, {{ end }}{{ $name }}{{ end }}</td>
+      <td>{{ if .HasLatency }}{{ printf "%.0f" .LatencyAvgMs }} ms{{ else }}—{{ end }}</td>
+      <td>{{ if .HasLatency }}{{ .LossPct }}%{{ else }}—{{ end }}</td>
+      <td>{{ if .HasPMTU }}{{ .PMTU }}{{ else }}—{{ end }}</td>
+      <td>{{ if .HasHops }}{{ printf "%.1f" .HopsAvg }}{{ else }}—{{ end }}</td>
+      <td>{{ if .HasHTTP }}{{ printf "%.0f" .HTTPAvgMs }} ms{{ else }}—{{ end }}</td>
+    </tr>
+    {{ end }}
+  </table>
+  <p><small>{{ t "report.regions.note" }}</small></p>
+</section>
+{{ end }}
 {{ if .Speedtest }}
 <section>
-  <h2>Scala Speedtest (medie server locali)</h2>
+  <h2>{{ t "report.speedtest.title" }}</h2>
   {{ if .Speedtest.Available }}
   <div class="grid">
     <div class="card">
-      <h3>Download medio</h3>
+      <h3>{{ t "report.speedtest.download" }}</h3>
       {{ if .Speedtest.DownloadCurrentScale }}
       <div class="slider-block">
         <input type="range" min="0" max="{{ printf "%.0f" .Speedtest.DownloadMaxMbps }}" value="{{ printf "%.0f" .Speedtest.LocalDownloadMbps }}" disabled />
@@ -752,7 +806,7 @@ small { color: #6b7280; }
       </ul>
     </div>
     <div class="card">
-      <h3>Upload medio</h3>
+      <h3>{{ t "report.speedtest.upload" }}</h3>
       {{ if .Speedtest.UploadCurrentScale }}
       <div class="slider-block">
         <input type="range" min="0" max="{{ printf "%.0f" .Speedtest.UploadMaxMbps }}" value="{{ printf "%.0f" .Speedtest.LocalUploadMbps }}" disabled />
@@ -769,29 +823,29 @@ small { color: #6b7280; }
   </div>
   {{ if .Speedtest.Comparisons }}
   <div class="card" style="margin-top: 12px;">
-    <h3>Variazioni rispetto ai locali</h3>
+    <h3>{{ t "report.speedtest.comparisons" }}</h3>
     {{ range .Speedtest.Comparisons }}
     <div class="comparison">
-      <div><strong>{{ .Label }}</strong>: {{ printf "%.0f" .Percent }}% ({{ printf "%.0f" .SpeedMbps }} Mbps, perdita {{ printf "%.0f" .LossPct }}%)</div>
+      <div><strong>{{ .Label }}</strong>: {{ t "report.speedtest.comparison" .Percent .SpeedMbps .LossPct }}</div>
       <input type="range" min="0" max="100" value="{{ printf "%.0f" .Percent }}" disabled />
     </div>
     {{ end }}
   </div>
   {{ end }}
   {{ else }}
-  <p>Speedtest non disponibile o senza dati locali.</p>
+  <p>{{ t "report.speedtest.unavailable" }}</p>
   {{ end }}
 </section>
 {{ end }}
 {{ if .DNS }}
 <section>
-  <h2>DNS Benchmark</h2>
+  <h2>{{ t "report.dns.title" }}</h2>
   {{ if .DNS.Available }}
   {{ if .DNS.Summary }}
   <p><strong>{{ .DNS.Summary }}</strong></p>
   {{ end }}
   {{ if .DNS.Domains }}
-  <p><small>Domains: {{ range $index, $domain := .DNS.Domains }}{{ if $index }}, {{ end }}{{ $domain }}{{ end }}</small></p>
+  <p><small>{{ t "report.dns.domains" }} {{ range $index, $domain := .DNS.Domains }}{{ if $index }}, {{ end }}{{ $domain }}{{ end }}</small></p>
   {{ end }}
   <div>
     {{ range .DNS.Servers }}
@@ -799,19 +853,19 @@ small { color: #6b7280; }
       <div class="dns-label">{{ .Server }}</div>
       <div class="dns-bar">
         <div class="dns-bar-fill" style="--target: {{ printf "%.0f" .Percent }}%;"></div>
-        <span>{{ printf "%.1f" .AvgMs }} ms ({{ .Success }} ok, {{ .Fail }} fail)</span>
+        <span>{{ t "report.dns.bar" .AvgMs .Success .Fail }}</span>
       </div>
     </div>
     {{ end }}
   </div>
   {{ else }}
-  <p>DNS benchmark non disponibile o senza dati sufficienti.</p>
+  <p>{{ t "report.dns.unavailable" }}</p>
   {{ end }}
 </section>
 {{ end }}
 {{ if .Latency }}
 <section>
-  <h2>Monitor Latency (1 minuto, intervallo 100ms)</h2>
+  <h2>{{ t "report.latency.title" }}</h2>
   {{ if .Latency.Available }}
   <div class="latency-chart-wrap">
     <div class="latency-controls">
@@ -824,21 +878,21 @@ small { color: #6b7280; }
         {{ end }}
         <div class="latency-legend-item">
           <span class="latency-dot" style="--dot-color: #dc2626;"></span>
-          <span>Loss</span>
+          <span>{{ t "report.latency.loss" }}</span>
         </div>
       </div>
-      <button class="latency-button" id="latency-replay">Replay animazione</button>
+      <button class="latency-button" id="latency-replay">{{ t "report.latency.replay" }}</button>
     </div>
     <canvas id="latency-chart" width="980" height="320"></canvas>
-    <div class="latency-note">La linea mostra la variazione della latenza; i punti rossi indicano packet loss.</div>
+    <div class="latency-note">{{ t "report.latency.note" }}</div>
   </div>
   <div class="grid" style="margin-top: 12px;">
     {{ range .Latency.Targets }}
     <div class="card">
       <h3>{{ .Target }}</h3>
-      <p><strong>Media:</strong> {{ .AvgMs }} ms</p>
-      <p><strong>Min:</strong> {{ .MinMs }} ms | <strong>Max:</strong> {{ .MaxMs }} ms</p>
-      <p><strong>Loss:</strong> {{ .LossPct }}%</p>
+      <p><strong>{{ t "report.latency.avg" }}</strong> {{ .AvgMs }} ms</p>
+      <p><strong>{{ t "report.latency.min" }}</strong> {{ .MinMs }} ms | <strong>{{ t "report.latency.max" }}</strong> {{ .MaxMs }} ms</p>
+      <p><strong>{{ t "report.latency.loss_label" }}</strong> {{ .LossPct }}%</p>
     </div>
     {{ end }}
   </div>
@@ -954,35 +1008,35 @@ small { color: #6b7280; }
     })();
   </script>
   {{ else }}
-  <p>Monitor latency non disponibile o senza dati sufficienti.</p>
+  <p>{{ t "report.latency.unavailable" }}</p>
   {{ end }}
 </section>
 {{ end }}
 {{ if .MTU }}
 <section>
-  <h2>MTU &amp; PMTU</h2>
+  <h2>{{ t "report.mtu.title" }}</h2>
   {{ if .MTU.Available }}
   <div class="mtu-grid">
     <div class="card">
-      <h3>Stato MTU</h3>
+      <h3>{{ t "report.mtu.status" }}</h3>
       <p>
-        <span class="mtu-pill {{ if eq .MTU.Health "OK" }}{{ else if eq .MTU.Health "WARN" }}warn{{ else }}bad{{ end }}">Health: {{ .MTU.Health }}</span>
+        <span class="mtu-pill {{ if eq .MTU.Health "OK" }}{{ else if eq .MTU.Health "Warning" }}warn{{ else }}bad{{ end }}">{{ t "report.mtu.health" (label "mtu.health" .MTU.Health) }}</span>
         {{ if .MTU.Blackhole }}
-        <span class="mtu-pill {{ if eq .MTU.Blackhole "probable" }}bad{{ else }}{{ end }}">Blackhole: {{ .MTU.Blackhole }}</span>
+        <span class="mtu-pill {{ if eq .MTU.Blackhole "probable" }}bad{{ else }}{{ end }}">{{ t "report.mtu.blackhole" (label "mtu.blackhole_state" .MTU.Blackhole) }}</span>
         {{ end }}
       </p>
       {{ if .MTU.MSSClass }}
-      <p><small>MSS: {{ .MTU.MSSClass }}</small></p>
+      <p><small>{{ t "report.mtu.mss" (label "mss.class" .MTU.MSSClass) }}</small></p>
       {{ end }}
       {{ if .MTU.TargetsTested }}
-      <p><small>Targets testati: {{ range $index, $target := .MTU.TargetsTested }}{{ if $index }}, {{ end }}{{ $target }}{{ end }}</small></p>
+      <p><small>{{ t "report.mtu.targets_tested" }} {{ range $index, $target := .MTU.TargetsTested }}{{ if $index }}, {{ end }}{{ $target }}{{ end }}</small></p>
       {{ end }}
     </div>
     <div class="card">
-      <h3>Valori principali</h3>
+      <h3>{{ t "report.mtu.values" }}</h3>
       {{ if gt .MTU.LocalMTU 0 }}
       <div class="mtu-meter">
-        <div class="mtu-meter-label">MTU locale: {{ .MTU.LocalMTU }}</div>
+        <div class="mtu-meter-label">{{ t "report.mtu.local" .MTU.LocalMTU }}</div>
         <div class="mtu-bar">
           <div class="mtu-bar-fill primary" style="--target: {{ printf "%.0f" (mulPercent .MTU.LocalMTU .MTU.MaxValue) }}%;"></div>
           <span>{{ .MTU.LocalMTU }}</span>
@@ -991,7 +1045,7 @@ small { color: #6b7280; }
       {{ end }}
       {{ if gt .MTU.PMTUMin 0 }}
       <div class="mtu-meter">
-        <div class="mtu-meter-label">PMTU minimo: {{ .MTU.PMTUMin }}</div>
+        <div class="mtu-meter-label">{{ t "report.mtu.pmtu_min" .MTU.PMTUMin }}</div>
         <div class="mtu-bar">
           <div class="mtu-bar-fill secondary" style="--target: {{ printf "%.0f" (mulPercent .MTU.PMTUMin .MTU.MaxValue) }}%;"></div>
           <span>{{ .MTU.PMTUMin }}</span>
@@ -1000,7 +1054,7 @@ small { color: #6b7280; }
       {{ end }}
       {{ if gt .MTU.SuggestedMTU 0 }}
       <div class="mtu-meter">
-        <div class="mtu-meter-label">Suggerito: {{ .MTU.SuggestedMTU }}</div>
+        <div class="mtu-meter-label">{{ t "report.mtu.suggested" .MTU.SuggestedMTU }}</div>
         <div class="mtu-bar">
           <div class="mtu-bar-fill accent" style="--target: {{ printf "%.0f" (mulPercent .MTU.SuggestedMTU .MTU.MaxValue) }}%;"></div>
           <span>{{ .MTU.SuggestedMTU }}</span>
@@ -1011,7 +1065,7 @@ small { color: #6b7280; }
   </div>
   {{ if .MTU.Details }}
   <div class="mtu-details">
-    <h3>Dettaglio PMTU per target</h3>
+    <h3>{{ t "report.mtu.details" }}</h3>
     {{ range .MTU.Details }}
     <div class="card">
       <div><strong>{{ .Target }}</strong> <span class="stack-tag">{{ .Stack }}</span></div>
@@ -1024,15 +1078,176 @@ small { color: #6b7280; }
   </div>
   {{ end }}
   {{ else }}
-  <p>MTU non disponibile o senza dati sufficienti.</p>
+  <p>{{ t "report.mtu.unavailable" }}</p>
+  {{ end }}
+</section>
+{{ end }}
+{{ if .Gateway }}
+<section>
+  <h2>{{ t "report.gateway.title" }}</h2>
+  {{ if .Gateway.Available }}
+  <div class="grid">
+    <div class="card">
+      <p><strong>{{ t "report.gateway.address" }}</strong> {{ .Gateway.Gateway }} <span class="status-{{ .Gateway.Status }}">({{ label "status" .Gateway.Status }})</span></p>
+      {{ if .Gateway.Method }}<p><strong>{{ t "report.gateway.method" }}</strong> {{ .Gateway.Method }}{{ if .Gateway.ICMPNote }} · {{ .Gateway.ICMPNote }}{{ end }}</p>{{ end }}
+      <p><strong>{{ t "report.latency.loss_label" }}</strong> {{ printf "%.1f" .Gateway.LossPct }}%{{ if .Gateway.Detailed }} · {{ t "report.gateway.samples" .Gateway.Sent .Gateway.LossBurst }}{{ end }}</p>
+      <p><strong>{{ t "report.latency.min" }}</strong> {{ printf "%.1f" .Gateway.MinMs }} ms | <strong>{{ t "report.latency.avg" }}</strong> {{ printf "%.1f" .Gateway.AvgMs }} ms | <strong>{{ t "report.latency.max" }}</strong> {{ printf "%.1f" .Gateway.MaxMs }} ms</p>
+      {{ if .Gateway.Detailed }}
+      <p><strong>p50</strong> {{ printf "%.1f" .Gateway.P50Ms }} ms | <strong>p95</strong> {{ printf "%.1f" .Gateway.P95Ms }} ms | <strong>p99</strong> {{ printf "%.1f" .Gateway.P99Ms }} ms</p>
+      <p><strong>{{ t "report.gateway.jitter" }}</strong> {{ printf "%.2f" .Gateway.JitterMs }} ms | <strong>{{ t "report.gateway.stddev" }}</strong> {{ printf "%.2f" .Gateway.StddevMs }} ms</p>
+      {{ end }}
+    </div>
+    <div class="card">
+      <svg class="svg-chart" viewBox="0 0 360 70" width="360" height="70" role="img" aria-label="{{ t "report.gateway.range" }}">
+        <line x1="20" y1="30" x2="340" y2="30" stroke="#e5e7eb" stroke-width="8" stroke-linecap="round" />
+        <line x1="{{ printf "%.1f" .Gateway.MinX }}" y1="30" x2="{{ printf "%.1f" .Gateway.MaxX }}" y2="30" stroke="#60a5fa" stroke-width="8" stroke-linecap="round" />
+        <circle cx="{{ printf "%.1f" .Gateway.AvgX }}" cy="30" r="7" fill="#1d4ed8" />
+        <text x="20" y="60">0 ms</text>
+        <text x="340" y="60" text-anchor="end">{{ .Gateway.ScaleMax }} ms</text>
+        <text x="{{ printf "%.1f" .Gateway.AvgX }}" y="14" text-anchor="middle">{{ printf "%.1f" .Gateway.AvgMs }} ms</text>
+      </svg>
+      <small>{{ t "report.gateway.range" }}</small>
+    </div>
+  </div>
+  {{ else }}
+  <p>{{ t "report.gateway.unavailable" }}</p>
+  {{ end }}
+</section>
+{{ end }}
+{{ with .WiFi }}
+<section>
+  <h2>{{ t "report.wifi.title" }}</h2>
+  <div class="grid">
+    <div class="card">
+      <p><strong>{{ t "report.wifi.network" }}</strong> {{ if .SSID }}{{ .SSID }}{{ else }}{{ t "report.wifi.hidden" }}{{ end }} <span class="status-{{ .Status }}">({{ label "status" .Status }})</span></p>
+      <p><strong>{{ t "report.wifi.interface" }}</strong> {{ .Interface }}{{ if .BSSID }} · BSSID {{ .BSSID }}{{ end }}{{ if .RadioType }} · {{ .RadioType }}{{ end }}</p>
+      <p><strong>{{ t "report.wifi.channel" }}</strong> {{ if .Channel }}{{ .Channel }}{{ else }}?{{ end }}{{ if .Band }} ({{ .Band }} GHz){{ end }}{{ if .WidthMHz }} · {{ .WidthMHz }} MHz{{ end }}</p>
+    </div>
+    <div class="card">
+      <p><strong>{{ t "report.wifi.signal" }}</strong> {{ if .SignalDbm }}{{ .SignalDbm }} dBm{{ else }}?{{ end }}{{ if .NoiseDbm }} | <strong>{{ t "report.wifi.noise" }}</strong> {{ .NoiseDbm }} dBm{{ end }}{{ if .SNRDb }} | <strong>SNR</strong> {{ .SNRDb }} dB{{ end }}</p>
+      {{ if or .RxMbps .TxMbps }}<p><strong>{{ t "report.wifi.rates" }}</strong> {{ if .RxMbps }}↓ {{ .RxMbps }} Mbps{{ end }} {{ if .TxMbps }}↑ {{ .TxMbps }} Mbps{{ end }}</p>{{ end }}
+      {{ if .Score }}<p><strong>{{ t "report.wifi.congestion" }}</strong> {{ .Score }}/100 · {{ t "report.wifi.overlapping" .Overlapping .Networks }}</p>{{ end }}
+      {{ if .Recommended }}<p><strong>{{ t "report.wifi.recommended" }}</strong> {{ .Recommended }}</p>{{ end }}
+    </div>
+  </div>
+  {{ if .Channels }}
+  <table class="trace-table">
+    <tr><th>{{ t "report.wifi.channel_header" }}</th><th>{{ t "report.wifi.networks" }}</th><th>{{ t "report.wifi.overlapping_header" }}</th><th>{{ t "report.wifi.score" }}</th></tr>
+    {{ range .Channels }}
+    <tr{{ if .Current }} class="jump"{{ end }}>
+      <td>{{ if .Current }}<strong>{{ .Channel }}</strong> ({{ t "report.wifi.current" }}){{ else }}{{ .Channel }}{{ end }}{{ if .Recommended }} ✓{{ end }}</td>
+      <td>{{ .Networks }}</td>
+      <td>{{ .Overlapping }}</td>
+      <td>{{ .Score }}</td>
+    </tr>
+    {{ end }}
+  </table>
+  {{ end }}
+</section>
+{{ end }}
+{{ if .DualStack }}
+<section>
+  <h2>{{ t "report.dualstack.title" }}</h2>
+  <svg class="svg-chart" viewBox="0 0 420 170" width="420" height="170" role="img" aria-label="{{ t "report.dualstack.title" }}">
+    <text x="200" y="24" text-anchor="middle">{{ t "report.dualstack.address" }}</text>
+    <text x="320" y="24" text-anchor="middle">{{ t "report.dualstack.reachability" }}</text>
+    {{ range .DualStack.Rows }}
+    <text x="10" y="{{ .Y }}">{{ .Label }}</text>
+    {{ if .Address }}<circle cx="200" cy="{{ .CY }}" r="10" class="cell-{{ .Address }}" fill="{{ if eq .Address "ok" }}#16a34a{{ else if eq .Address "fail" }}#dc2626{{ else }}#d1d5db{{ end }}"><title>{{ label "report.dualstack.state" .Address }}</title></circle>{{ end }}
+    <circle cx="320" cy="{{ .CY }}" r="10" class="cell-{{ .Reachable }}" fill="{{ if eq .Reachable "ok" }}#16a34a{{ else if eq .Reachable "fail" }}#dc2626{{ else }}#d1d5db{{ end }}"><title>{{ label "report.dualstack.state" .Reachable }}</title></circle>
+    {{ end }}
+  </svg>
+  <p class="http-legend">
+    <span><span class="legend-swatch" style="background:#16a34a"></span>{{ label "report.dualstack.state" "ok" }}</span>
+    <span><span class="legend-swatch" style="background:#dc2626"></span>{{ label "report.dualstack.state" "fail" }}</span>
+    <span><span class="legend-swatch" style="background:#d1d5db"></span>{{ label "report.dualstack.state" "unknown" }}</span>
+  </p>
+</section>
+{{ end }}
+{{ if .Trace }}
+<section>
+  <h2>{{ t "report.traceroute.title" }}</h2>
+  <p><small>{{ t "report.traceroute.note" }}</small></p>
+  {{ range .Trace.Targets }}
+  <div class="card" style="margin-top: 12px;">
+    <h3>{{ .Target }}</h3>
+    <table class="trace-table">
+      <tr><th>{{ t "report.traceroute.hop" }}</th><th>{{ t "report.traceroute.host" }}</th><th>{{ t "report.traceroute.rtt" }}</th><th></th></tr>
+      {{ range .Hops }}
+      <tr{{ if .Jump }} class="jump"{{ end }}>
+        <td>{{ .Hop }}</td>
+        {{ if .Timeout }}
+        <td class="trace-timeout">{{ t "report.traceroute.timeout" }}</td><td class="trace-timeout">—</td><td></td>
+        {{ else }}
+        <td>{{ .Host }}{{ if and .Address (ne .Address .Host) }} <small>({{ .Address }})</small>{{ end }}</td>
+        <td>{{ printf "%.1f" .AvgMs }} ms</td>
+        <td><svg width="220" height="12" role="img" aria-label="{{ printf "%.1f" .AvgMs }} ms"><rect x="0" y="1" width="{{ printf "%.1f" .BarPx }}" height="10" rx="3" fill="{{ if .Jump }}#f59e0b{{ else }}#60a5fa{{ end }}" /></svg></td>
+        {{ end }}
+      </tr>
+      {{ end }}
+    </table>
+  </div>
+  {{ end }}
+</section>
+{{ end }}
+{{ if .HTTP }}
+<section>
+  <h2>{{ t "report.http.title" }}</h2>
+  {{ if .HTTP.Available }}
+  <p class="http-legend">
+    <span><span class="legend-swatch" style="background:#a78bfa"></span>{{ t "report.http.phase.dns" }}</span>
+    <span><span class="legend-swatch" style="background:#60a5fa"></span>{{ t "report.http.phase.connect" }}</span>
+    <span><span class="legend-swatch" style="background:#34d399"></span>{{ t "report.http.phase.tls" }}</span>
+    <span><span class="legend-swatch" style="background:#fbbf24"></span>{{ t "report.http.phase.ttfb" }}</span>
+    <span><span class="legend-swatch" style="background:#f472b6"></span>{{ t "report.http.phase.download" }}</span>
+  </p>
+  {{ range .HTTP.Endpoints }}
+  <div class="card" style="margin-top: 8px;">
+    <div><strong>{{ .Endpoint }}</strong> <small>{{ t "report.http.summary" .Status .TotalMs }}</small></div>
+    <svg class="svg-chart" viewBox="0 0 560 24" width="560" height="24" role="img" aria-label="{{ .Endpoint }}">
+      <rect x="0" y="4" width="560" height="16" rx="4" fill="#f3f4f6" />
+      {{ range .Segments }}
+      <rect x="{{ printf "%.1f" .X }}" y="4" width="{{ printf "%.1f" .Width }}" height="16" fill="{{ .Color }}"><title>{{ .Label }}: {{ printf "%.1f" .Ms }} ms</title></rect>
+      {{ end }}
+    </svg>
+  </div>
+  {{ end }}
+  {{ else }}
+  <p>{{ t "report.http.unavailable" }}</p>
   {{ end }}
 </section>
 {{ end }}
+{{ if .Bloat }}
 <section>
-  <h2>Test Results</h2>
+  <h2>{{ t "report.bufferbloat.title" }}</h2>
+  {{ if .Bloat.Available }}
+  <p><small>{{ t "report.bufferbloat.target" .Bloat.Target }}</small></p>
+  <svg class="svg-chart" viewBox="0 0 420 220" width="420" height="220" role="img" aria-label="{{ t "report.bufferbloat.title" }}">
+    <line x1="40" y1="20" x2="40" y2="180" stroke="#e5e7eb" />
+    <line x1="40" y1="180" x2="410" y2="180" stroke="#e5e7eb" />
+    <text x="34" y="24" text-anchor="end">{{ printf "%.0f" .Bloat.MaxMs }}</text>
+    <text x="34" y="180" text-anchor="end">0</text>
+    {{ range .Bloat.Phases }}
+    <rect x="{{ printf "%.1f" .X }}" y="{{ printf "%.1f" .AvgY }}" width="40" height="{{ printf "%.1f" .AvgHeight }}" fill="#60a5fa"><title>{{ t "report.bufferbloat.avg" }} {{ printf "%.1f" .AvgMs }} ms</title></rect>
+    <rect x="{{ printf "%.1f" .P95X }}" y="{{ printf "%.1f" .P95Y }}" width="40" height="{{ printf "%.1f" .P95Height }}" fill="#f97316"><title>p95 {{ printf "%.1f" .P95Ms }} ms</title></rect>
+    <text x="{{ printf "%.1f" .LabelX }}" y="198" text-anchor="middle">{{ .Label }}</text>
+    {{ if .HasMbps }}<text x="{{ printf "%.1f" .LabelX }}" y="214" text-anchor="middle">{{ printf "%.0f" .Mbps }} Mbps</text>{{ end }}
+    {{ end }}
+  </svg>
+  <p class="http-legend">
+    <span><span class="legend-swatch" style="background:#60a5fa"></span>{{ t "report.bufferbloat.avg" }}</span>
+    <span><span class="legend-swatch" style="background:#f97316"></span>p95</span>
+  </p>
+  {{ else }}
+  <p>{{ t "report.bufferbloat.unavailable" }}</p>
+  {{ end }}
+</section>
+{{ end }}
+<section>
+  <h2>{{ t "report.tests" }}</h2>
   {{ range .Tests }}
   <div class="card">
-    <h3>{{ .Name }} <span class="status-{{ .Status }}">({{ .Status }})</span></h3>
+    <h3>{{ label "test" .Name }} <span class="status-{{ .Status }}">({{ label "status" .Status }})</span></h3>
     <p><small>{{ .StartedAt }} → {{ .EndedAt }}</small></p>
     {{ if .Metrics }}
       <ul>
@@ -1042,7 +1257,7 @@ small { color: #6b7280; }
       </ul>
     {{ end }}
     {{ if .Evidence }}
-      <p><strong>Evidence</strong></p>
+      <p><strong>{{ t "report.evidence" }}</strong></p>
       <ul>
         {{ range .Evidence }}
         <li>{{ .Label }}: {{ .Path }} {{ if .Note }}({{ .Note }}){{ end }}</li>
@@ -1051,15 +1266,31 @@ small { color: #6b7280; }
     {{ end }}
   </div>
   {{ end }}
+  {{ if .Excluded }}
+  <h3>{{ t "report.excluded.title" }}</h3>
+  <ul>
+    {{ range .Excluded }}
+    <li>{{ label "test" .Test }}: {{ excluded . }}</li>
+    {{ end }}
+  </ul>
+  {{ end }}
 </section>
 <footer>
-  <p><small>Generated at {{ .FinishedAt }}</small></p>
+  <p><small>{{ t "report.generated" .FinishedAt }}</small></p>
 </footer>
 </body>
 </html>`
 
+// ExclusionReason describes why a test was left out of a run.
+func ExclusionReason(excluded model.Exclusion) string {
+	if excluded.Reason == "config" {
+		return i18n.T("report.excluded.config")
+	}
+	return i18n.T("report.excluded."+excluded.Reason, excluded.Pattern)
+}
+
 func FormatSummary(result model.Result) string {
-	return fmt.Sprintf("Tests: %d, OK: %d, WARN: %d, FAIL: %d, SKIPPED: %d",
+	return i18n.T("report.summary",
 		len(result.Tests),
 		result.Summary.StatusCounts["OK"],
 		result.Summary.StatusCounts["WARN"],
@@ -1067,3 +1298,20 @@ func FormatSummary(result model.Result) string {
 		result.Summary.StatusCounts["SKIPPED"],
 	)
 }
+
+// WorstStatus returns the most severe test status in result: FAIL, WARN, OK,
+// or SKIPPED when nothing ran.
+func WorstStatus(result model.Result) string {
+	worst := "SKIPPED"
+	for _, test := range result.Tests {
+		switch {
+		case test.Status == "FAIL":
+			return "FAIL"
+		case test.Status == "WARN":
+			worst = "WARN"
+		case test.Status == "OK" && worst == "SKIPPED":
+			worst = "OK"
+		}
+	}
+	return worst
+}
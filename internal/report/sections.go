@@ -0,0 +1,622 @@
+package report
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
+)
+
+const (
+	traceBarWidth     = 220.0
+	httpChartWidth    = 560.0
+	gatewayChartWidth = 320.0
+	bloatChartHeight  = 160.0
+	bloatChartBase    = 180.0
+)
+
+type tracerouteView struct {
+	Available bool
+	Targets   []traceTargetView
+}
+
+type traceTargetView struct {
+	Target string
+	MaxMs  float64
+	Hops   []traceHopView
+}
+
+type traceHopView struct {
+	Hop     int       `json:"hop"`
+	Host    string    `json:"host"`
+	Address string    `json:"address"`
+	RTTs    []float64 `json:"rtt_ms"`
+	Timeout bool      `json:"timeout"`
+	AvgMs   float64   `json:"-"`
+	BarPx   float64   `json:"-"`
+	Jump    bool      `json:"-"`
+}
+
+type dualStackView struct {
+	Available bool
+	Rows      []dualStackRowView
+}
+
+type dualStackRowView struct {
+	Label     string
+	Y         int
+	CY        int
+	Address   string
+	Reachable string
+}
+
+type gatewayView struct {
+	Available bool
+	Status    string
+	Gateway   string
+	Method    string
+	ICMPNote  string
+	Sent      int
+	LossPct   float64
+	LossBurst int
+	MinMs     float64
+	AvgMs     float64
+	MaxMs     float64
+	P50Ms     float64
+	P95Ms     float64
+	P99Ms     float64
+	StddevMs  float64
+	JitterMs  float64
+	// Detailed is set for runs that sampled the gateway with percentiles
+	// and jitter; older runs only have min/avg/max and loss.
+	Detailed bool
+	ScaleMax int
+	MinX     float64
+	AvgX     float64
+	MaxX     float64
+}
+
+// wifiView shows the Wi-Fi link; the fields are the metrics as recorded,
+// empty when the source did not report them.
+type wifiView struct {
+	Status    string
+	Interface string
+	SSID      string
+	BSSID     string
+	Band      string
+	Channel   string
+	WidthMHz  string
+	RadioType string
+	SignalDbm string
+	NoiseDbm  string
+	SNRDb     string
+	RxMbps    string
+	TxMbps    string
+	// Scan fields are set when the optional scan ran.
+	Networks    string
+	Overlapping string
+	Score       string
+	Recommended string
+	Channels    []wifiChannelView
+}
+
+// wifiChannelView is one row of the channel usage table: the channels in
+// use around, the current one and the recommended ones.
+type wifiChannelView struct {
+	Channel     int  `json:"channel"`
+	Networks    int  `json:"networks"`
+	Overlapping int  `json:"overlapping"`
+	Score       int  `json:"score"`
+	Current     bool `json:"-"`
+	Recommended bool `json:"-"`
+}
+
+type httpView struct {
+	Available bool
+	MaxMs     float64
+	Endpoints []httpEndpointView
+}
+
+type httpEndpointView struct {
+	Endpoint string
+	Status   string
+	TotalMs  float64
+	Segments []httpSegmentView
+}
+
+type httpSegmentView struct {
+	Phase string
+	Label string
+	Ms    float64
+	X     float64
+	Width float64
+	Color string
+}
+
+type bufferbloatView struct {
+	Available bool
+	Target    string
+	MaxMs     float64
+	Phases    []bufferbloatPhaseView
+}
+
+type bufferbloatPhaseView struct {
+	Phase     string
+	Label     string
+	AvgMs     float64
+	P95Ms     float64
+	Mbps      float64
+	HasMbps   bool
+	X         float64
+	P95X      float64
+	LabelX    float64
+	AvgY      float64
+	P95Y      float64
+	AvgHeight float64
+	P95Height float64
+}
+
+func findTest(result model.Result, name string) *model.TestResult {
+	for i := range result.Tests {
+		if result.Tests[i].Name == name {
+			return &result.Tests[i]
+		}
+	}
+	return nil
+}
+
+func buildTracerouteView(result model.Result) *tracerouteView {
+	test := findTest(result, "traceroute")
+	if test == nil {
+		return nil
+	}
+	view := &tracerouteView{}
+	for key, value := range test.Metrics {
+		if !strings.HasPrefix(key, "trace_hops.") {
+			continue
+		}
+		var hops []traceHopView
+		if err := json.Unmarshal([]byte(value), &hops); err != nil {
+			continue
+		}
+		target := traceTargetView{Target: strings.TrimPrefix(key, "trace_hops."), Hops: hops}
+		previous := 0.0
+		for i := range target.Hops {
+			hop := &target.Hops[i]
+			if hop.Timeout || len(hop.RTTs) == 0 {
+				continue
+			}
+			sum := 0.0
+			for _, rtt := range hop.RTTs {
+				sum += rtt
+			}
+			hop.AvgMs = sum / float64(len(hop.RTTs))
+			if hop.AvgMs > target.MaxMs {
+				target.MaxMs = hop.AvgMs
+			}
+			// A jump of 30 ms or more over the previous responding hop is
+			// worth pointing out; per-hop loss is not, because of ICMP rate limiting.
+			if previous > 0 && hop.AvgMs-previous >= 30 {
+				hop.Jump = true
+			}
+			previous = hop.AvgMs
+		}
+		if target.MaxMs > 0 {
+			for i := range target.Hops {
+				target.Hops[i].BarPx = target.Hops[i].AvgMs / target.MaxMs * traceBarWidth
+			}
+		}
+		view.Targets = append(view.Targets, target)
+	}
+	if len(view.Targets) == 0 {
+		return nil
+	}
+	sort.Slice(view.Targets, func(i, j int) bool {
+		return view.Targets[i].Target < view.Targets[j].Target
+	})
+	view.Available = true
+	return view
+}
+
+func buildDualStackView(result model.Result) *dualStackView {
+	test := findTest(result, "dualstack")
+	if test == nil {
+		return nil
+	}
+	ipv6Present, hasPresence := test.Metrics["ipv6_present"]
+	if !hasPresence {
+		return nil
+	}
+	// A name resolves to both families, so its row has no address cell.
+	rows := []struct {
+		label      string
+		hasAddress bool
+		address    string
+		reach      string
+	}{
+		{label: i18n.T("report.dualstack.ipv4"), hasAddress: true, address: test.Metrics["ipv4_present"], reach: test.Metrics["ipv4_reach"]},
+		{label: i18n.T("report.dualstack.ipv6"), hasAddress: true, address: ipv6Present, reach: test.Metrics["ipv6_reach"]},
+		{label: i18n.T("report.dualstack.dual"), reach: test.Metrics["dualstack_reach"]},
+	}
+	view := &dualStackView{Available: true}
+	for i, row := range rows {
+		entry := dualStackRowView{
+			Label:     row.label,
+			Y:         56 + i*36,
+			CY:        52 + i*36,
+			Reachable: cellState(row.reach),
+		}
+		if row.hasAddress {
+			entry.Address = cellState(row.address)
+		}
+		view.Rows = append(view.Rows, entry)
+	}
+	return view
+}
+
+func cellState(value string) string {
+	switch value {
+	case "true":
+		return "ok"
+	case "false":
+		return "fail"
+	default:
+		return "unknown"
+	}
+}
+
+func buildGatewayView(result model.Result) *gatewayView {
+	test := findTest(result, "lan_health")
+	if test == nil {
+		return nil
+	}
+	view := &gatewayView{Status: test.Status, Gateway: test.Metrics["gateway"]}
+	avg, hasAvg := metricFloat(test.Metrics, "avg_ms")
+	if !hasAvg || view.Gateway == "" {
+		return view
+	}
+	view.Available = true
+	view.Method, view.ICMPNote = gatewayMethod(test.Metrics)
+	view.AvgMs = avg
+	view.MinMs, _ = metricFloat(test.Metrics, "min_ms")
+	view.MaxMs, _ = metricFloat(test.Metrics, "max_ms")
+	view.LossPct, _ = metricFloat(test.Metrics, "loss_pct")
+	view.Sent, _ = metricInt(test.Metrics, "sent")
+	view.LossBurst, _ = metricInt(test.Metrics, "max_loss_burst")
+	view.StddevMs, _ = metricFloat(test.Metrics, "stddev_ms")
+	view.JitterMs, _ = metricFloat(test.Metrics, "jitter_ms")
+	view.P50Ms, _ = metricFloat(test.Metrics, "p50_ms")
+	view.P99Ms, _ = metricFloat(test.Metrics, "p99_ms")
+	view.P95Ms, view.Detailed = metricFloat(test.Metrics, "p95_ms")
+	scaleMax := view.MaxMs
+	if scaleMax < 10 {
+		scaleMax = 10
+	}
+	view.ScaleMax = int(math.Ceil(scaleMax*1.2/10.0) * 10)
+	scale := func(ms float64) float64 {
+		return 20 + ms/float64(view.ScaleMax)*gatewayChartWidth
+	}
+	view.MinX = scale(view.MinMs)
+	view.AvgX = scale(view.AvgMs)
+	view.MaxX = scale(view.MaxMs)
+	return view
+}
+
+// gatewayMethod describes how the gateway was measured and, when ping lost
+// requests, what the fallback probe made of it.
+func gatewayMethod(metrics model.StringMap) (string, string) {
+	method := metrics["method"]
+	if method == "" {
+		return "", ""
+	}
+	label := i18n.Label("lan.method", method)
+	if method == "tcp" && metrics["fallback_port"] != "" {
+		label += " :" + metrics["fallback_port"]
+	}
+	note := ""
+	switch code := metrics["icmp_loss"]; code {
+	case "rate_limited":
+		note = i18n.T("report.gateway.icmp_loss.rate_limited", metrics["icmp_loss_pct"])
+	case "blocked":
+		note = i18n.T("report.gateway.icmp_loss.blocked")
+	case "real":
+		note = i18n.T("report.gateway.icmp_loss.real", i18n.Label("lan.method", metrics["fallback_method"]))
+	}
+	if metrics["link_timing"] == "unavailable" {
+		if note != "" {
+			note += " · "
+		}
+		note += i18n.T("report.gateway.no_link_timing")
+	}
+	return label, note
+}
+
+func buildWiFiView(result model.Result) *wifiView {
+	test := findTest(result, "wifi")
+	if test == nil || test.Metrics["interface"] == "" {
+		return nil
+	}
+	view := &wifiView{
+		Status:    test.Status,
+		Interface: test.Metrics["interface"],
+		SSID:      test.Metrics["ssid"],
+		BSSID:     test.Metrics["bssid"],
+		Band:      test.Metrics["band_ghz"],
+		Channel:   test.Metrics["channel"],
+		WidthMHz:  test.Metrics["width_mhz"],
+		RadioType: test.Metrics["radio_type"],
+		SignalDbm: test.Metrics["signal_dbm"],
+		NoiseDbm:  test.Metrics["noise_dbm"],
+		SNRDb:     test.Metrics["snr_db"],
+		RxMbps:    test.Metrics["rx_rate_mbps"],
+		TxMbps:    test.Metrics["tx_rate_mbps"],
+
+		Networks:    test.Metrics["scan_networks"],
+		Overlapping: test.Metrics["overlapping_networks"],
+		Score:       test.Metrics["congestion_score"],
+		Recommended: strings.ReplaceAll(test.Metrics["recommended_channels"], ",", ", "),
+	}
+	var usage []wifiChannelView
+	if err := json.Unmarshal([]byte(test.Metrics["channel_usage"]), &usage); err != nil {
+		return view
+	}
+	recommended := strings.Split(test.Metrics["recommended_channels"], ",")
+	for _, row := range usage {
+		row.Current = strconv.Itoa(row.Channel) == view.Channel
+		row.Recommended = slices.Contains(recommended, strconv.Itoa(row.Channel))
+		if row.Networks > 0 || row.Current || row.Recommended {
+			view.Channels = append(view.Channels, row)
+		}
+	}
+	return view
+}
+
+func buildHTTPView(result model.Result) *httpView {
+	test := findTest(result, "http_check")
+	if test == nil {
+		return nil
+	}
+	view := &httpView{}
+	phases := []struct {
+		phase string
+		key   string
+		color string
+	}{
+		{phase: "dns", key: "http_dns_ms.", color: "#a78bfa"},
+		{phase: "connect", key: "http_connect_ms.", color: "#60a5fa"},
+		{phase: "tls", key: "http_tls_ms.", color: "#34d399"},
+		{phase: "ttfb", key: "http_ttfb_ms.", color: "#fbbf24"},
+	}
+	for key := range test.Metrics {
+		if !strings.HasPrefix(key, "http_total_ms.") {
+			continue
+		}
+		endpoint := strings.TrimPrefix(key, "http_total_ms.")
+		total, _ := metricFloat(test.Metrics, key)
+		entry := httpEndpointView{Endpoint: endpoint, Status: test.Metrics["http_status."+endpoint], TotalMs: total}
+		elapsed := 0.0
+		for _, phase := range phases {
+			value, ok := metricFloat(test.Metrics, phase.key+endpoint)
+			if !ok || value <= 0 {
+				continue
+			}
+			entry.Segments = append(entry.Segments, httpSegmentView{Phase: phase.phase, Ms: value, X: elapsed, Color: phase.color})
+			elapsed += value
+		}
+		if remaining := total - elapsed; remaining > 0 {
+			entry.Segments = append(entry.Segments, httpSegmentView{Phase: "download", Ms: remaining, X: elapsed, Color: "#f472b6"})
+		}
+		if total > view.MaxMs {
+			view.MaxMs = total
+		}
+		view.Endpoints = append(view.Endpoints, entry)
+	}
+	if len(view.Endpoints) == 0 {
+		return view
+	}
+	sort.Slice(view.Endpoints, func(i, j int) bool {
+		return view.Endpoints[i].Endpoint < view.Endpoints[j].Endpoint
+	})
+	for i := range view.Endpoints {
+		for j := range view.Endpoints[i].Segments {
+			segment := &view.Endpoints[i].Segments[j]
+			segment.Label = i18n.T("report.http.phase." + segment.Phase)
+			segment.Width = segment.Ms / view.MaxMs * httpChartWidth
+			segment.X = segment.X / view.MaxMs * httpChartWidth
+		}
+	}
+	view.Available = true
+	return view
+}
+
+func buildBufferbloatView(result model.Result) *bufferbloatView {
+	test := findTest(result, "bufferbloat")
+	if test == nil {
+		return nil
+	}
+	view := &bufferbloatView{Target: test.Metrics["target"]}
+	for _, phase := range []string{"idle", "download", "upload"} {
+		avg, ok := metricFloat(test.Metrics, phase+"_avg_ms")
+		if !ok {
+			continue
+		}
+		p95, _ := metricFloat(test.Metrics, phase+"_p95_ms")
+		mbps, hasMbps := metricFloat(test.Metrics, phase+"_mbps")
+		view.Phases = append(view.Phases, bufferbloatPhaseView{
+			Phase:   phase,
+			Label:   i18n.Label("bufferbloat.phase", phase),
+			AvgMs:   avg,
+			P95Ms:   p95,
+			Mbps:    mbps,
+			HasMbps: hasMbps,
+		})
+		if p95 > view.MaxMs {
+			view.MaxMs = p95
+		}
+		if avg > view.MaxMs {
+			view.MaxMs = avg
+		}
+	}
+	if len(view.Phases) == 0 {
+		return view
+	}
+	view.MaxMs = math.Max(10, math.Ceil(view.MaxMs*1.2/10.0)*10)
+	for i := range view.Phases {
+		phase := &view.Phases[i]
+		phase.X = 60 + float64(i)*120
+		phase.P95X = phase.X + 44
+		phase.LabelX = phase.X + 42
+		phase.AvgHeight = phase.AvgMs / view.MaxMs * bloatChartHeight
+		phase.P95Height = phase.P95Ms / view.MaxMs * bloatChartHeight
+		phase.AvgY = bloatChartBase - phase.AvgHeight
+		phase.P95Y = bloatChartBase - phase.P95Height
+	}
+	view.Available = true
+	return view
+}
+
+type regionView struct {
+	Rows []regionRowView
+}
+
+// regionRowView aggregates every result whose target belongs to Region.
+// Has* flags tell the template which columns have data.
+type regionRowView struct {
+	Region       string
+	Targets      []string
+	LatencyAvgMs float64
+	LossPct      int
+	HasLatency   bool
+	PMTU         int
+	HasPMTU      bool
+	HopsAvg      float64
+	HasHops      bool
+	HTTPAvgMs    float64
+	HasHTTP      bool
+}
+
+type regionTotals struct {
+	latency, hops, http []float64
+	loss, pmtu          int
+	hasPMTU, hasLoss    bool
+}
+
+// buildRegionView groups latency, MTU, traceroute and HTTP results by the
+// region of their catalog target. Targets outside the catalog, or without a
+// region, are collected under an "unassigned" row.
+func buildRegionView(result model.Result) *regionView {
+	if len(result.Targets) == 0 {
+		return nil
+	}
+	regionOf := map[string]string{}
+	names := map[string][]string{}
+	for _, target := range result.Targets {
+		region := target.Region
+		if region == "" {
+			region = i18n.T("report.regions.unassigned")
+		}
+		regionOf[target.Address] = region
+		if target.URL != "" {
+			regionOf[target.URL] = region
+		}
+		names[region] = append(names[region], target.Name)
+	}
+	lookup := func(key string) string {
+		if region, ok := regionOf[key]; ok {
+			return region
+		}
+		return i18n.T("report.regions.unassigned")
+	}
+
+	totals := map[string]*regionTotals{}
+	get := func(region string) *regionTotals {
+		if totals[region] == nil {
+			totals[region] = &regionTotals{}
+		}
+		return totals[region]
+	}
+
+	if test := findTest(result, "latency"); test != nil {
+		for key := range test.Metrics {
+			target, ok := strings.CutSuffix(key, "_avg_ms")
+			if !ok {
+				continue
+			}
+			row := get(lookup(target))
+			if avg, ok := metricFloat(test.Metrics, key); ok {
+				row.latency = append(row.latency, avg)
+			}
+			if loss, ok := metricInt(test.Metrics, target+"_loss_pct"); ok {
+				row.hasLoss = true
+				row.loss = max(row.loss, loss)
+			}
+		}
+	}
+	if test := findTest(result, "mtu_pmtu"); test != nil {
+		for _, detail := range splitDetails(test.Metrics["pmtu_details"]) {
+			row := get(lookup(detail.Target))
+			if !row.hasPMTU || detail.Value < row.pmtu {
+				row.pmtu = detail.Value
+			}
+			row.hasPMTU = true
+		}
+	}
+	if test := findTest(result, "traceroute"); test != nil {
+		for key := range test.Metrics {
+			target, ok := strings.CutSuffix(key, "_hops")
+			if !ok {
+				continue
+			}
+			if hops, ok := metricFloat(test.Metrics, key); ok {
+				row := get(lookup(target))
+				row.hops = append(row.hops, hops)
+			}
+		}
+	}
+	if test := findTest(result, "http_check"); test != nil {
+		for key := range test.Metrics {
+			endpoint, ok := strings.CutPrefix(key, "http_total_ms.")
+			if !ok {
+				continue
+			}
+			if total, ok := metricFloat(test.Metrics, key); ok {
+				row := get(lookup(endpoint))
+				row.http = append(row.http, total)
+			}
+		}
+	}
+
+	view := &regionView{}
+	for region := range names {
+		get(region)
+	}
+	for region, total := range totals {
+		row := regionRowView{Region: region, Targets: names[region]}
+		row.LatencyAvgMs, row.HasLatency = mean(total.latency)
+		row.LossPct = total.loss
+		row.PMTU, row.HasPMTU = total.pmtu, total.hasPMTU
+		row.HopsAvg, row.HasHops = mean(total.hops)
+		row.HTTPAvgMs, row.HasHTTP = mean(total.http)
+		view.Rows = append(view.Rows, row)
+	}
+	sort.Slice(view.Rows, func(i, j int) bool {
+		return view.Rows[i].Region < view.Rows[j].Region
+	})
+	return view
+}
+
+func mean(values []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values)), true
+}
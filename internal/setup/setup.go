@@ -0,0 +1,266 @@
+package setup
+
+import (
+	"bytes"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"conncheck/internal/config"
+	"conncheck/internal/i18n"
+	"conncheck/internal/tests"
+)
+
+// Detected is what init learns about the host before asking anything.
+type Detected struct {
+	Resolvers []string
+	Gateway   string
+	IPv6      bool
+}
+
+// Detect inspects the host. Command output is written to a scratch folder
+// that is removed afterwards, since init has no run folder yet.
+func Detect() Detected {
+	scratch, err := os.MkdirTemp("", "conncheck-init-")
+	if err != nil {
+		return Detected{}
+	}
+	defer os.RemoveAll(scratch)
+	_ = os.MkdirAll(filepath.Join(scratch, "raw_logs"), 0o755)
+
+	var detected Detected
+	if resolvers, _, err := tests.SystemDNSServers(scratch); err == nil {
+		for _, resolver := range resolvers {
+			if _, err := netip.ParseAddr(resolver); err == nil {
+				detected.Resolvers = append(detected.Resolvers, resolver)
+			}
+		}
+	}
+	detected.Gateway, _, _ = tests.DetectDefaultGateway(scratch)
+	detected.IPv6, _ = tests.DetectIPv6(scratch)
+	return detected
+}
+
+// Region is a preset of DNS domains for a part of the world. Speedtest
+// servers are not part of it: ids go stale, so the generated file keeps the
+// defaults, which let the Speedtest CLI pick the nearest server.
+type Region struct {
+	Name    string
+	Domains []string
+}
+
+var regions = map[string]Region{
+	"it-north": {Domains: []string{"www.tim.it", "www.vodafone.it", "www.fastweb.it", "www.iliad.it", "www.windtre.it", "www.poste.it"}},
+	"it-south": {Domains: []string{"www.tim.it", "www.vodafone.it", "www.fastweb.it", "www.iliad.it", "www.windtre.it", "www.poste.it"}},
+	"eu":       {Domains: []string{"www.ripe.net", "www.ams-ix.net", "www.de-cix.net", "www.bbc.co.uk"}},
+	"us":       {Domains: []string{"www.nytimes.com", "www.amazon.com", "www.lumen.com", "www.arin.net"}},
+	"other":    {Domains: []string{"www.icann.org", "www.iana.org"}},
+}
+
+// Regions lists the preset names accepted by -region.
+func Regions() []string {
+	names := make([]string, 0, len(regions))
+	for name := range regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupRegion returns the preset for name.
+func LookupRegion(name string) (Region, error) {
+	region, ok := regions[strings.ToLower(name)]
+	if !ok {
+		return Region{}, fmt.Errorf("unknown region %q (available: %s)", name, strings.Join(Regions(), ", "))
+	}
+	region.Name = strings.ToLower(name)
+	return region, nil
+}
+
+// Answers are the choices made through flags or prompts.
+type Answers struct {
+	Region       string
+	DownloadMbps float64
+	UploadMbps   float64
+	Language     string
+}
+
+var (
+	commonDomains   = []string{"www.google.com", "www.cloudflare.com", "www.wikipedia.org", "www.youtube.com", "www.microsoft.com", "www.apple.com", "www.netflix.com", "github.com"}
+	publicResolvers = []string{"1.1.1.1", "8.8.8.8", "9.9.9.9"}
+	publicIPv6      = []string{"2606:4700:4700::1111", "2001:4860:4860::8888"}
+)
+
+// Render produces a commented conncheck.yaml for the detected host and the
+// answers, and checks it with config.ValidateData before returning it.
+func Render(detected Detected, answers Answers) ([]byte, error) {
+	region, err := LookupRegion(answers.Region)
+	if err != nil {
+		return nil, err
+	}
+	defaults := config.Default()
+	view := templateView{
+		Detected:    detected,
+		Answers:     answers,
+		Region:      region,
+		PingTargets: []string{"1.1.1.1", "8.8.8.8", "9.9.9.9"},
+		DNSServers:  unique(append(append([]string{}, detected.Resolvers...), publicResolvers...)),
+		DNSDomains:  unique(append(append([]string{}, commonDomains...), region.Domains...)),
+		MTUTargets:  []string{"1.1.1.1", "8.8.8.8"},
+		Speedtest:   defaults.Speedtest,
+		Bufferbloat: defaults.Bufferbloat,
+	}
+	if detected.IPv6 {
+		view.PingTargets = append(view.PingTargets, publicIPv6[0])
+		view.DNSServers = append(view.DNSServers, publicIPv6[0])
+		view.MTUTargets = append(view.MTUTargets, publicIPv6...)
+	}
+
+	var buf bytes.Buffer
+	if err := configTemplate.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+	issues, err := config.ValidateData(config.DefaultConfigFilename, buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		return nil, &config.ValidationError{Issues: issues}
+	}
+	return buf.Bytes(), nil
+}
+
+func unique(values []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		out = append(out, value)
+	}
+	return out
+}
+
+type templateView struct {
+	Detected    Detected
+	Answers     Answers
+	Region      Region
+	PingTargets []string
+	DNSServers  []string
+	DNSDomains  []string
+	MTUTargets  []string
+	Speedtest   config.Speedtest
+	Bufferbloat config.Bufferbloat
+}
+
+var configTemplate = template.Must(template.New("conncheck.yaml").Funcs(template.FuncMap{
+	"ids": func(values []int) string {
+		parts := make([]string, 0, len(values))
+		for _, value := range values {
+			parts = append(parts, fmt.Sprint(value))
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	},
+	"num": func(value float64) string {
+		return fmt.Sprintf("%g", value)
+	},
+	// comment renders a catalog message in the active language as YAML
+	// comment lines.
+	"comment": func(key string, args ...any) string {
+		return "# " + strings.ReplaceAll(i18n.T(key, args...), "\n", "\n# ")
+	},
+	"t": i18n.T,
+	"join": func(values []string) string {
+		return strings.Join(values, ", ")
+	},
+}).Parse(`{{comment "setup.yaml.header"}}
+#
+{{comment "setup.yaml.detected"}}
+{{if .Detected.Gateway}}{{comment "setup.yaml.gateway" .Detected.Gateway}}{{else}}{{comment "setup.yaml.gateway" (t "setup.yaml.not_found")}}{{end}}
+{{if .Detected.Resolvers}}{{comment "setup.yaml.resolvers" (join .Detected.Resolvers)}}{{else}}{{comment "setup.yaml.resolvers" (t "setup.yaml.none_found")}}{{end}}
+{{if .Detected.IPv6}}{{comment "setup.yaml.ipv6" (t "setup.yaml.available")}}{{else}}{{comment "setup.yaml.ipv6" (t "setup.yaml.not_available")}}{{end}}
+
+mode: standard
+privacy: standard
+{{comment "setup.yaml.language"}}
+language: {{.Answers.Language}}
+output_dir: ""
+
+tests:
+  preflight: true
+  lan_health: true
+  dualstack: {{.Detected.IPv6}}
+  dns_benchmark: true
+  mtu_pmtu: true
+  latency: true
+  bufferbloat: true
+  speedtest: true
+  traceroute: true
+  http_check: true
+  neighbors: true
+  interfaces: true
+  wifi: true
+
+{{comment "setup.yaml.plan"}}
+plan:
+  download_mbps: {{num .Answers.DownloadMbps}}
+  upload_mbps: {{num .Answers.UploadMbps}}
+
+targets:
+  ping_targets:
+{{- range .PingTargets}}
+    - {{.}}
+{{- end}}
+  {{comment "setup.yaml.dns_servers"}}
+  dns_servers:
+{{- range .DNSServers}}
+    - {{.}}
+{{- end}}
+  dns_domains:
+{{- range .DNSDomains}}
+    - {{.}}
+{{- end}}
+  dns_queries_per_domain: 5
+  traceroute_targets:
+    - 1.1.1.1
+    - 8.8.8.8
+  mtu_targets:
+{{- range .MTUTargets}}
+    - {{.}}
+{{- end}}
+
+{{comment "setup.yaml.speedtest"}}
+speedtest:
+  local:
+    server_ids: {{ids .Speedtest.Local.ServerIDs}}
+    runs: {{.Speedtest.Local.Runs}}
+    weight: {{num .Speedtest.Local.Weight}}
+  national:
+    server_ids: {{ids .Speedtest.National.ServerIDs}}
+    runs: {{.Speedtest.National.Runs}}
+    weight: {{num .Speedtest.National.Weight}}
+  eu:
+    server_ids: {{ids .Speedtest.EU.ServerIDs}}
+    runs: {{.Speedtest.EU.Runs}}
+    weight: {{num .Speedtest.EU.Weight}}
+  us:
+    server_ids: {{ids .Speedtest.US.ServerIDs}}
+    runs: {{.Speedtest.US.Runs}}
+    weight: {{num .Speedtest.US.Weight}}
+
+http:
+  endpoints:
+    - https://www.cloudflare.com
+    - https://www.google.com
+
+{{comment "setup.yaml.bufferbloat"}}
+bufferbloat:
+  download_url: {{.Bufferbloat.DownloadURL}}
+  upload_url: "{{.Bufferbloat.UploadURL}}"
+`))
@@ -27,11 +27,7 @@ func (b *Bufferbloat) Run(ctx context.Context) model.TestResult {
 	result.Status = StatusSkipped
 	result.Metrics["download_url"] = b.cfg.Bufferbloat.DownloadURL
 	result.Metrics["upload_url"] = b.cfg.Bufferbloat.UploadURL
-	result.Findings = append(result.Findings, model.Finding{
-		Severity: "INFO",
-		Title:    "Bufferbloat test pending",
-		Detail:   "Base version includes configuration for load tests; measurements will be added later.",
-	})
+	result.Findings = append(result.Findings, newFinding("INFO", "bufferbloat.pending"))
 	result.EndedAt = time.Now()
 	return result
 }
@@ -1,6 +1,9 @@
 package tests
 
-import "conncheck/internal/model"
+import (
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
+)
 
 const (
 	StatusOK      = "OK"
@@ -18,3 +21,29 @@ func baseResult(name string) model.TestResult {
 		Evidence: []model.Evidence{},
 	}
 }
+
+// newFinding builds a finding whose title and detail come from the message
+// catalog entries "<id>.title" and "<id>.detail"; args format the detail.
+func newFinding(severity, id string, args ...any) model.Finding {
+	return model.Finding{
+		ID:       id,
+		Severity: severity,
+		Title:    i18n.T(id + ".title"),
+		Detail:   i18n.T(id+".detail", args...),
+	}
+}
+
+var statusRank = map[string]int{
+	StatusSkipped: 0,
+	StatusOK:      1,
+	StatusWarn:    2,
+	StatusFail:    3,
+}
+
+// worseStatus returns the more severe of two statuses.
+func worseStatus(current, candidate string) string {
+	if statusRank[candidate] > statusRank[current] {
+		return candidate
+	}
+	return current
+}
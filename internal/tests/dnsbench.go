@@ -2,6 +2,7 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"os"
@@ -39,16 +40,12 @@ func (d *DNSBench) Run(ctx context.Context) model.TestResult {
 		domains = []string{"www.google.com", "www.cloudflare.com", "www.wikipedia.org"}
 	}
 
-	systemServers, evidence, err := systemDNSServers(d.outDir)
+	systemServers, evidence, err := SystemDNSServers(d.outDir)
 	if evidence != nil {
 		result.Evidence = append(result.Evidence, *evidence)
 	}
 	if err != nil {
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "Unable to read system DNS servers",
-			Detail:   err.Error(),
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "dns.system_servers_unreadable", err.Error()))
 	}
 
 	configServers := d.cfg.Targets.DNSServers
@@ -67,11 +64,7 @@ func (d *DNSBench) Run(ctx context.Context) model.TestResult {
 	result.Metrics["dns_servers"] = joinList(allServers)
 
 	if len(allServers) == 0 {
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "No DNS servers available",
-			Detail:   "Provide DNS servers in config or ensure DHCP provides resolvers.",
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "dns.no_servers"))
 		result.EndedAt = time.Now()
 		return result
 	}
@@ -82,8 +75,10 @@ func (d *DNSBench) Run(ctx context.Context) model.TestResult {
 		fail    int
 		avgMs   float64
 		hasAvg  bool
+		samples []dnsQuerySample
 	}
 
+	started := result.StartedAt
 	results := make(chan serverResult, len(allServers))
 	var wg sync.WaitGroup
 
@@ -94,9 +89,17 @@ func (d *DNSBench) Run(ctx context.Context) model.TestResult {
 			serverSuccess := 0
 			serverFail := 0
 			var totalLatency time.Duration
+			var samples []dnsQuerySample
 			for _, domain := range domains {
 				for i := 0; i < queriesPerDomain; i++ {
+					offset := time.Since(started)
 					latency, err := dnsLookupLatency(ctx, server, domain, 2*time.Second)
+					samples = append(samples, dnsQuerySample{
+						OffsetMs:  int(offset.Milliseconds()),
+						Domain:    domain,
+						LatencyMs: float64(latency.Microseconds()) / 1000,
+						OK:        err == nil,
+					})
 					if err != nil {
 						serverFail++
 						continue
@@ -110,6 +113,7 @@ func (d *DNSBench) Run(ctx context.Context) model.TestResult {
 				server:  server,
 				success: serverSuccess,
 				fail:    serverFail,
+				samples: samples,
 			}
 			if serverSuccess > 0 {
 				result.avgMs = float64(totalLatency.Milliseconds()) / float64(serverSuccess)
@@ -134,6 +138,9 @@ func (d *DNSBench) Run(ctx context.Context) model.TestResult {
 		}
 		result.Metrics[fmt.Sprintf("dns_success.%s", serverResult.server)] = strconv.Itoa(serverResult.success)
 		result.Metrics[fmt.Sprintf("dns_fail.%s", serverResult.server)] = strconv.Itoa(serverResult.fail)
+		if seriesJSON, err := json.Marshal(serverResult.samples); err == nil {
+			result.Metrics[fmt.Sprintf("dns_series.%s", serverResult.server)] = string(seriesJSON)
+		}
 	}
 
 	result.Metrics["dns_success_total"] = strconv.Itoa(totalSuccess)
@@ -141,18 +148,10 @@ func (d *DNSBench) Run(ctx context.Context) model.TestResult {
 
 	if totalSuccess == 0 {
 		result.Status = StatusFail
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "FAIL",
-			Title:    "DNS benchmark failed",
-			Detail:   "All DNS queries failed for every server.",
-		})
+		result.Findings = append(result.Findings, newFinding("FAIL", "dns.all_failed"))
 	} else if totalFail > 0 {
 		result.Status = StatusWarn
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "Partial DNS failures detected",
-			Detail:   fmt.Sprintf("%d queries failed out of %d.", totalFail, totalSuccess+totalFail),
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "dns.partial_failures", totalFail, totalSuccess+totalFail))
 	} else {
 		result.Status = StatusOK
 	}
@@ -161,6 +160,15 @@ func (d *DNSBench) Run(ctx context.Context) model.TestResult {
 	return result
 }
 
+// dnsQuerySample is one query against a resolver; t is the offset from the
+// start of the test.
+type dnsQuerySample struct {
+	OffsetMs  int     `json:"t"`
+	Domain    string  `json:"domain"`
+	LatencyMs float64 `json:"ms"`
+	OK        bool    `json:"ok"`
+}
+
 func dnsLookupLatency(ctx context.Context, server, domain string, timeout time.Duration) (time.Duration, error) {
 	resolver := &net.Resolver{
 		PreferGo: true,
@@ -177,7 +185,9 @@ func dnsLookupLatency(ctx context.Context, server, domain string, timeout time.D
 	return time.Since(start), err
 }
 
-func systemDNSServers(outDir string) ([]string, *model.Evidence, error) {
+// SystemDNSServers returns the resolvers configured on the host (ipconfig /all
+// on Windows, /etc/resolv.conf elsewhere).
+func SystemDNSServers(outDir string) ([]string, *model.Evidence, error) {
 	if runtime.GOOS == "windows" {
 		output, logPath, err := sys.RunCommand(outDir, "ipconfig", "/all")
 		if err != nil {
@@ -2,6 +2,8 @@ package tests
 
 import (
 	"context"
+	"net"
+	"net/netip"
 	"runtime"
 	"strings"
 	"time"
@@ -26,33 +28,16 @@ func (d *DualStack) Run(ctx context.Context) model.TestResult {
 	result := baseResult(d.Name())
 	result.StartedAt = time.Now()
 
-	ipv6Present := false
-	if runtime.GOOS == "windows" {
-		output, logPath, err := sys.RunCommand(d.outDir, "ipconfig")
-		if logPath != "" {
-			result.Evidence = append(result.Evidence, model.Evidence{Label: "ipconfig", Path: logPath})
-		}
-		if err == nil && strings.Contains(output, "IPv6") {
-			ipv6Present = true
-		}
-	} else {
-		output, logPath, err := sys.RunCommand(d.outDir, "ip", "-6", "addr")
-		if logPath != "" {
-			result.Evidence = append(result.Evidence, model.Evidence{Label: "ip_addr_v6", Path: logPath})
-		}
-		if err == nil && strings.Contains(output, "inet6") {
-			ipv6Present = true
-		}
+	ipv6Present, evidence := DetectIPv6(d.outDir)
+	if evidence.Path != "" {
+		result.Evidence = append(result.Evidence, evidence)
 	}
 
+	result.Metrics["ipv4_present"] = boolString(hasIPv4Address())
 	result.Metrics["ipv6_present"] = boolString(ipv6Present)
 	if !ipv6Present {
 		result.Status = StatusSkipped
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "INFO",
-			Title:    "IPv6 not detected",
-			Detail:   "No IPv6 addresses were found on active interfaces.",
-		})
+		result.Findings = append(result.Findings, newFinding("INFO", "dualstack.ipv6_absent"))
 		result.EndedAt = time.Now()
 		return result
 	}
@@ -68,17 +53,44 @@ func (d *DualStack) Run(ctx context.Context) model.TestResult {
 	result.Status = StatusOK
 	if ipv6Present && !ipv6OK {
 		result.Status = StatusWarn
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "IPv6 appears broken",
-			Detail:   "IPv6 is present but connectivity tests failed.",
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "dualstack.ipv6_broken"))
 	}
 
 	result.EndedAt = time.Now()
 	return result
 }
 
+// DetectIPv6 reports whether any interface has an IPv6 address, together with
+// the command output used to decide.
+func DetectIPv6(outDir string) (bool, model.Evidence) {
+	if runtime.GOOS == "windows" {
+		output, logPath, err := sys.RunCommand(outDir, "ipconfig")
+		return err == nil && strings.Contains(output, "IPv6"), model.Evidence{Label: "ipconfig", Path: logPath}
+	}
+	output, logPath, err := sys.RunCommand(outDir, "ip", "-6", "addr")
+	return err == nil && strings.Contains(output, "inet6"), model.Evidence{Label: "ip_addr_v6", Path: logPath}
+}
+
+// hasIPv4Address reports whether an interface has an IPv4 address other than
+// loopback and the link-local range hosts assign themselves without DHCP.
+func hasIPv4Address() bool {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		prefix, err := netip.ParsePrefix(addr.String())
+		if err != nil {
+			continue
+		}
+		ip := prefix.Addr().Unmap()
+		if ip.Is4() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() {
+			return true
+		}
+	}
+	return false
+}
+
 func pingOnce(outDir, target string) bool {
 	var output string
 	var err error
@@ -8,12 +8,15 @@ import (
 	"conncheck/internal/model"
 )
 
-func CollectEnvironment() model.Environment {
+// CollectEnvironment describes the machine the run happened on, including
+// the link state of the interface carrying the default route.
+func CollectEnvironment(outDir string) model.Environment {
 	hostname, _ := os.Hostname()
 	return model.Environment{
 		OS:       runtime.GOOS,
 		Arch:     runtime.GOARCH,
 		Hostname: hostname,
 		Timezone: time.Now().Format("MST"),
+		Link:     readLink(outDir),
 	}
 }
@@ -1,8 +1,17 @@
 package tests
 
 import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"os/exec"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"conncheck/internal/sys"
 )
@@ -35,3 +44,85 @@ func DetectDefaultGateway(outDir string) (string, string, error) {
 	}
 	return "", logPath, nil
 }
+
+// gatewayProbe sends one request and returns its round-trip time in
+// milliseconds, or lost.
+type gatewayProbe func(ctx context.Context) (float64, bool)
+
+// gatewayTCPPorts are the router services tried when ICMP is unreliable: the
+// admin UI and the DNS forwarder.
+var gatewayTCPPorts = []int{80, 443, 53}
+
+var arpingReplyRe = regexp.MustCompile(`(?i)(?:reply from|bytes from).*?([\d.]+)\s*m(?:s|sec)\b`)
+
+func icmpProbe(gateway string) gatewayProbe {
+	return func(ctx context.Context) (float64, bool) {
+		return pingRTT(ctx, gateway, "")
+	}
+}
+
+// tcpProbe times a TCP connect to the gateway. A refused connection counts
+// as an answer: the reset comes from the router itself.
+func tcpProbe(gateway string, port int) gatewayProbe {
+	address := net.JoinHostPort(gateway, strconv.Itoa(port))
+	return func(ctx context.Context) (float64, bool) {
+		dialCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		start := time.Now()
+		conn, err := (&net.Dialer{}).DialContext(dialCtx, "tcp", address)
+		elapsed := float64(time.Since(start).Microseconds()) / 1000
+		if err == nil {
+			conn.Close()
+			return elapsed, false
+		}
+		if errors.Is(err, syscall.ECONNREFUSED) || strings.Contains(err.Error(), "refused") {
+			return elapsed, false
+		}
+		return -1, true
+	}
+}
+
+// arpingProbe times an ARP request to an IPv4 gateway with arping, which
+// usually needs raw-socket privileges; nil when arping is not installed.
+// IPv6 gateways get no link-layer probe: there is no NDP counterpart.
+func arpingProbe(gateway string) gatewayProbe {
+	if addr, err := netip.ParseAddr(gateway); err != nil || !addr.Is4() {
+		return nil
+	}
+	if _, err := exec.LookPath("arping"); err != nil {
+		return nil
+	}
+	return func(ctx context.Context) (float64, bool) {
+		probeCtx, cancel := context.WithTimeout(ctx, 1500*time.Millisecond)
+		defer cancel()
+		output, _ := sys.RunCommandNoLog(probeCtx, "arping", "-c", "1", "-w", "1", gateway)
+		if m := arpingReplyRe.FindStringSubmatch(output); len(m) == 2 {
+			return parseLatencyValue(m[1]), false
+		}
+		return -1, true
+	}
+}
+
+// gatewayFallback is a probe that answered where ICMP did not.
+type gatewayFallback struct {
+	Method string
+	Port   int
+	Probe  gatewayProbe
+}
+
+// findGatewayFallback tries ARP first, since it measures the link itself,
+// then TCP to gatewayTCPPorts, and returns the first probe that answers.
+func findGatewayFallback(ctx context.Context, gateway string) (gatewayFallback, bool) {
+	if probe := arpingProbe(gateway); probe != nil {
+		if _, lost := probe(ctx); !lost {
+			return gatewayFallback{Method: "arp", Probe: probe}, true
+		}
+	}
+	for _, port := range gatewayTCPPorts {
+		probe := tcpProbe(gateway, port)
+		if _, lost := probe(ctx); !lost {
+			return gatewayFallback{Method: "tcp", Port: port, Probe: probe}, true
+		}
+	}
+	return gatewayFallback{}, false
+}
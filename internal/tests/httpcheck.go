@@ -25,12 +25,9 @@ func (h *HTTPCheck) Run(ctx context.Context) model.TestResult {
 	result := baseResult(h.Name())
 	result.StartedAt = time.Now()
 	result.Status = StatusSkipped
-	result.Metrics["endpoints"] = joinList(h.cfg.HTTP.Endpoints)
-	result.Findings = append(result.Findings, model.Finding{
-		Severity: "INFO",
-		Title:    "HTTP timing checks pending",
-		Detail:   "Endpoints are configured; timing probes will be added in a future version.",
-	})
+	endpoints := h.cfg.Targets.ResolveEndpoints(h.cfg.HTTP.Endpoints)
+	result.Metrics["endpoints"] = joinList(config.Addresses(endpoints))
+	result.Findings = append(result.Findings, newFinding("INFO", "http.pending"))
 	result.EndedAt = time.Now()
 	return result
 }
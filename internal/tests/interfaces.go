@@ -0,0 +1,369 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"conncheck/internal/config"
+	"conncheck/internal/model"
+	"conncheck/internal/sys"
+)
+
+// interfaceErrorFailRatio is the share of packets with errors above which a
+// growing error counter fails the test instead of warning: one bad frame in
+// a thousand is far beyond what a sound cable or radio link produces.
+const interfaceErrorFailRatio = 0.001
+
+// aggregateInterface names the totals of `netstat -e`, which Windows does
+// not split by adapter.
+const aggregateInterface = "all"
+
+// interfaceErrorCounters grow on bad cables, duplex mismatches and flaky
+// radios; interfaceDropCounters also count harmless discards (unknown
+// protocols, VLAN tags), so they only make an INFO finding.
+var (
+	interfaceErrorCounters = []string{"rx_errors", "rx_crc_errors", "rx_frame_errors", "rx_fifo_errors", "tx_errors", "tx_carrier_errors", "collisions"}
+	interfaceDropCounters  = []string{"rx_dropped", "tx_dropped"}
+	netstatNumbersRe       = regexp.MustCompile(`^\D*?(\d+)\s+(\d+)\s*$`)
+)
+
+// interfaceCounters maps interface name to counter name to value.
+type interfaceCounters map[string]map[string]uint64
+
+// Interfaces snapshots the interface counters when the run starts and when
+// it ends, and reports the errors and drops of the active interface along
+// with the duplex and speed of the link carrying the default route.
+type Interfaces struct {
+	outDir string
+	plan   config.Plan
+	link   *model.Link
+
+	start    interfaceCounters
+	startAt  time.Time
+	evidence []model.Evidence
+}
+
+func NewInterfaces(outDir string, plan config.Plan, link *model.Link) *Interfaces {
+	return &Interfaces{outDir: outDir, plan: plan, link: link}
+}
+
+func (i *Interfaces) Name() string {
+	return "interfaces"
+}
+
+func (i *Interfaces) Start(ctx context.Context) {
+	i.start = i.snapshot("counters_start")
+	i.startAt = time.Now()
+}
+
+func (i *Interfaces) Run(ctx context.Context) model.TestResult {
+	result := baseResult(i.Name())
+	result.StartedAt = time.Now()
+	end := i.snapshot("counters_end")
+	result.Evidence = append(result.Evidence, i.evidence...)
+	checkLink(&result, i.link, i.plan)
+	if i.start == nil || end == nil {
+		// Without Start there is nothing to compare; a platform without a
+		// counter source has neither snapshot.
+		result.Findings = append(result.Findings, newFinding("INFO", "interfaces.unavailable", runtime.GOOS))
+		result.EndedAt = time.Now()
+		return result
+	}
+
+	iface := activeInterface(i.start, end)
+	if iface == "" {
+		result.Findings = append(result.Findings, newFinding("INFO", "interfaces.no_active"))
+		result.EndedAt = time.Now()
+		return result
+	}
+	result.Status = worseStatus(result.Status, StatusOK)
+	result.Metrics["interface"] = iface
+	if iface == aggregateInterface {
+		result.Findings = append(result.Findings, newFinding("INFO", "interfaces.machine_wide"))
+	}
+	result.Metrics["duration_s"] = fmt.Sprintf("%.0f", time.Since(i.startAt).Seconds())
+
+	before, after := i.start[iface], end[iface]
+	deltas := map[string]uint64{}
+	for name, value := range after {
+		previous, ok := before[name]
+		if !ok {
+			continue
+		}
+		if value < previous {
+			// Counters only go down when the driver reset them, usually
+			// because the link went down and up again during the run.
+			result.Status = StatusWarn
+			result.Findings = append(result.Findings, newFinding("WARN", "interfaces.counters_reset", iface))
+			result.EndedAt = time.Now()
+			return result
+		}
+		deltas[name] = value - previous
+	}
+	for _, name := range append(append([]string{"rx_bytes", "rx_packets", "tx_bytes", "tx_packets"}, interfaceErrorCounters...), interfaceDropCounters...) {
+		if delta, ok := deltas[name]; ok {
+			result.Metrics[name+"_delta"] = strconv.FormatUint(delta, 10)
+		}
+	}
+
+	packets := deltas["rx_packets"] + deltas["tx_packets"]
+	var grown []string
+	var errors uint64
+	for _, name := range interfaceErrorCounters {
+		if deltas[name] > 0 {
+			grown = append(grown, fmt.Sprintf("%s +%d", name, deltas[name]))
+			if name != "rx_crc_errors" && name != "rx_frame_errors" && name != "rx_fifo_errors" {
+				// The detailed receive counters are already part of
+				// rx_errors.
+				errors += deltas[name]
+			}
+		}
+	}
+	if len(grown) > 0 {
+		ratio := 0.0
+		if packets > 0 {
+			ratio = float64(errors) / float64(packets)
+		}
+		result.Metrics["error_ratio"] = fmt.Sprintf("%.6f", ratio)
+		severity := StatusWarn
+		if ratio >= interfaceErrorFailRatio {
+			severity = StatusFail
+		}
+		result.Status = worseStatus(result.Status, severity)
+		result.Findings = append(result.Findings, newFinding(severity, "interfaces.errors", iface, strings.Join(grown, ", "), ratio*100, packets))
+	}
+	var dropped []string
+	for _, name := range interfaceDropCounters {
+		if deltas[name] > 0 {
+			dropped = append(dropped, fmt.Sprintf("%s +%d", name, deltas[name]))
+		}
+	}
+	if len(dropped) > 0 {
+		result.Findings = append(result.Findings, newFinding("INFO", "interfaces.drops", iface, strings.Join(dropped, ", ")))
+	}
+
+	result.EndedAt = time.Now()
+	return result
+}
+
+// snapshot reads every interface's counters and keeps the raw source as
+// evidence. It returns nil when the platform has no counter source.
+func (i *Interfaces) snapshot(label string) interfaceCounters {
+	switch runtime.GOOS {
+	case "linux":
+		counters, raw := readLinuxCounters()
+		if counters == nil {
+			return nil
+		}
+		if path := writeRawLog(i.outDir, label, raw); path != "" {
+			i.evidence = append(i.evidence, model.Evidence{Label: label, Path: path})
+		}
+		return counters
+	case "windows":
+		// Get-NetAdapterStatistics has per-adapter counters; netstat -e,
+		// the fallback where it is missing, only has machine-wide totals.
+		output, logPath, err := sys.RunCommand(i.outDir, "powershell", "-NoProfile", "-Command",
+			"Get-NetAdapterStatistics | Format-List "+strings.Join(netAdapterStatisticsFields, ","))
+		if err == nil {
+			if counters := parseNetAdapterStatistics(output); len(counters) > 0 {
+				i.evidence = append(i.evidence, model.Evidence{Label: label, Path: logPath})
+				return counters
+			}
+		}
+		output, logPath, err = sys.RunCommand(i.outDir, "netstat", "-e")
+		evidence := model.Evidence{Label: label, Path: logPath}
+		if err != nil {
+			evidence.Note = err.Error()
+		}
+		i.evidence = append(i.evidence, evidence)
+		if err != nil {
+			return nil
+		}
+		return parseNetstatE(output)
+	}
+	return nil
+}
+
+// readLinuxCounters reads /sys/class/net/*/statistics, which has the
+// detailed error counters, falling back to /proc/net/dev. raw is a text dump
+// of what was read.
+func readLinuxCounters() (interfaceCounters, string) {
+	dirs, _ := filepath.Glob("/sys/class/net/*/statistics")
+	if len(dirs) > 0 {
+		counters := interfaceCounters{}
+		var raw strings.Builder
+		for _, dir := range dirs {
+			iface := filepath.Base(filepath.Dir(dir))
+			files, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+			counters[iface] = map[string]uint64{}
+			for _, file := range files {
+				data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+				if err != nil {
+					continue
+				}
+				value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+				if err != nil {
+					continue
+				}
+				counters[iface][file.Name()] = value
+				fmt.Fprintf(&raw, "%s %s %d\n", iface, file.Name(), value)
+			}
+		}
+		return counters, raw.String()
+	}
+	data, err := os.ReadFile("/proc/net/dev")
+	if err != nil {
+		return nil, ""
+	}
+	return parseProcNetDev(string(data)), string(data)
+}
+
+// procNetDevColumns are the columns of /proc/net/dev after the interface
+// name, receive side first.
+var procNetDevColumns = []string{
+	"rx_bytes", "rx_packets", "rx_errors", "rx_dropped", "rx_fifo_errors", "rx_frame_errors", "rx_compressed", "multicast",
+	"tx_bytes", "tx_packets", "tx_errors", "tx_dropped", "tx_fifo_errors", "collisions", "tx_carrier_errors", "tx_compressed",
+}
+
+func parseProcNetDev(output string) interfaceCounters {
+	counters := interfaceCounters{}
+	for _, line := range strings.Split(output, "\n") {
+		name, values, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(values)
+		if len(fields) < len(procNetDevColumns) {
+			continue
+		}
+		iface := strings.TrimSpace(name)
+		counters[iface] = map[string]uint64{}
+		for col, column := range procNetDevColumns {
+			if value, err := strconv.ParseUint(fields[col], 10, 64); err == nil {
+				counters[iface][column] = value
+			}
+		}
+	}
+	return counters
+}
+
+// netAdapterStatisticsFields are the Get-NetAdapterStatistics properties
+// read, listed by name so the output does not depend on the locale.
+var netAdapterStatisticsFields = []string{
+	"Name",
+	"ReceivedBytes", "ReceivedUnicastPackets", "ReceivedMulticastPackets", "ReceivedBroadcastPackets", "ReceivedDiscardedPackets", "ReceivedPacketErrors",
+	"SentBytes", "SentUnicastPackets", "SentMulticastPackets", "SentBroadcastPackets", "OutboundDiscardedPackets", "OutboundPacketErrors",
+}
+
+// parseNetAdapterStatistics parses Get-NetAdapterStatistics records, keyed
+// by adapter name.
+func parseNetAdapterStatistics(output string) interfaceCounters {
+	counters := interfaceCounters{}
+	for _, record := range parseFormatList(output) {
+		name := record["Name"]
+		if name == "" {
+			continue
+		}
+		value := func(field string) uint64 {
+			v, _ := strconv.ParseUint(record[field], 10, 64)
+			return v
+		}
+		counters[name] = map[string]uint64{
+			"rx_bytes":   value("ReceivedBytes"),
+			"rx_packets": value("ReceivedUnicastPackets") + value("ReceivedMulticastPackets") + value("ReceivedBroadcastPackets"),
+			"rx_dropped": value("ReceivedDiscardedPackets"),
+			"rx_errors":  value("ReceivedPacketErrors"),
+			"tx_bytes":   value("SentBytes"),
+			"tx_packets": value("SentUnicastPackets") + value("SentMulticastPackets") + value("SentBroadcastPackets"),
+			"tx_dropped": value("OutboundDiscardedPackets"),
+			"tx_errors":  value("OutboundPacketErrors"),
+		}
+	}
+	return counters
+}
+
+// netstatERows are the rows of `netstat -e` with a received and a sent
+// column, in order. Row titles are localized, so rows are matched by
+// position.
+var netstatERows = [][2]string{
+	{"rx_bytes", "tx_bytes"},
+	{"rx_packets", "tx_packets"},
+	{"rx_multicast_packets", "tx_multicast_packets"},
+	{"rx_dropped", "tx_dropped"},
+	{"rx_errors", "tx_errors"},
+}
+
+func parseNetstatE(output string) interfaceCounters {
+	totals := map[string]uint64{}
+	row := 0
+	for _, line := range strings.Split(output, "\n") {
+		if row == len(netstatERows) {
+			break
+		}
+		m := netstatNumbersRe.FindStringSubmatch(strings.TrimSpace(line))
+		if len(m) != 3 {
+			continue
+		}
+		totals[netstatERows[row][0]], _ = strconv.ParseUint(m[1], 10, 64)
+		totals[netstatERows[row][1]], _ = strconv.ParseUint(m[2], 10, 64)
+		row++
+	}
+	if row == 0 {
+		return nil
+	}
+	// Unicast and non-unicast packets together are the packet totals.
+	totals["rx_packets"] += totals["rx_multicast_packets"]
+	totals["tx_packets"] += totals["tx_multicast_packets"]
+	return interfaceCounters{aggregateInterface: totals}
+}
+
+// activeInterface picks the interface carrying the default route, or else
+// the one that moved the most traffic between the snapshots.
+func activeInterface(start, end interfaceCounters) string {
+	if _, ok := end[aggregateInterface]; ok {
+		return aggregateInterface
+	}
+	if _, iface := localAddressFor("1.1.1.1"); iface != "" {
+		if _, ok := end[iface]; ok {
+			return iface
+		}
+	}
+	names := make([]string, 0, len(end))
+	for name := range end {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	best, bestBytes := "", uint64(0)
+	for _, name := range names {
+		if isLoopbackInterface(name) {
+			continue
+		}
+		moved := uint64(0)
+		for _, counter := range []string{"rx_bytes", "tx_bytes"} {
+			if end[name][counter] > start[name][counter] {
+				moved += end[name][counter] - start[name][counter]
+			}
+		}
+		if moved > bestBytes {
+			best, bestBytes = name, moved
+		}
+	}
+	return best
+}
+
+func isLoopbackInterface(name string) bool {
+	iface, err := net.InterfaceByName(name)
+	return err == nil && iface.Flags&net.FlagLoopback != 0
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProcNetDev(t *testing.T) {
+	const output = "Inter-|   Receive                                                |  Transmit\n" +
+		" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+		"    lo: 1234567    8901    0    0    0     0          0         0  1234567    8901    0    0    0     0       0          0\n" +
+		"  eth0: 987654321 654321   12    3    1     5          0      1200 123456789 234567    4    2    0     7       2          0\n" +
+		"wlan0: 42 1 0 0\n"
+	got := parseProcNetDev(output)
+	want := interfaceCounters{
+		"lo": {
+			"rx_bytes": 1234567, "rx_packets": 8901, "rx_errors": 0, "rx_dropped": 0, "rx_fifo_errors": 0, "rx_frame_errors": 0, "rx_compressed": 0, "multicast": 0,
+			"tx_bytes": 1234567, "tx_packets": 8901, "tx_errors": 0, "tx_dropped": 0, "tx_fifo_errors": 0, "collisions": 0, "tx_carrier_errors": 0, "tx_compressed": 0,
+		},
+		"eth0": {
+			"rx_bytes": 987654321, "rx_packets": 654321, "rx_errors": 12, "rx_dropped": 3, "rx_fifo_errors": 1, "rx_frame_errors": 5, "rx_compressed": 0, "multicast": 1200,
+			"tx_bytes": 123456789, "tx_packets": 234567, "tx_errors": 4, "tx_dropped": 2, "tx_fifo_errors": 0, "collisions": 7, "tx_carrier_errors": 2, "tx_compressed": 0,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseProcNetDev =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestParseNetstatE(t *testing.T) {
+	// Both locales give the same figures; only the row titles differ.
+	want := interfaceCounters{aggregateInterface: {
+		"rx_bytes": 3458923452, "tx_bytes": 283746234,
+		"rx_packets": 4587234 + 23423, "tx_packets": 2934823 + 3423,
+		"rx_multicast_packets": 23423, "tx_multicast_packets": 3423,
+		"rx_dropped": 5, "tx_dropped": 0,
+		"rx_errors": 2, "tx_errors": 1,
+	}}
+	tests := []struct {
+		name   string
+		output string
+		want   interfaceCounters
+	}{
+		{
+			name: "English",
+			output: "Interface Statistics\r\n\r\n" +
+				"                           Received            Sent\r\n\r\n" +
+				"Bytes                    3458923452      283746234\r\n" +
+				"Unicast packets             4587234        2934823\r\n" +
+				"Non-unicast packets           23423           3423\r\n" +
+				"Discards                          5              0\r\n" +
+				"Errors                            2              1\r\n" +
+				"Unknown protocols                 0\r\n",
+			want: want,
+		},
+		{
+			name: "Italian",
+			output: "Statistiche interfaccia\r\n\r\n" +
+				"                           Ricevuti           Inviati\r\n\r\n" +
+				"Byte                     3458923452      283746234\r\n" +
+				"Pacchetti unicast           4587234        2934823\r\n" +
+				"Pacchetti non unicast         23423           3423\r\n" +
+				"Scartati                          5              0\r\n" +
+				"Errori                            2              1\r\n" +
+				"Protocolli sconosciuti            0\r\n",
+			want: want,
+		},
+		{
+			name:   "no figures",
+			output: "The requested operation requires elevation.\r\n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNetstatE(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNetstatE =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNetAdapterStatistics(t *testing.T) {
+	const output = "\r\n\r\n" +
+		"Name                     : Ethernet\r\n" +
+		"ReceivedBytes            : 987654321\r\n" +
+		"ReceivedUnicastPackets   : 654000\r\n" +
+		"ReceivedMulticastPackets : 300\r\n" +
+		"ReceivedBroadcastPackets : 21\r\n" +
+		"ReceivedDiscardedPackets : 3\r\n" +
+		"ReceivedPacketErrors     : 12\r\n" +
+		"SentBytes                : 123456789\r\n" +
+		"SentUnicastPackets       : 234000\r\n" +
+		"SentMulticastPackets     : 500\r\n" +
+		"SentBroadcastPackets     : 67\r\n" +
+		"OutboundDiscardedPackets : 0\r\n" +
+		"OutboundPacketErrors     : 1\r\n" +
+		"\r\n" +
+		"Name                     : Wi-Fi\r\n" +
+		"ReceivedBytes            : 0\r\n" +
+		"ReceivedUnicastPackets   : 0\r\n" +
+		"ReceivedMulticastPackets : 0\r\n" +
+		"ReceivedBroadcastPackets : 0\r\n" +
+		"ReceivedDiscardedPackets : 0\r\n" +
+		"ReceivedPacketErrors     : 0\r\n" +
+		"SentBytes                : 0\r\n" +
+		"SentUnicastPackets       : 0\r\n" +
+		"SentMulticastPackets     : 0\r\n" +
+		"SentBroadcastPackets     : 0\r\n" +
+		"OutboundDiscardedPackets : 0\r\n" +
+		"OutboundPacketErrors     : 0\r\n\r\n"
+	got := parseNetAdapterStatistics(output)
+	want := interfaceCounters{
+		"Ethernet": {
+			"rx_bytes": 987654321, "rx_packets": 654321, "rx_dropped": 3, "rx_errors": 12,
+			"tx_bytes": 123456789, "tx_packets": 234567, "tx_dropped": 0, "tx_errors": 1,
+		},
+		"Wi-Fi": {
+			"rx_bytes": 0, "rx_packets": 0, "rx_dropped": 0, "rx_errors": 0,
+			"tx_bytes": 0, "tx_packets": 0, "tx_dropped": 0, "tx_errors": 0,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNetAdapterStatistics =\n%v\nwant\n%v", got, want)
+	}
+}
@@ -2,20 +2,41 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"runtime"
+	"math"
+	"net/netip"
+	"strings"
 	"time"
 
+	"conncheck/internal/config"
+	"conncheck/internal/i18n"
 	"conncheck/internal/model"
-	"conncheck/internal/sys"
+)
+
+// gatewayFallbackSamples caps the second series taken with a fallback probe,
+// which only has to tell ICMP rate limiting from real loss.
+const gatewayFallbackSamples = 50
+
+// gatewayBurstNotice is the run of consecutive lost echo requests worth a
+// finding on its own, even when the overall loss stays under the threshold.
+const gatewayBurstNotice = 3
+
+// A gateway is only taken to rate-limit ping when at least
+// gatewayRateLimitMinLost echo requests, and gatewayRateLimitMinLossPct of
+// them, were lost: a single lost ping says nothing about rate limiting.
+const (
+	gatewayRateLimitMinLost    = 2
+	gatewayRateLimitMinLossPct = 10.0
 )
 
 type LAN struct {
 	outDir string
+	cfg    config.Config
 }
 
-func NewLAN(outDir string) *LAN {
-	return &LAN{outDir: outDir}
+func NewLAN(outDir string, cfg config.Config) *LAN {
+	return &LAN{outDir: outDir, cfg: cfg}
 }
 
 func (l *LAN) Name() string {
@@ -32,63 +53,300 @@ func (l *LAN) Run(ctx context.Context) model.TestResult {
 	}
 	if err != nil {
 		result.Status = StatusSkipped
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "Gateway detection failed",
-			Detail:   err.Error(),
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "lan.gateway_detection_failed", err.Error()))
 		result.EndedAt = time.Now()
 		return result
 	}
 	if gateway == "" {
 		result.Status = StatusSkipped
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "Gateway not found",
-			Detail:   "Unable to locate default gateway from routing table.",
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "lan.gateway_not_found"))
 		result.EndedAt = time.Now()
 		return result
 	}
 
-	var output string
-	var pingLog string
-	if runtime.GOOS == "windows" {
-		output, pingLog, err = sys.RunCommand(l.outDir, "ping", "-n", "20", gateway)
-	} else {
-		output, pingLog, err = sys.RunCommand(l.outDir, "ping", "-c", "20", gateway)
+	interval := time.Duration(l.cfg.Gateway.IntervalMs) * time.Millisecond
+	icmp := sampleGateway(ctx, gateway, icmpProbe(gateway), l.cfg.Gateway.Samples, interval)
+	icmpStats := summarizeGateway(icmp)
+	result.Metrics["gateway"] = gateway
+	result.Metrics["interval_ms"] = fmt.Sprintf("%d", l.cfg.Gateway.IntervalMs)
+	result.Metrics["icmp_sent"] = fmt.Sprintf("%d", icmpStats.Sent)
+	result.Metrics["icmp_received"] = fmt.Sprintf("%d", icmpStats.Received)
+	result.Metrics["icmp_loss_pct"] = fmt.Sprintf("%.1f", icmpStats.LossPct)
+
+	method, samples, stats := "icmp", icmp, icmpStats
+	icmpLoss := "none"
+	if icmpStats.Received < icmpStats.Sent {
+		// Loss seen by ping alone may be the router rate-limiting or dropping
+		// ICMP: measure again with a probe it cannot treat differently.
+		icmpLoss = "unverified"
+		if addr, err := netip.ParseAddr(gateway); err == nil && addr.Is6() {
+			// Only TCP can stand in for ping here: there is no NDP probe.
+			result.Metrics["link_timing"] = "unavailable"
+			result.Findings = append(result.Findings, newFinding("INFO", "lan.no_link_timing", gateway))
+		}
+		if fallback, ok := findGatewayFallback(ctx, gateway); ok {
+			count := min(l.cfg.Gateway.Samples, gatewayFallbackSamples)
+			fallbackSamples := sampleGateway(ctx, gateway, fallback.Probe, count, interval)
+			fallbackStats := summarizeGateway(fallbackSamples)
+			result.Metrics["fallback_method"] = fallback.Method
+			result.Metrics["fallback_loss_pct"] = fmt.Sprintf("%.1f", fallbackStats.LossPct)
+			if fallback.Port > 0 {
+				result.Metrics["fallback_port"] = fmt.Sprintf("%d", fallback.Port)
+			}
+			icmpLoss = icmpLossVerdict(icmpStats, fallbackStats)
+			if icmpLoss == "rate_limited" || icmpLoss == "blocked" {
+				method, samples, stats = fallback.Method, fallbackSamples, fallbackStats
+				result.Findings = append(result.Findings, newFinding("INFO", "lan.icmp_"+icmpLoss, gateway, icmpStats.LossPct, probeLabel(fallback.Method, fallback.Port), fallbackStats.LossPct))
+			}
+		}
 	}
-	if pingLog != "" {
-		result.Evidence = append(result.Evidence, model.Evidence{Label: "gateway_ping", Path: pingLog})
+	result.Metrics["icmp_loss"] = icmpLoss
+	result.Metrics["method"] = method
+	result.Metrics["sent"] = fmt.Sprintf("%d", stats.Sent)
+	result.Metrics["received"] = fmt.Sprintf("%d", stats.Received)
+	result.Metrics["loss_pct"] = fmt.Sprintf("%.1f", stats.LossPct)
+	result.Metrics["max_loss_burst"] = fmt.Sprintf("%d", stats.LossBurst)
+	result.Metrics["max_loss_burst_ms"] = fmt.Sprintf("%d", stats.LossBurstMs)
+	if seriesJSON, err := json.Marshal(samples); err == nil {
+		result.Metrics["gateway_series"] = string(seriesJSON)
 	}
-	if err != nil {
-		result.Status = StatusWarn
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "Gateway ping failed",
-			Detail:   err.Error(),
-		})
+	if stats.Received == 0 {
+		l.checkLinkLayer(&result, gateway, stats.Sent)
 		result.EndedAt = time.Now()
 		return result
 	}
-
-	stats := ParsePing(output)
-	result.Metrics["gateway"] = gateway
-	result.Metrics["loss_pct"] = fmt.Sprintf("%d", stats.LossPct)
-	result.Metrics["min_ms"] = fmt.Sprintf("%d", stats.MinMs)
-	result.Metrics["avg_ms"] = fmt.Sprintf("%d", stats.AvgMs)
-	result.Metrics["max_ms"] = fmt.Sprintf("%d", stats.MaxMs)
+	for key, value := range map[string]float64{
+		"min_ms":    stats.MinMs,
+		"avg_ms":    stats.AvgMs,
+		"max_ms":    stats.MaxMs,
+		"p50_ms":    stats.P50Ms,
+		"p95_ms":    stats.P95Ms,
+		"p99_ms":    stats.P99Ms,
+		"stddev_ms": stats.StddevMs,
+		"jitter_ms": stats.JitterMs,
+	} {
+		result.Metrics[key] = fmt.Sprintf("%.2f", value)
+	}
 
 	result.Status = StatusOK
-	if stats.LossPct > 0 || stats.AvgMs > 20 {
-		result.Status = StatusWarn
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "Gateway latency or loss",
-			Detail:   "The local gateway shows packet loss or elevated latency. Wi-Fi/LAN may be unstable.",
-		})
+	severity, reasons := l.bottleneck(stats)
+	result.Metrics["likely_bottleneck"] = fmt.Sprintf("%t", severity != "")
+	if severity != "" {
+		result.Status = severity
+		result.Findings = append(result.Findings, newFinding(severity, "lan.bottleneck", gateway, strings.Join(reasons, "; ")))
+	}
+	if stats.LossBurst >= gatewayBurstNotice {
+		result.Findings = append(result.Findings, newFinding("INFO", "lan.loss_burst", stats.LossBurst, float64(stats.LossBurstMs)/1000))
 	}
 
 	result.EndedAt = time.Now()
 	return result
 }
+
+// icmpLossVerdict compares the ping series with the fallback series taken
+// after it: "none" without ping loss, "blocked" or "rate_limited" when the
+// fallback lost at most half as much, otherwise "real".
+func icmpLossVerdict(icmp, fallback gatewayStats) string {
+	lost := icmp.Sent - icmp.Received
+	switch {
+	case lost == 0:
+		return "none"
+	case fallback.Received == 0 || fallback.LossPct*2 > icmp.LossPct:
+		return "real"
+	case icmp.Received == 0:
+		return "blocked"
+	case lost < gatewayRateLimitMinLost || icmp.LossPct < gatewayRateLimitMinLossPct:
+		return "real"
+	}
+	return "rate_limited"
+}
+
+// checkLinkLayer handles a gateway that answered no probe at all. The
+// neighbour table still shows whether it is present on the link: if so its
+// latency is merely unmeasurable, otherwise it is unreachable.
+func (l *LAN) checkLinkLayer(result *model.TestResult, gateway string, sent int) {
+	neighbors, logPath, err := readNeighbors(l.outDir)
+	if logPath != "" {
+		result.Evidence = append(result.Evidence, model.Evidence{Label: "neighbors", Path: logPath})
+	}
+	if entry, ok := findNeighbor(neighbors, gateway); err == nil && ok && entry.resolved() {
+		result.Metrics["method"] = "neighbor"
+		result.Metrics["gateway_mac"] = entry.MAC
+		result.Status = StatusOK
+		result.Findings = append(result.Findings, newFinding("INFO", "lan.gateway_link_only", gateway, entry.MAC, entry.State))
+		return
+	}
+	result.Status = StatusWarn
+	result.Findings = append(result.Findings, newFinding("WARN", "lan.gateway_unreachable", gateway, sent))
+}
+
+// probeLabel names a probe method in findings, e.g. "TCP :443".
+func probeLabel(method string, port int) string {
+	label := i18n.Label("lan.method", method)
+	if port > 0 {
+		label += fmt.Sprintf(" :%d", port)
+	}
+	return label
+}
+
+// bottleneck compares the gateway statistics with the configured thresholds.
+// It returns the worst severity reached (empty when none is) and one reason
+// per exceeded threshold.
+func (l *LAN) bottleneck(stats gatewayStats) (string, []string) {
+	thresholds := l.cfg.Thresholds
+	checks := []struct {
+		key        string
+		value      float64
+		warn, fail int
+	}{
+		{"lan.reason.loss", stats.LossPct, thresholds.PacketLossWarnPct, thresholds.PacketLossFailPct},
+		{"lan.reason.p95", stats.P95Ms, thresholds.GatewayP95WarnMs, thresholds.GatewayP95FailMs},
+		{"lan.reason.jitter", stats.JitterMs, thresholds.GatewayJitterWarnMs, thresholds.GatewayJitterFailMs},
+	}
+	severity := ""
+	var reasons []string
+	for _, check := range checks {
+		switch {
+		case check.fail > 0 && check.value >= float64(check.fail):
+			severity = StatusFail
+			reasons = append(reasons, i18n.T(check.key, check.value, check.fail))
+		case check.warn > 0 && check.value >= float64(check.warn):
+			if severity == "" {
+				severity = StatusWarn
+			}
+			reasons = append(reasons, i18n.T(check.key, check.value, check.warn))
+		}
+	}
+	return severity, reasons
+}
+
+type gatewaySample struct {
+	OffsetMs int     `json:"t"`
+	RTTMs    float64 `json:"rtt_ms"`
+	Loss     bool    `json:"loss"`
+}
+
+// sampleGateway sends count probes to gateway, one every interval, until done or ctx is
+// cancelled. A lost probe waits for its timeout, so under loss the probes
+// are further apart than interval; OffsetMs records when each was sent.
+func sampleGateway(ctx context.Context, gateway string, probe gatewayProbe, count int, interval time.Duration) []gatewaySample {
+	samples := make([]gatewaySample, 0, count)
+	start := time.Now()
+	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		iterStart := time.Now()
+		rttMs, lost := probe(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		samples = append(samples, gatewaySample{
+			OffsetMs: int(iterStart.Sub(start).Milliseconds()),
+			RTTMs:    rttMs,
+			Loss:     lost,
+		})
+		reportProgress(ctx, model.Progress{
+			Fraction: float64(i+1) / float64(count),
+			Target:   gateway,
+			RTTMs:    rttMs,
+			Lost:     lost,
+		})
+		if sleep := interval - time.Since(iterStart); sleep > 0 {
+			timer := time.NewTimer(sleep)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return samples
+			case <-timer.C:
+			}
+		}
+	}
+	return samples
+}
+
+type gatewayStats struct {
+	Sent     int
+	Received int
+	LossPct  float64
+	MinMs    float64
+	AvgMs    float64
+	MaxMs    float64
+	P50Ms    float64
+	P95Ms    float64
+	P99Ms    float64
+	StddevMs float64
+	// JitterMs is the RFC 3550 interarrival jitter estimate, applied to
+	// consecutive round-trip times: J += (|D| - J) / 16.
+	JitterMs float64
+	// LossBurst is the longest run of consecutive lost requests, and
+	// LossBurstMs how long it lasted: from its first probe to the next probe
+	// sent after it, which includes the timeouts of the lost ones.
+	LossBurst   int
+	LossBurstMs int
+}
+
+func summarizeGateway(samples []gatewaySample) gatewayStats {
+	stats := gatewayStats{Sent: len(samples)}
+	values := make([]float64, 0, len(samples))
+	burst, burstStartMs := 0, 0
+	closeBurst := func(endMs int) {
+		if burst > stats.LossBurst || (burst == stats.LossBurst && endMs-burstStartMs > stats.LossBurstMs) {
+			stats.LossBurst, stats.LossBurstMs = burst, endMs-burstStartMs
+		}
+		burst = 0
+	}
+	previous := -1.0
+	for _, sample := range samples {
+		if sample.Loss {
+			if burst == 0 {
+				burstStartMs = sample.OffsetMs
+			}
+			burst++
+			continue
+		}
+		if burst > 0 {
+			closeBurst(sample.OffsetMs)
+		}
+		if previous >= 0 {
+			stats.JitterMs += (math.Abs(sample.RTTMs-previous) - stats.JitterMs) / 16
+		}
+		previous = sample.RTTMs
+		values = append(values, sample.RTTMs)
+	}
+	if burst > 0 {
+		// No probe followed the last burst: end it one average spacing
+		// after its last probe.
+		last := samples[len(samples)-1].OffsetMs
+		spacing := 0
+		if len(samples) > 1 {
+			spacing = (last - samples[0].OffsetMs) / (len(samples) - 1)
+		}
+		closeBurst(last + spacing)
+	}
+	stats.Received = len(values)
+	if stats.Sent > 0 {
+		stats.LossPct = float64(stats.Sent-stats.Received) * 100 / float64(stats.Sent)
+	}
+	if len(values) == 0 {
+		return stats
+	}
+
+	stats.MinMs, stats.MaxMs = values[0], values[0]
+	sum := 0.0
+	for _, value := range values {
+		stats.MinMs = math.Min(stats.MinMs, value)
+		stats.MaxMs = math.Max(stats.MaxMs, value)
+		sum += value
+	}
+	stats.AvgMs = sum / float64(len(values))
+	variance := 0.0
+	for _, value := range values {
+		variance += (value - stats.AvgMs) * (value - stats.AvgMs)
+	}
+	stats.StddevMs = math.Sqrt(variance / float64(len(values)))
+	stats.P50Ms = percentile(values, 50)
+	stats.P95Ms = percentile(values, 95)
+	stats.P99Ms = percentile(values, 99)
+	return stats
+}
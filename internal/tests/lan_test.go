@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"math"
+	"testing"
+)
+
+func TestICMPLossVerdict(t *testing.T) {
+	stats := func(sent, received int) gatewayStats {
+		s := gatewayStats{Sent: sent, Received: received}
+		if sent > 0 {
+			s.LossPct = float64(sent-received) / float64(sent) * 100
+		}
+		return s
+	}
+	tests := []struct {
+		name     string
+		icmp     gatewayStats
+		fallback gatewayStats
+		want     string
+	}{
+		{name: "no loss", icmp: stats(100, 100), fallback: stats(50, 50), want: "none"},
+		{name: "one lost ping is not rate limiting", icmp: stats(100, 99), fallback: stats(50, 50), want: "real"},
+		{name: "one lost of ten is not rate limiting", icmp: stats(10, 9), fallback: stats(10, 10), want: "real"},
+		{name: "two lost under the share", icmp: stats(100, 98), fallback: stats(50, 50), want: "real"},
+		{name: "just under the share", icmp: stats(100, 91), fallback: stats(50, 50), want: "real"},
+		{name: "at the share", icmp: stats(100, 90), fallback: stats(50, 50), want: "rate_limited"},
+		{name: "two lost of twenty", icmp: stats(20, 18), fallback: stats(20, 20), want: "rate_limited"},
+		{name: "fallback loses half as much", icmp: stats(100, 80), fallback: stats(50, 45), want: "rate_limited"},
+		{name: "fallback loses more than half as much", icmp: stats(100, 80), fallback: stats(50, 44), want: "real"},
+		{name: "no ping answered", icmp: stats(100, 0), fallback: stats(50, 50), want: "blocked"},
+		{name: "nothing answered", icmp: stats(100, 0), fallback: stats(50, 0), want: "real"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := icmpLossVerdict(tt.icmp, tt.fallback); got != tt.want {
+				t.Errorf("icmpLossVerdict(%.0f%% ping loss, %.0f%% fallback loss) = %q, want %q", tt.icmp.LossPct, tt.fallback.LossPct, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarizeGateway(t *testing.T) {
+	ok := func(offsetMs int, rttMs float64) gatewaySample {
+		return gatewaySample{OffsetMs: offsetMs, RTTMs: rttMs}
+	}
+	lost := func(offsetMs int) gatewaySample {
+		return gatewaySample{OffsetMs: offsetMs, RTTMs: -1, Loss: true}
+	}
+	tests := []struct {
+		name    string
+		samples []gatewaySample
+		want    gatewayStats
+	}{
+		{
+			name: "no samples",
+			want: gatewayStats{},
+		},
+		{
+			name:    "all lost",
+			samples: []gatewaySample{lost(0), lost(100), lost(200), lost(300)},
+			want:    gatewayStats{Sent: 4, LossPct: 100, LossBurst: 4, LossBurstMs: 400},
+		},
+		{
+			// Deviations from the mean of 3 are -2, 0, -1 and 3, so the
+			// variance is 14/4. Jitter takes |D| = 2, 1, 4 in turn.
+			name:    "no loss",
+			samples: []gatewaySample{ok(0, 1), ok(100, 3), ok(200, 2), ok(300, 6)},
+			want: gatewayStats{
+				Sent: 4, Received: 4, MinMs: 1, AvgMs: 3, MaxMs: 6,
+				P50Ms: 2.5, P95Ms: 5.55, P99Ms: 5.91, StddevMs: math.Sqrt(3.5),
+				JitterMs: 0.41845703125,
+			},
+		},
+		{
+			// The two lost probes each waited for their timeout, so the
+			// outage runs from 100 ms to the next probe at 2500 ms.
+			name:    "burst in the middle",
+			samples: []gatewaySample{ok(0, 1), lost(100), lost(1300), ok(2500, 2), ok(2600, 1)},
+			want: gatewayStats{
+				Sent: 5, Received: 3, LossPct: 40, MinMs: 1, AvgMs: 4.0 / 3, MaxMs: 2,
+				P50Ms: 1, P95Ms: 1.9, P99Ms: 1.98, StddevMs: math.Sqrt(2.0 / 9),
+				JitterMs: 0.12109375, LossBurst: 2, LossBurstMs: 2400,
+			},
+		},
+		{
+			// No probe follows: the burst ends one average spacing (100 ms)
+			// after its last probe.
+			name:    "single burst at the end",
+			samples: []gatewaySample{ok(0, 1), ok(100, 1), lost(200), lost(300)},
+			want: gatewayStats{
+				Sent: 4, Received: 2, LossPct: 50, MinMs: 1, AvgMs: 1, MaxMs: 1,
+				P50Ms: 1, P95Ms: 1, P99Ms: 1, LossBurst: 2, LossBurstMs: 200,
+			},
+		},
+		{
+			name:    "longest burst by count wins over a longer single loss",
+			samples: []gatewaySample{lost(0), lost(100), ok(200, 1), lost(300), ok(3000, 1)},
+			want: gatewayStats{
+				Sent: 5, Received: 2, LossPct: 60, MinMs: 1, AvgMs: 1, MaxMs: 1,
+				P50Ms: 1, P95Ms: 1, P99Ms: 1, LossBurst: 2, LossBurstMs: 200,
+			},
+		},
+		{
+			name:    "equal bursts keep the longer outage",
+			samples: []gatewaySample{ok(0, 1), lost(100), ok(200, 1), lost(300), ok(1500, 1)},
+			want: gatewayStats{
+				Sent: 5, Received: 3, LossPct: 40, MinMs: 1, AvgMs: 1, MaxMs: 1,
+				P50Ms: 1, P95Ms: 1, P99Ms: 1, LossBurst: 1, LossBurstMs: 1200,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarizeGateway(tt.samples)
+			if got.Sent != tt.want.Sent || got.Received != tt.want.Received ||
+				got.LossBurst != tt.want.LossBurst || got.LossBurstMs != tt.want.LossBurstMs {
+				t.Errorf("sent %d, received %d, burst %d over %d ms; want %d, %d, %d over %d ms",
+					got.Sent, got.Received, got.LossBurst, got.LossBurstMs,
+					tt.want.Sent, tt.want.Received, tt.want.LossBurst, tt.want.LossBurstMs)
+			}
+			for _, field := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"loss_pct", got.LossPct, tt.want.LossPct},
+				{"min", got.MinMs, tt.want.MinMs},
+				{"avg", got.AvgMs, tt.want.AvgMs},
+				{"max", got.MaxMs, tt.want.MaxMs},
+				{"p50", got.P50Ms, tt.want.P50Ms},
+				{"p95", got.P95Ms, tt.want.P95Ms},
+				{"p99", got.P99Ms, tt.want.P99Ms},
+				{"stddev", got.StddevMs, tt.want.StddevMs},
+				{"jitter", got.JitterMs, tt.want.JitterMs},
+			} {
+				if math.Abs(field.got-field.want) > 1e-9 {
+					t.Errorf("%s = %v, want %v", field.name, field.got, field.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	hundred := make([]float64, 100)
+	for i := range hundred {
+		hundred[i] = float64(i + 1)
+	}
+	tests := []struct {
+		name   string
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{name: "empty", values: nil, p: 50, want: 0},
+		{name: "single value", values: []float64{7}, p: 99, want: 7},
+		{name: "two values interpolate", values: []float64{10, 20}, p: 50, want: 15},
+		{name: "odd count median", values: []float64{5, 1, 4, 2, 3}, p: 50, want: 3},
+		{name: "p0 is the minimum", values: []float64{5, 1, 4, 2, 3}, p: 0, want: 1},
+		{name: "p100 is the maximum", values: []float64{5, 1, 4, 2, 3}, p: 100, want: 5},
+		{name: "p95 of five", values: []float64{1, 2, 3, 4, 5}, p: 95, want: 4.8},
+		{name: "p99 of five", values: []float64{1, 2, 3, 4, 5}, p: 99, want: 4.96},
+		{name: "p50 of 1..100", values: hundred, p: 50, want: 50.5},
+		{name: "p95 of 1..100", values: hundred, p: 95, want: 95.05},
+		{name: "p99 of 1..100", values: hundred, p: 99, want: 99.01},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.values, tt.p); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("percentile(p%.0f) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
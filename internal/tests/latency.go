@@ -37,13 +37,10 @@ func (l *Latency) Run(ctx context.Context) model.TestResult {
 	result := baseResult(l.Name())
 	result.StartedAt = time.Now()
 
-	if len(l.cfg.Targets.PingTargets) == 0 {
+	targets := l.cfg.Targets.Resolve(l.cfg.Targets.PingTargets)
+	if len(targets) == 0 {
 		result.Status = StatusSkipped
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "INFO",
-			Title:    "No ping targets",
-			Detail:   "No targets configured for latency checks.",
-		})
+		result.Findings = append(result.Findings, newFinding("INFO", "latency.no_targets"))
 		result.EndedAt = time.Now()
 		return result
 	}
@@ -56,19 +53,19 @@ func (l *Latency) Run(ctx context.Context) model.TestResult {
 		err     error
 	}
 
-	results := make([]targetResult, 0, len(l.cfg.Targets.PingTargets))
+	results := make([]targetResult, 0, len(targets))
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	for _, target := range l.cfg.Targets.PingTargets {
+	for _, target := range targets {
 		target := target
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			samples, summary, err := runLatencySeries(ctx, target)
+			samples, summary, err := runLatencySeries(ctx, target.Address, target.Stack)
 			mu.Lock()
 			results = append(results, targetResult{
-				target:  target,
+				target:  target.Address,
 				samples: samples,
 				summary: summary,
 				err:     err,
@@ -82,11 +79,7 @@ func (l *Latency) Run(ctx context.Context) model.TestResult {
 	for _, entry := range results {
 		if entry.err != nil {
 			result.Status = StatusWarn
-			result.Findings = append(result.Findings, model.Finding{
-				Severity: "WARN",
-				Title:    "Latency sampling failed",
-				Detail:   fmt.Sprintf("Latency sampling for %s failed: %s", entry.target, entry.err.Error()),
-			})
+			result.Findings = append(result.Findings, newFinding("WARN", "latency.sampling_failed", entry.target, entry.err.Error()))
 			continue
 		}
 
@@ -125,7 +118,7 @@ type latencySummary struct {
 	LossPct int
 }
 
-func runLatencySeries(ctx context.Context, target string) ([]latencySample, latencySummary, error) {
+func runLatencySeries(ctx context.Context, target, stack string) ([]latencySample, latencySummary, error) {
 	sampleCount := int(latencyProbeDuration / latencyProbeInterval)
 	if sampleCount < 1 {
 		sampleCount = 1
@@ -149,7 +142,7 @@ func runLatencySeries(ctx context.Context, target string) ([]latencySample, late
 		}
 
 		iterStart := time.Now()
-		latencyMs, lost := pingSample(ctx, target)
+		latencyMs, lost := pingSample(ctx, target, stack)
 		offsetMs := int(time.Since(start).Milliseconds())
 		samples = append(samples, latencySample{
 			OffsetMs:  offsetMs,
@@ -157,6 +150,13 @@ func runLatencySeries(ctx context.Context, target string) ([]latencySample, late
 			Loss:      lost,
 		})
 
+		reportProgress(ctx, model.Progress{
+			Fraction: float64(i+1) / float64(sampleCount),
+			Target:   target,
+			RTTMs:    float64(latencyMs),
+			Lost:     lost || latencyMs < 0,
+		})
+
 		if lost || latencyMs < 0 {
 			lossCount++
 		} else {
@@ -196,22 +196,31 @@ func summarizeLatency(successCount, sumLatency, minLatency, maxLatency, lossCoun
 	}
 }
 
-func pingSample(ctx context.Context, target string) (int, bool) {
+// pingSample sends one echo request. stack, when ipv4 or ipv6, forces the
+// address family for hostnames.
+func pingSample(ctx context.Context, target, stack string) (int, bool) {
+	rttMs, lost := pingRTT(ctx, target, stack)
+	if lost {
+		return -1, true
+	}
+	return int(rttMs), false
+}
+
+// pingRTT is pingSample with the sub-millisecond precision ping prints,
+// which matters for targets a hop away such as the gateway.
+func pingRTT(ctx context.Context, target, stack string) (float64, bool) {
 	timeout := 1200 * time.Millisecond
 	pingCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	args := pingArgs(target)
-	output, err := sys.RunCommandNoLog(pingCtx, "ping", args...)
-	if latencyMs, ok := parsePingLatency(output); ok {
-		return latencyMs, false
+	args := append(stackArgs(stack), pingArgs(target)...)
+	output, _ := sys.RunCommandNoLog(pingCtx, "ping", args...)
+	if rttMs, ok := parsePingLatency(output); ok {
+		return rttMs, false
 	}
 	stats := ParsePing(output)
 	if stats.Sent > 0 && stats.LossPct < 100 {
-		return stats.AvgMs, false
-	}
-	if err != nil {
-		return -1, true
+		return float64(stats.AvgMs), false
 	}
 	return -1, true
 }
@@ -227,7 +236,22 @@ func pingArgs(target string) []string {
 	}
 }
 
-func parsePingLatency(output string) (int, bool) {
+// stackArgs returns the -4/-6 flag understood by ping, traceroute and tracert.
+// macOS ping has no -6, so the family is left to the resolver there.
+func stackArgs(stack string) []string {
+	if runtime.GOOS == "darwin" {
+		return nil
+	}
+	switch stack {
+	case "ipv4":
+		return []string{"-4"}
+	case "ipv6":
+		return []string{"-6"}
+	}
+	return nil
+}
+
+func parsePingLatency(output string) (float64, bool) {
 	for _, matcher := range pingLatencyMatchers {
 		if match := matcher.FindStringSubmatch(output); len(match) == 3 {
 			return parseLatencyValue(match[2]), true
@@ -236,12 +260,12 @@ func parsePingLatency(output string) (int, bool) {
 	return 0, false
 }
 
-func parseLatencyValue(value string) int {
+func parseLatencyValue(value string) float64 {
 	value = strings.TrimSpace(value)
 	value = strings.ReplaceAll(value, ",", ".")
 	parsed, err := strconv.ParseFloat(value, 64)
 	if err != nil {
 		return 0
 	}
-	return int(parsed)
+	return parsed
 }
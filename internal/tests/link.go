@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"conncheck/internal/config"
+	"conncheck/internal/i18n"
+	"conncheck/internal/model"
+	"conncheck/internal/sys"
+)
+
+// linkCapRatio is the share of the negotiated link speed above which a
+// speedtest result is considered capped by the link: protocol overhead keeps
+// even a saturated link some way below its nominal rate.
+const linkCapRatio = 0.85
+
+var (
+	linkSpeedRe      = regexp.MustCompile(`(?i)^([\d.,]+)\s*([kmg])b(?:ps|/s)$`)
+	ethtoolSpeedRe   = regexp.MustCompile(`(?m)^\s*Speed:\s*(\d+)\s*Mb/s`)
+	ethtoolDuplexRe  = regexp.MustCompile(`(?m)^\s*Duplex:\s*(\w+)`)
+	ethtoolAutonegRe = regexp.MustCompile(`(?m)^\s*Auto-negotiation:\s*(\w+)`)
+)
+
+// readLink describes the interface carrying the default route: negotiated
+// speed, duplex and autonegotiation. It returns nil when the interface or
+// its link state cannot be determined.
+func readLink(outDir string) *model.Link {
+	_, iface := localAddressFor("1.1.1.1")
+	if iface == "" {
+		return nil
+	}
+	switch runtime.GOOS {
+	case "linux":
+		return readLinuxLink(outDir, iface)
+	case "windows":
+		return readWindowsLink(outDir, iface)
+	}
+	return nil
+}
+
+// readLinuxLink reads /sys/class/net/<iface>, then ethtool where installed,
+// which also knows the autonegotiation state.
+func readLinuxLink(outDir, iface string) *model.Link {
+	dir := filepath.Join("/sys/class/net", iface)
+	link := &model.Link{Interface: iface, Media: "ethernet", Source: "sysfs"}
+	if _, err := os.Stat(filepath.Join(dir, "wireless")); err == nil {
+		link.Media = "wireless"
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "speed")); err == nil {
+		// Reading speed fails or gives -1 while the link is down and on
+		// virtual or wireless interfaces.
+		if speed, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && speed > 0 {
+			link.SpeedMbps = float64(speed)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "duplex")); err == nil {
+		link.Duplex = normalizeDuplex(strings.TrimSpace(string(data)))
+	}
+	if _, err := exec.LookPath("ethtool"); err == nil {
+		if output, _, err := sys.RunCommand(outDir, "ethtool", iface); err == nil {
+			parseEthtool(output, link)
+			link.Source = "ethtool"
+		}
+	}
+	return link
+}
+
+// parseEthtool fills link from `ethtool <iface>`, keeping what is already
+// known when a field is missing or unknown.
+func parseEthtool(output string, link *model.Link) {
+	if m := ethtoolSpeedRe.FindStringSubmatch(output); len(m) == 2 {
+		if speed, err := strconv.Atoi(m[1]); err == nil && speed > 0 {
+			link.SpeedMbps = float64(speed)
+		}
+	}
+	if m := ethtoolDuplexRe.FindStringSubmatch(output); len(m) == 2 {
+		if duplex := normalizeDuplex(m[1]); duplex != "" {
+			link.Duplex = duplex
+		}
+	}
+	if m := ethtoolAutonegRe.FindStringSubmatch(output); len(m) == 2 {
+		switch strings.ToLower(m[1]) {
+		case "on":
+			link.Autoneg = "on"
+		case "off":
+			link.Autoneg = "off"
+		}
+	}
+}
+
+func normalizeDuplex(duplex string) string {
+	switch strings.ToLower(duplex) {
+	case "full", "true":
+		return "full"
+	case "half", "false":
+		return "half"
+	}
+	return ""
+}
+
+// readWindowsLink asks Get-NetAdapter for the speed and duplex, and the
+// *SpeedDuplex advanced property for autonegotiation. Both are listed with
+// Format-List so the property names stay in English whatever the locale.
+func readWindowsLink(outDir, iface string) *model.Link {
+	output, _, err := sys.RunCommand(outDir, "powershell", "-NoProfile", "-Command",
+		"Get-NetAdapter | Format-List Name,LinkSpeed,FullDuplex,MediaType,PhysicalMediaType")
+	if err != nil {
+		return nil
+	}
+	link, ok := parseNetAdapter(output)[iface]
+	if !ok {
+		return nil
+	}
+	output, _, err = sys.RunCommand(outDir, "powershell", "-NoProfile", "-Command",
+		"Get-NetAdapterAdvancedProperty -RegistryKeyword '*SpeedDuplex' -ErrorAction SilentlyContinue | Format-List Name,RegistryValue")
+	if err == nil {
+		if autoneg, ok := parseSpeedDuplex(output)[iface]; ok {
+			link.Autoneg = autoneg
+		}
+	}
+	return link
+}
+
+// parseFormatList splits PowerShell Format-List output into one map of
+// property to value per record.
+func parseFormatList(output string) []map[string]string {
+	var records []map[string]string
+	var current map[string]string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			current = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		if current == nil {
+			current = map[string]string{}
+			records = append(records, current)
+		}
+		current[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), "{}")
+	}
+	return records
+}
+
+// parseNetAdapter parses Get-NetAdapter records, keyed by adapter name.
+func parseNetAdapter(output string) map[string]*model.Link {
+	links := map[string]*model.Link{}
+	for _, record := range parseFormatList(output) {
+		name := record["Name"]
+		if name == "" {
+			continue
+		}
+		link := &model.Link{Interface: name, Media: "ethernet", Source: "netadapter"}
+		media := strings.ToLower(record["MediaType"] + " " + record["PhysicalMediaType"])
+		if strings.Contains(media, "802.11") || strings.Contains(media, "wireless") {
+			link.Media = "wireless"
+		}
+		link.SpeedMbps = parseLinkSpeed(record["LinkSpeed"])
+		link.Duplex = normalizeDuplex(record["FullDuplex"])
+		links[name] = link
+	}
+	return links
+}
+
+// parseSpeedDuplex reads the *SpeedDuplex registry value of each adapter:
+// 0 is autonegotiation, any other value a forced speed and duplex.
+func parseSpeedDuplex(output string) map[string]string {
+	autoneg := map[string]string{}
+	for _, record := range parseFormatList(output) {
+		value, err := strconv.Atoi(record["RegistryValue"])
+		if record["Name"] == "" || err != nil {
+			continue
+		}
+		autoneg[record["Name"]] = "off"
+		if value == 0 {
+			autoneg[record["Name"]] = "on"
+		}
+	}
+	return autoneg
+}
+
+// parseLinkSpeed converts "1 Gbps", "100 Mbps" or "866.7 Mbps" to Mbps, or
+// 0 when it is not a speed.
+func parseLinkSpeed(value string) float64 {
+	m := linkSpeedRe.FindStringSubmatch(strings.TrimSpace(value))
+	if len(m) != 3 {
+		return 0
+	}
+	speed, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", "."), 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToLower(m[2]) {
+	case "k":
+		return speed / 1000
+	case "g":
+		return speed * 1000
+	}
+	return speed
+}
+
+// checkLink compares the local link with the plan. Half duplex, or a link
+// slower than the plan, means the local link caps the throughput rather
+// than the ISP.
+func checkLink(result *model.TestResult, link *model.Link, plan config.Plan) {
+	if link == nil {
+		return
+	}
+	result.Metrics["link_interface"] = link.Interface
+	if link.SpeedMbps > 0 {
+		result.Metrics["link_speed_mbps"] = fmt.Sprintf("%.0f", link.SpeedMbps)
+	}
+	if link.Duplex != "" {
+		result.Metrics["link_duplex"] = link.Duplex
+	}
+	if link.Duplex == "half" {
+		result.Status = worseStatus(result.Status, StatusWarn)
+		result.Findings = append(result.Findings, newFinding("WARN", "link.half_duplex", link.Interface, link.SpeedMbps))
+	}
+	if link.Autoneg == "off" {
+		result.Findings = append(result.Findings, newFinding("INFO", "link.autoneg_off", link.Interface))
+	}
+	// A wireless rate is the PHY rate of the last frame, not a ceiling the
+	// plan or the throughput can be compared with.
+	if link.SpeedMbps <= 0 || link.Media == "wireless" {
+		return
+	}
+	for _, entry := range []struct {
+		direction string
+		planMbps  float64
+	}{{"download", plan.DownloadMbps}, {"upload", plan.UploadMbps}} {
+		if entry.planMbps > link.SpeedMbps {
+			result.Status = worseStatus(result.Status, StatusWarn)
+			result.Findings = append(result.Findings, newFinding("WARN", "link.below_plan", link.Interface, link.SpeedMbps, i18n.Label("speedtest.direction", entry.direction), entry.planMbps))
+			break
+		}
+	}
+}
+
+// checkSpeedtestCap flags a speedtest whose best average, in bits per
+// second, reached the link speed.
+func checkSpeedtestCap(result *model.TestResult, link *model.Link, bestBps float64) {
+	if link == nil || link.SpeedMbps <= 0 || link.Media == "wireless" || bestBps <= 0 {
+		return
+	}
+	best := bestBps / 1_000_000
+	result.Metrics["link_speed_mbps"] = fmt.Sprintf("%.0f", link.SpeedMbps)
+	result.Metrics["link_utilization_pct"] = fmt.Sprintf("%.1f", best/link.SpeedMbps*100)
+	capped := best >= link.SpeedMbps*linkCapRatio
+	result.Metrics["link_capped"] = fmt.Sprintf("%t", capped)
+	if capped {
+		result.Status = worseStatus(result.Status, StatusWarn)
+		result.Findings = append(result.Findings, newFinding("WARN", "link.speedtest_capped", best, link.Interface, link.SpeedMbps, best/link.SpeedMbps*100))
+	}
+}
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"conncheck/internal/model"
+)
+
+func TestParseEthtool(t *testing.T) {
+	const gigabit = "Settings for eth0:\n" +
+		"\tSupported ports: [ TP ]\n" +
+		"\tSupported link modes:   10baseT/Half 10baseT/Full\n" +
+		"\t                        100baseT/Half 100baseT/Full\n" +
+		"\t                        1000baseT/Full\n" +
+		"\tSupported pause frame use: No\n" +
+		"\tSupports auto-negotiation: Yes\n" +
+		"\tAdvertised link modes:  10baseT/Half 10baseT/Full\n" +
+		"\t                        100baseT/Half 100baseT/Full\n" +
+		"\t                        1000baseT/Full\n" +
+		"\tAdvertised auto-negotiation: Yes\n" +
+		"\tSpeed: 1000Mb/s\n" +
+		"\tDuplex: Full\n" +
+		"\tAuto-negotiation: on\n" +
+		"\tPort: Twisted Pair\n" +
+		"\tPHYAD: 1\n" +
+		"\tTransceiver: internal\n" +
+		"\tMDI-X: on (auto)\n" +
+		"\tSupports Wake-on: pumbg\n" +
+		"\tWake-on: g\n" +
+		"\tLink detected: yes\n"
+	tests := []struct {
+		name   string
+		output string
+		link   model.Link
+		want   model.Link
+	}{
+		{
+			name:   "gigabit full duplex",
+			output: gigabit,
+			want:   model.Link{SpeedMbps: 1000, Duplex: "full", Autoneg: "on"},
+		},
+		{
+			name: "forced 100 half duplex",
+			output: "Settings for eth0:\n" +
+				"\tSupports auto-negotiation: Yes\n" +
+				"\tAdvertised auto-negotiation: No\n" +
+				"\tSpeed: 100Mb/s\n" +
+				"\tDuplex: Half\n" +
+				"\tAuto-negotiation: off\n" +
+				"\tLink detected: yes\n",
+			want: model.Link{SpeedMbps: 100, Duplex: "half", Autoneg: "off"},
+		},
+		{
+			name: "link down keeps what sysfs found",
+			output: "Settings for eth0:\n" +
+				"\tSupports auto-negotiation: Yes\n" +
+				"\tSpeed: Unknown!\n" +
+				"\tDuplex: Unknown! (255)\n" +
+				"\tAuto-negotiation: on\n" +
+				"\tLink detected: no\n",
+			link: model.Link{SpeedMbps: 10, Duplex: "half"},
+			want: model.Link{SpeedMbps: 10, Duplex: "half", Autoneg: "on"},
+		},
+		{
+			name:   "no settings",
+			output: "Settings for eth0:\nCannot get device settings: Operation not permitted\n\tLink detected: yes\n",
+			want:   model.Link{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := tt.link
+			parseEthtool(tt.output, &link)
+			if link != tt.want {
+				t.Errorf("parseEthtool = %+v, want %+v", link, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormatList(t *testing.T) {
+	const output = "\r\n\r\n" +
+		"Name          : Ethernet\r\n" +
+		"Description   : Intel(R) Ethernet Connection (7) I219-V\r\n" +
+		"MacAddress    : 3C-A6-2F-AA-BB-CC\r\n" +
+		"RegistryValue : {0}\r\n" +
+		"\r\n" +
+		"Name          : Wi-Fi\r\n" +
+		"Status        : Up\r\n" +
+		"not a property\r\n\r\n"
+	want := []map[string]string{
+		{"Name": "Ethernet", "Description": "Intel(R) Ethernet Connection (7) I219-V", "MacAddress": "3C-A6-2F-AA-BB-CC", "RegistryValue": "0"},
+		{"Name": "Wi-Fi", "Status": "Up"},
+	}
+	if got := parseFormatList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseFormatList =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestParseNetAdapter(t *testing.T) {
+	const output = "\r\n" +
+		"Name              : Ethernet\r\n" +
+		"LinkSpeed         : 1 Gbps\r\n" +
+		"FullDuplex        : True\r\n" +
+		"MediaType         : 802.3\r\n" +
+		"PhysicalMediaType : 802.3\r\n" +
+		"\r\n" +
+		"Name              : Ethernet 2\r\n" +
+		"LinkSpeed         : 100 Mbps\r\n" +
+		"FullDuplex        : False\r\n" +
+		"MediaType         : 802.3\r\n" +
+		"PhysicalMediaType : 802.3\r\n" +
+		"\r\n" +
+		"Name              : Wi-Fi\r\n" +
+		"LinkSpeed         : 866.7 Mbps\r\n" +
+		"FullDuplex        : True\r\n" +
+		"MediaType         : Native 802.11\r\n" +
+		"PhysicalMediaType : Native 802.11\r\n" +
+		"\r\n" +
+		"Name              : vEthernet (WSL)\r\n" +
+		"LinkSpeed         : 0 bps\r\n" +
+		"FullDuplex        : \r\n" +
+		"MediaType         : 802.3\r\n" +
+		"PhysicalMediaType : Unspecified\r\n\r\n"
+	want := map[string]*model.Link{
+		"Ethernet":        {Interface: "Ethernet", Media: "ethernet", SpeedMbps: 1000, Duplex: "full", Source: "netadapter"},
+		"Ethernet 2":      {Interface: "Ethernet 2", Media: "ethernet", SpeedMbps: 100, Duplex: "half", Source: "netadapter"},
+		"Wi-Fi":           {Interface: "Wi-Fi", Media: "wireless", SpeedMbps: 866.7, Duplex: "full", Source: "netadapter"},
+		"vEthernet (WSL)": {Interface: "vEthernet (WSL)", Media: "ethernet", Source: "netadapter"},
+	}
+	if got := parseNetAdapter(output); !reflect.DeepEqual(got, want) {
+		for name, link := range got {
+			t.Logf("%s: %+v", name, *link)
+		}
+		t.Errorf("parseNetAdapter did not match the expected adapters")
+	}
+}
+
+func TestParseSpeedDuplex(t *testing.T) {
+	const output = "\r\n" +
+		"Name          : Ethernet\r\n" +
+		"RegistryValue : {0}\r\n" +
+		"\r\n" +
+		"Name          : Ethernet 2\r\n" +
+		"RegistryValue : {4}\r\n" +
+		"\r\n" +
+		"Name          : Ethernet 3\r\n" +
+		"RegistryValue : \r\n\r\n"
+	want := map[string]string{"Ethernet": "on", "Ethernet 2": "off"}
+	if got := parseSpeedDuplex(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSpeedDuplex = %v, want %v", got, want)
+	}
+}
+
+func TestParseLinkSpeed(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{value: "1 Gbps", want: 1000},
+		{value: "100 Mbps", want: 100},
+		{value: "10 Mbps", want: 10},
+		{value: "866.7 Mbps", want: 866.7},
+		{value: "2,5 Gbps", want: 2500},
+		{value: "1Gb/s", want: 1000},
+		{value: "512 Kbps", want: 0.512},
+		{value: " 1 Gbps ", want: 1000},
+		{value: "0 bps", want: 0},
+		{value: "Unknown!", want: 0},
+		{value: "", want: 0},
+	}
+	for _, tt := range tests {
+		if got := parseLinkSpeed(tt.value); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("parseLinkSpeed(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
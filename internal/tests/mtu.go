@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"conncheck/internal/config"
+	"conncheck/internal/i18n"
 	"conncheck/internal/model"
 	"conncheck/internal/sys"
 )
@@ -35,9 +36,10 @@ func (m *MTU) Name() string {
 func (m *MTU) Run(ctx context.Context) model.TestResult {
 	result := baseResult(m.Name())
 	result.StartedAt = time.Now()
-	result.Metrics["targets"] = joinList(m.cfg.Targets.MTUTargets)
+	catalog := m.cfg.Targets.Resolve(m.cfg.Targets.MTUTargets)
+	result.Metrics["targets"] = joinList(config.Addresses(catalog))
 
-	baseline, baselineEvidence := collectBaseline(m.outDir, m.cfg.Targets.MTUTargets)
+	baseline, baselineEvidence := collectBaseline(m.outDir, config.Addresses(catalog))
 	result.Evidence = append(result.Evidence, baselineEvidence...)
 	if baseline.Interface != "" {
 		result.Metrics["local_interface"] = baseline.Interface
@@ -61,17 +63,13 @@ func (m *MTU) Run(ctx context.Context) model.TestResult {
 		result.Metrics["gateway"] = baseline.Gateway
 	}
 
-	targets := append([]string{}, m.cfg.Targets.MTUTargets...)
-	if baseline.Gateway != "" && !containsTarget(targets, baseline.Gateway) {
-		targets = append([]string{baseline.Gateway}, targets...)
+	targets := catalog
+	if baseline.Gateway != "" && !containsTarget(config.Addresses(targets), baseline.Gateway) {
+		targets = append([]config.Target{{Address: baseline.Gateway}}, targets...)
 	}
 	if len(targets) == 0 {
 		result.Status = StatusSkipped
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "INFO",
-			Title:    "No MTU targets",
-			Detail:   "No targets configured for PMTU checks.",
-		})
+		result.Findings = append(result.Findings, newFinding("INFO", "mtu.no_targets"))
 		result.EndedAt = time.Now()
 		return result
 	}
@@ -82,9 +80,10 @@ func (m *MTU) Run(ctx context.Context) model.TestResult {
 	var pmtuDetails []string
 	var targetsTested []string
 
-	for _, target := range targets {
+	for _, entry := range targets {
+		target := entry.Address
 		for _, stack := range []string{"ipv4", "ipv6"} {
-			if !targetSupportsStack(target, stack) {
+			if !targetSupportsStack(target, stack) || (entry.Stack != "" && entry.Stack != stack) {
 				continue
 			}
 			pmtuResult := runPMTUTest(ctx, m.outDir, target, stack)
@@ -107,11 +106,7 @@ func (m *MTU) Run(ctx context.Context) model.TestResult {
 			}
 			if pmtuResult.Err != nil {
 				result.Status = StatusWarn
-				result.Findings = append(result.Findings, model.Finding{
-					Severity: "WARN",
-					Title:    "PMTU check failed",
-					Detail:   fmt.Sprintf("PMTU check for %s (%s) failed: %s", target, stack, pmtuResult.Err.Error()),
-				})
+				result.Findings = append(result.Findings, newFinding("WARN", "mtu.check_failed", target, stack, pmtuResult.Err.Error()))
 			}
 			if pmtuResult.PMTU > 0 || pmtuResult.Err == nil {
 				targetsTested = append(targetsTested, fmt.Sprintf("%s/%s", target, stack))
@@ -121,11 +116,7 @@ func (m *MTU) Run(ctx context.Context) model.TestResult {
 
 	if len(pmtuValues) == 0 {
 		result.Status = StatusWarn
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "PMTU not detected",
-			Detail:   "No PMTU values could be measured from the configured targets.",
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "mtu.not_detected"))
 		result.EndedAt = time.Now()
 		return result
 	}
@@ -141,54 +132,39 @@ func (m *MTU) Run(ctx context.Context) model.TestResult {
 
 	if baseline.MTU > 0 && minPMTU > 0 && minPMTU < baseline.MTU {
 		result.Status = StatusWarn
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "PMTU lower than interface MTU",
-			Detail:   fmt.Sprintf("Detected PMTU %d while local MTU is %d. Possible clamping/PPPoE or tunnel overhead.", minPMTU, baseline.MTU),
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "mtu.below_interface", minPMTU, baseline.MTU))
 	}
 
 	if len(blackholeTargets) > 0 {
 		result.Status = StatusWarn
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "Possible blackhole MTU",
-			Detail:   fmt.Sprintf("Targets with DF loss and no ICMP fragmentation replies: %s.", strings.Join(blackholeTargets, ", ")),
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "mtu.blackhole", strings.Join(blackholeTargets, ", ")))
 		result.Metrics["blackhole_mtu"] = "probable"
 	} else {
 		result.Metrics["blackhole_mtu"] = "no"
 	}
 
-	mssResult := collectMSS(ctx, targets)
+	mssResult := collectMSS(ctx, config.Addresses(targets))
 	if mssResult.Err != nil {
 		result.Status = StatusWarn
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "MSS observation failed",
-			Detail:   mssResult.Err.Error(),
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "mtu.mss_failed", mssResult.Err.Error()))
 	} else if mssResult.MSS > 0 {
 		result.Metrics["mss_observed"] = fmt.Sprintf("%d", mssResult.MSS)
 		result.Metrics["mss_class"] = mssResult.Class
-		if mssResult.Class != "assente" {
+		if mssResult.Class != "none" {
 			result.Status = StatusWarn
-			result.Findings = append(result.Findings, model.Finding{
-				Severity: "WARN",
-				Title:    "MSS clamping detected",
-				Detail:   fmt.Sprintf("Observed MSS %d (%s).", mssResult.MSS, mssResult.Class),
-			})
+			result.Findings = append(result.Findings, newFinding("WARN", "mtu.mss_detected", mssResult.MSS, i18n.Label("mss.class", mssResult.Class)))
 		}
 	}
 
 	result.Metrics["mtu_health"] = scoreMTUHealth(minPMTU, len(blackholeTargets) > 0, mssResult.MSS, baseline.MTU)
 	if result.Metrics["mtu_health"] != "OK" {
 		result.Status = StatusWarn
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "WARN",
-			Title:    "MTU health warning",
-			Detail:   fmt.Sprintf("MTU health rated %s. PMTU min %d, blackhole=%s, MSS=%s.", result.Metrics["mtu_health"], minPMTU, result.Metrics["blackhole_mtu"], result.Metrics["mss_class"]),
-		})
+		result.Findings = append(result.Findings, newFinding("WARN", "mtu.health_warning",
+			i18n.Label("mtu.health", result.Metrics["mtu_health"]),
+			minPMTU,
+			i18n.Label("mtu.blackhole_state", result.Metrics["blackhole_mtu"]),
+			i18n.Label("mss.class", result.Metrics["mss_class"]),
+		))
 	}
 
 	result.Findings = append(result.Findings, suggestRemediations(minPMTU, len(blackholeTargets) > 0, mssResult.Class)...)
@@ -667,13 +643,13 @@ func normalizeAddress(target, defaultPort string) (string, error) {
 func classifyMSS(mss int) string {
 	switch {
 	case mss >= 1456:
-		return "assente"
+		return "none"
 	case mss >= 1440:
-		return "pppoe_sospetto"
+		return "pppoe_suspected"
 	case mss >= 1360:
-		return "basso"
+		return "low"
 	default:
-		return "aggressivo"
+		return "aggressive"
 	}
 }
 
@@ -699,32 +675,16 @@ func scoreMTUHealth(pmtuMin int, blackhole bool, mss int, ifaceMTU int) string {
 func suggestRemediations(pmtuMin int, blackhole bool, mssClass string) []model.Finding {
 	var findings []model.Finding
 	if pmtuMin > 0 && pmtuMin < 1500 {
-		findings = append(findings, model.Finding{
-			Severity: "INFO",
-			Title:    "Suggested MTU",
-			Detail:   fmt.Sprintf("Suggested effective MTU: %d (based on PMTU min).", pmtuMin),
-		})
+		findings = append(findings, newFinding("INFO", "mtu.suggested", pmtuMin))
 	}
 	if blackhole {
-		findings = append(findings, model.Finding{
-			Severity: "WARN",
-			Title:    "Possible ICMP blocking",
-			Detail:   "Some paths likely block ICMP fragmentation-needed replies (blackhole MTU). This can cause pages not loading or unstable VPNs.",
-		})
-	}
-	if mssClass == "pppoe_sospetto" {
-		findings = append(findings, model.Finding{
-			Severity: "WARN",
-			Title:    "PPPoE overhead suspected",
-			Detail:   "Observed MSS suggests PPPoE/overhead (MTU ~1492).",
-		})
-	}
-	if mssClass == "basso" || mssClass == "aggressivo" {
-		findings = append(findings, model.Finding{
-			Severity: "WARN",
-			Title:    "MSS clamping",
-			Detail:   "Observed MSS is lower than expected; a router or upstream network is clamping MSS.",
-		})
+		findings = append(findings, newFinding("WARN", "mtu.icmp_blocking"))
+	}
+	if mssClass == "pppoe_suspected" {
+		findings = append(findings, newFinding("WARN", "mtu.pppoe_suspected"))
+	}
+	if mssClass == "low" || mssClass == "aggressive" {
+		findings = append(findings, newFinding("WARN", "mtu.mss_clamping"))
 	}
 	return findings
 }
@@ -0,0 +1,371 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"conncheck/internal/model"
+	"conncheck/internal/sys"
+)
+
+// neighborPollInterval is how often the neighbour table is read between the
+// start and end snapshots, so a gateway MAC that flips and flips back is
+// still caught.
+const neighborPollInterval = 10 * time.Second
+
+var (
+	arpingReceivedRe = regexp.MustCompile(`Received (\d+) response`)
+	arpingMACRe      = regexp.MustCompile(`\[([0-9A-Fa-f:]{17})\]`)
+)
+
+// Neighbors watches the ARP/NDP neighbour table for the whole run: a gateway
+// answering from a new MAC, several MACs for one address and a duplicate of
+// our own address.
+type Neighbors struct {
+	outDir string
+
+	gateway   string
+	snapshots []neighborSnapshot
+	evidence  []model.Evidence
+	mu        sync.Mutex
+	stop      chan struct{}
+	done      sync.WaitGroup
+}
+
+type neighborSnapshot struct {
+	at      time.Time
+	entries []neighbor
+	output  string
+}
+
+func NewNeighbors(outDir string) *Neighbors {
+	return &Neighbors{outDir: outDir}
+}
+
+func (n *Neighbors) Name() string {
+	return "neighbors"
+}
+
+// Start takes the first snapshot and polls the table until Run.
+func (n *Neighbors) Start(ctx context.Context) {
+	n.detectGateway()
+	n.snapshot("neighbors_start", true)
+	n.stop = make(chan struct{})
+	n.done.Add(1)
+	go func() {
+		defer n.done.Done()
+		ticker := time.NewTicker(neighborPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-n.stop:
+				return
+			case <-ticker.C:
+				n.snapshot("", false)
+			}
+		}
+	}()
+}
+
+func (n *Neighbors) detectGateway() {
+	gateway, routeLog, _ := DetectDefaultGateway(n.outDir)
+	n.gateway = gateway
+	if routeLog != "" {
+		n.evidence = append(n.evidence, model.Evidence{Label: "route_print", Path: routeLog})
+	}
+}
+
+// snapshot reads the table. Logged snapshots are kept as evidence; polled
+// ones only when they turn out to show a change.
+func (n *Neighbors) snapshot(label string, logged bool) {
+	var (
+		output  string
+		logPath string
+		err     error
+	)
+	if logged {
+		output, logPath, err = neighborTable(n.outDir)
+	} else {
+		output, err = neighborTableNoLog()
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if logPath != "" {
+		evidence := model.Evidence{Label: label, Path: logPath}
+		if err != nil {
+			evidence.Note = err.Error()
+		}
+		n.evidence = append(n.evidence, evidence)
+	}
+	if err != nil {
+		return
+	}
+	n.snapshots = append(n.snapshots, neighborSnapshot{at: time.Now(), entries: parseNeighborTable(output), output: output})
+}
+
+func (n *Neighbors) Run(ctx context.Context) model.TestResult {
+	result := baseResult(n.Name())
+	result.StartedAt = time.Now()
+	if n.stop == nil {
+		// Run without Start (e.g. called on its own): the end snapshot is
+		// all there is.
+		n.detectGateway()
+	} else {
+		close(n.stop)
+		n.done.Wait()
+	}
+	n.snapshot("neighbors_end", true)
+	result.Evidence = append(result.Evidence, n.evidence...)
+	if len(n.snapshots) == 0 {
+		result.Findings = append(result.Findings, newFinding("INFO", "neighbors.table_failed"))
+		result.EndedAt = time.Now()
+		return result
+	}
+
+	last := n.snapshots[len(n.snapshots)-1]
+	result.Status = StatusOK
+	result.Metrics["snapshots"] = fmt.Sprintf("%d", len(n.snapshots))
+	result.Metrics["neighbors"] = fmt.Sprintf("%d", len(last.entries))
+	n.checkGateway(&result)
+	n.checkAddresses(&result)
+	n.checkOwnAddress(ctx, &result)
+
+	result.EndedAt = time.Now()
+	return result
+}
+
+// checkGateway flags a gateway seen at more than one MAC during the run.
+func (n *Neighbors) checkGateway(result *model.TestResult) {
+	if n.gateway == "" {
+		return
+	}
+	result.Metrics["gateway"] = n.gateway
+	history := n.macHistory(func(entry neighbor) bool { return entry.IP == n.gateway })
+	if len(history) == 0 {
+		return
+	}
+	result.Metrics["gateway_mac"] = history[len(history)-1].mac
+	if len(history) == 1 {
+		return
+	}
+	macs := make([]string, 0, len(history))
+	for _, seen := range history {
+		macs = append(macs, seen.mac)
+	}
+	result.Metrics["gateway_macs"] = joinList(macs)
+	result.Status = worseStatus(result.Status, StatusFail)
+	result.Findings = append(result.Findings, newFinding("FAIL", "neighbors.gateway_mac_changed", n.gateway, strings.Join(macs, " → ")))
+	n.recordChanges(result, n.gateway, history)
+}
+
+// checkAddresses flags any other address answered by several MACs on the
+// same interface, within one snapshot or across them.
+func (n *Neighbors) checkAddresses(result *model.TestResult) {
+	keys := map[string]neighbor{}
+	for _, snapshot := range n.snapshots {
+		for _, entry := range snapshot.entries {
+			if entry.IP != n.gateway && entry.resolved() && !isMulticastMAC(entry.MAC) {
+				keys[entry.IP+"%"+entry.Iface] = entry
+			}
+		}
+	}
+	var conflicts []string
+	for _, key := range sortedNeighborKeys(keys) {
+		target := keys[key]
+		history := n.macHistory(func(entry neighbor) bool { return entry.IP == target.IP && entry.Iface == target.Iface })
+		if len(history) < 2 {
+			continue
+		}
+		macs := make([]string, 0, len(history))
+		for _, seen := range history {
+			macs = append(macs, seen.mac)
+		}
+		conflicts = append(conflicts, target.IP)
+		result.Findings = append(result.Findings, newFinding("WARN", "neighbors.ip_multiple_macs", target.IP, strings.Join(macs, ", ")))
+		n.recordChanges(result, target.IP, history)
+	}
+	if len(conflicts) > 0 {
+		result.Metrics["conflicts"] = joinList(conflicts)
+		result.Status = worseStatus(result.Status, StatusWarn)
+	}
+}
+
+type macSighting struct {
+	mac      string
+	snapshot int
+}
+
+// macHistory lists the MACs of the entries matching match each time it
+// changed, with the snapshot that showed it, so a MAC that flips and flips
+// back appears three times.
+func (n *Neighbors) macHistory(match func(neighbor) bool) []macSighting {
+	var history []macSighting
+	for i, snapshot := range n.snapshots {
+		for _, entry := range snapshot.entries {
+			if !match(entry) || !entry.resolved() {
+				continue
+			}
+			if len(history) == 0 || history[len(history)-1].mac != entry.MAC {
+				history = append(history, macSighting{mac: entry.MAC, snapshot: i})
+			}
+		}
+	}
+	return history
+}
+
+// recordChanges adds, as evidence, each snapshot where the MAC of ip changed.
+// Polled snapshots were not logged, so they are written out now.
+func (n *Neighbors) recordChanges(result *model.TestResult, ip string, history []macSighting) {
+	for _, seen := range history[1:] {
+		snapshot := n.snapshots[seen.snapshot]
+		path := writeRawLog(n.outDir, "neighbors_change", snapshot.output)
+		if path == "" {
+			continue
+		}
+		result.Evidence = append(result.Evidence, model.Evidence{
+			Label: "neighbors_change",
+			Path:  path,
+			Note:  fmt.Sprintf("%s at %s (%s)", ip, seen.mac, snapshot.at.Format(time.TimeOnly)),
+		})
+	}
+}
+
+// checkOwnAddress looks for another host using our address: an RFC 5227 ARP
+// probe for IPv4, where arping is installed and permitted, and the kernel's
+// duplicate address detection for IPv6 on Linux.
+func (n *Neighbors) checkOwnAddress(ctx context.Context, result *model.TestResult) {
+	probe := "unavailable"
+	defer func() { result.Metrics["duplicate_address_probe"] = probe }()
+	if runtime.GOOS == "linux" {
+		if output, logPath, err := sys.RunCommand(n.outDir, "ip", "-o", "addr", "show"); err == nil {
+			if failed := dadFailedAddresses(output); len(failed) > 0 {
+				result.Evidence = append(result.Evidence, model.Evidence{Label: "ip_addr", Path: logPath, Note: "dadfailed"})
+				result.Status = worseStatus(result.Status, StatusFail)
+				for _, address := range failed {
+					result.Findings = append(result.Findings, newFinding("FAIL", "neighbors.duplicate_address", address, "IPv6 DAD"))
+				}
+				probe = "duplicate"
+			}
+		}
+	}
+	if n.gateway == "" || ctx.Err() != nil {
+		return
+	}
+	own, iface := localAddressFor(n.gateway)
+	if !own.Is4() || iface == "" {
+		return
+	}
+	result.Metrics["own_address"] = own.String()
+	if _, err := exec.LookPath("arping"); err != nil {
+		return
+	}
+	output, logPath, _ := sys.RunCommand(n.outDir, "arping", "-D", "-c", "2", "-w", "3", "-I", iface, own.String())
+	m := arpingReceivedRe.FindStringSubmatch(output)
+	if len(m) != 2 {
+		// No summary line: arping lacked the privileges or is not the
+		// iputils flavour that supports -D.
+		return
+	}
+	if m[1] == "0" {
+		if probe != "duplicate" {
+			probe = "clear"
+		}
+		return
+	}
+	probe = "duplicate"
+	other := "?"
+	if mac := arpingMACRe.FindStringSubmatch(output); len(mac) == 2 {
+		other = normalizeMAC(mac[1])
+	}
+	result.Evidence = append(result.Evidence, model.Evidence{Label: "arping_dad", Path: logPath})
+	result.Status = worseStatus(result.Status, StatusFail)
+	result.Findings = append(result.Findings, newFinding("FAIL", "neighbors.duplicate_address", own.String(), other))
+}
+
+// localAddressFor returns the local address used to reach gateway and the
+// interface holding it. Connecting a UDP socket sends nothing.
+func localAddressFor(gateway string) (netip.Addr, string) {
+	conn, err := net.Dial("udp", net.JoinHostPort(gateway, "9"))
+	if err != nil {
+		return netip.Addr{}, ""
+	}
+	own := conn.LocalAddr().(*net.UDPAddr).AddrPort().Addr().Unmap()
+	conn.Close()
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return own, ""
+	}
+	for _, iface := range interfaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if prefix, err := netip.ParsePrefix(addr.String()); err == nil && prefix.Addr().Unmap() == own {
+				return own, iface.Name
+			}
+		}
+	}
+	return own, ""
+}
+
+// dadFailedAddresses returns the addresses `ip -o addr show` flags as
+// dadfailed: the kernel saw another host claim them.
+func dadFailedAddresses(output string) []string {
+	var failed []string
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "dadfailed") {
+			continue
+		}
+		fields := strings.Fields(line)
+		for i := 0; i+1 < len(fields); i++ {
+			if fields[i] == "inet6" || fields[i] == "inet" {
+				failed = append(failed, strings.Split(fields[i+1], "/")[0])
+				break
+			}
+		}
+	}
+	return failed
+}
+
+// isMulticastMAC reports whether mac has the group bit set, which covers
+// broadcast and the multicast entries Windows lists as static.
+func isMulticastMAC(mac string) bool {
+	var first byte
+	if _, err := fmt.Sscanf(mac, "%02x", &first); err != nil {
+		return false
+	}
+	return first&1 == 1
+}
+
+func sortedNeighborKeys(entries map[string]neighbor) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeRawLog saves content under raw_logs like sys.RunCommand does and
+// returns the path, or "" when it could not be written.
+func writeRawLog(outDir, name, content string) string {
+	path := filepath.Join(outDir, "raw_logs", fmt.Sprintf("%s_%d.log", name, time.Now().UnixNano()))
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMACHistory(t *testing.T) {
+	const (
+		gateway = "192.168.1.1"
+		macA    = "aa:bb:cc:dd:ee:01"
+		macB    = "aa:bb:cc:dd:ee:02"
+	)
+	entry := func(ip, mac, state string) neighbor {
+		return neighbor{IP: ip, MAC: mac, State: state, Iface: "eth0"}
+	}
+	tests := []struct {
+		name      string
+		snapshots [][]neighbor
+		want      []macSighting
+	}{
+		{
+			name:      "stable",
+			snapshots: [][]neighbor{{entry(gateway, macA, "REACHABLE")}, {entry(gateway, macA, "STALE")}, {entry(gateway, macA, "REACHABLE")}},
+			want:      []macSighting{{mac: macA, snapshot: 0}},
+		},
+		{
+			name:      "flip",
+			snapshots: [][]neighbor{{entry(gateway, macA, "REACHABLE")}, {entry(gateway, macA, "REACHABLE")}, {entry(gateway, macB, "REACHABLE")}},
+			want:      []macSighting{{mac: macA, snapshot: 0}, {mac: macB, snapshot: 2}},
+		},
+		{
+			name:      "flip and back",
+			snapshots: [][]neighbor{{entry(gateway, macA, "REACHABLE")}, {entry(gateway, macB, "REACHABLE")}, {entry(gateway, macA, "REACHABLE")}},
+			want:      []macSighting{{mac: macA, snapshot: 0}, {mac: macB, snapshot: 1}, {mac: macA, snapshot: 2}},
+		},
+		{
+			name:      "unresolved and missing entries are not changes",
+			snapshots: [][]neighbor{{entry(gateway, macA, "REACHABLE")}, {entry(gateway, "", "FAILED")}, {}, {entry(gateway, macA, "REACHABLE")}},
+			want:      []macSighting{{mac: macA, snapshot: 0}},
+		},
+		{
+			name:      "other addresses are ignored",
+			snapshots: [][]neighbor{{entry(gateway, macA, "REACHABLE"), entry("192.168.1.20", macB, "REACHABLE")}, {entry(gateway, macA, "REACHABLE")}},
+			want:      []macSighting{{mac: macA, snapshot: 0}},
+		},
+		{
+			name:      "never resolved",
+			snapshots: [][]neighbor{{entry(gateway, "", "INCOMPLETE")}},
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := NewNeighbors(t.TempDir())
+			for _, entries := range tt.snapshots {
+				n.snapshots = append(n.snapshots, neighborSnapshot{entries: entries})
+			}
+			got := n.macHistory(func(e neighbor) bool { return e.IP == gateway })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("macHistory = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
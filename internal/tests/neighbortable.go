@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"net/netip"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"conncheck/internal/sys"
+)
+
+// neighbor is one entry of the ARP/NDP neighbour table.
+type neighbor struct {
+	IP    string
+	MAC   string
+	State string
+	Iface string
+}
+
+// resolved reports whether the entry holds a usable link-layer address.
+func (n neighbor) resolved() bool {
+	switch n.State {
+	case "FAILED", "INCOMPLETE":
+		return false
+	}
+	return n.MAC != ""
+}
+
+var (
+	bsdARPLineRe   = regexp.MustCompile(`\(([^)]+)\) at ([0-9a-fA-F:]+) on (\S+)`)
+	winARPIfaceRe  = regexp.MustCompile(`^Interface:\s+(\S+)`)
+	macAddressRe   = regexp.MustCompile(`^[0-9a-fA-F]{1,2}([:-][0-9a-fA-F]{1,2}){5}$`)
+	zeroMACAddress = "00:00:00:00:00:00"
+)
+
+// readNeighbors dumps the neighbour table: `ip neigh show` on Linux, which
+// covers ARP and NDP, and `arp -a` elsewhere.
+func readNeighbors(outDir string) ([]neighbor, string, error) {
+	output, logPath, err := neighborTable(outDir)
+	if err != nil {
+		return nil, logPath, err
+	}
+	return parseNeighborTable(output), logPath, nil
+}
+
+func neighborTable(outDir string) (string, string, error) {
+	if runtime.GOOS == "linux" {
+		return sys.RunCommand(outDir, "ip", "neigh", "show")
+	}
+	return sys.RunCommand(outDir, "arp", "-a")
+}
+
+func neighborTableNoLog() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if runtime.GOOS == "linux" {
+		return sys.RunCommandNoLog(ctx, "ip", "neigh", "show")
+	}
+	return sys.RunCommandNoLog(ctx, "arp", "-a")
+}
+
+func parseNeighborTable(output string) []neighbor {
+	if runtime.GOOS == "linux" {
+		return parseIPNeigh(output)
+	}
+	return parseARPTable(output)
+}
+
+// parseIPNeigh parses lines such as
+// "192.168.1.1 dev eth0 lladdr aa:bb:cc:dd:ee:ff REACHABLE".
+func parseIPNeigh(output string) []neighbor {
+	var neighbors []neighbor
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if _, err := netip.ParseAddr(fields[0]); err != nil {
+			continue
+		}
+		entry := neighbor{IP: fields[0], State: fields[len(fields)-1]}
+		for i := 1; i+1 < len(fields); i++ {
+			switch fields[i] {
+			case "dev":
+				entry.Iface = fields[i+1]
+			case "lladdr":
+				entry.MAC = normalizeMAC(fields[i+1])
+			}
+		}
+		neighbors = append(neighbors, entry)
+	}
+	return neighbors
+}
+
+// parseARPTable parses `arp -a` from Windows ("Interface:" headers followed by
+// address, MAC and type columns) and from macOS/BSD
+// ("? (192.168.1.1) at aa:bb:cc:dd:ee:ff on en0 ...").
+func parseARPTable(output string) []neighbor {
+	var neighbors []neighbor
+	iface := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if m := winARPIfaceRe.FindStringSubmatch(line); len(m) == 2 {
+			iface = m[1]
+			continue
+		}
+		if m := bsdARPLineRe.FindStringSubmatch(line); len(m) == 4 {
+			neighbors = append(neighbors, neighbor{IP: m[1], MAC: normalizeMAC(m[2]), State: "REACHABLE", Iface: m[3]})
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !macAddressRe.MatchString(fields[1]) {
+			continue
+		}
+		if _, err := netip.ParseAddr(fields[0]); err != nil {
+			continue
+		}
+		neighbors = append(neighbors, neighbor{IP: fields[0], MAC: normalizeMAC(fields[1]), State: strings.ToUpper(fields[2]), Iface: iface})
+	}
+	return neighbors
+}
+
+// normalizeMAC returns mac in lower case with colons and two digits per
+// octet, or "" for the all-zero address some tables use for unresolved
+// entries.
+func normalizeMAC(mac string) string {
+	parts := strings.FieldsFunc(strings.ToLower(mac), func(r rune) bool { return r == ':' || r == '-' })
+	if len(parts) != 6 {
+		return ""
+	}
+	for i, part := range parts {
+		if len(part) == 1 {
+			parts[i] = "0" + part
+		}
+	}
+	normalized := strings.Join(parts, ":")
+	if normalized == zeroMACAddress {
+		return ""
+	}
+	return normalized
+}
+
+// findNeighbor returns the entry for ip, preferring a resolved one.
+func findNeighbor(neighbors []neighbor, ip string) (neighbor, bool) {
+	var found neighbor
+	ok := false
+	for _, entry := range neighbors {
+		if entry.IP != ip {
+			continue
+		}
+		if entry.resolved() {
+			return entry, true
+		}
+		found, ok = entry, true
+	}
+	return found, ok
+}
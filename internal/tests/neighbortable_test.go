@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIPNeigh(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []neighbor
+	}{
+		{
+			name:   "reachable IPv4",
+			output: "192.168.1.1 dev eth0 lladdr aa:bb:cc:dd:ee:ff REACHABLE\n",
+			want:   []neighbor{{IP: "192.168.1.1", MAC: "aa:bb:cc:dd:ee:ff", State: "REACHABLE", Iface: "eth0"}},
+		},
+		{
+			name:   "IPv6 router flag",
+			output: "fe80::1 dev wlan0 lladdr AA:BB:CC:DD:EE:01 router STALE\n",
+			want:   []neighbor{{IP: "fe80::1", MAC: "aa:bb:cc:dd:ee:01", State: "STALE", Iface: "wlan0"}},
+		},
+		{
+			name: "failed and incomplete have no MAC",
+			output: "192.168.1.7 dev eth0  FAILED\n" +
+				"192.168.1.8 dev eth0  INCOMPLETE\n",
+			want: []neighbor{
+				{IP: "192.168.1.7", State: "FAILED", Iface: "eth0"},
+				{IP: "192.168.1.8", State: "INCOMPLETE", Iface: "eth0"},
+			},
+		},
+		{
+			name:   "zero MAC is unresolved",
+			output: "192.168.1.9 dev eth0 lladdr 00:00:00:00:00:00 PERMANENT\n",
+			want:   []neighbor{{IP: "192.168.1.9", State: "PERMANENT", Iface: "eth0"}},
+		},
+		{
+			name:   "lines without an address are skipped",
+			output: "\nDump was interrupted and may be inconsistent.\n192.168.1.1 dev eth0 lladdr aa:bb:cc:dd:ee:ff DELAY\n",
+			want:   []neighbor{{IP: "192.168.1.1", MAC: "aa:bb:cc:dd:ee:ff", State: "DELAY", Iface: "eth0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseIPNeigh(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseIPNeigh =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseARPTable(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []neighbor
+	}{
+		{
+			name: "Windows",
+			output: "\r\nInterface: 192.168.1.10 --- 0xb\r\n" +
+				"  Internet Address      Physical Address      Type\r\n" +
+				"  192.168.1.1           aa-bb-cc-dd-ee-ff     dynamic   \r\n" +
+				"  192.168.1.255         ff-ff-ff-ff-ff-ff     static    \r\n" +
+				"\r\nInterface: 10.8.0.2 --- 0x11\r\n" +
+				"  Internet Address      Physical Address      Type\r\n" +
+				"  10.8.0.1              00-ff-3a-12-00-01     dynamic   \r\n",
+			want: []neighbor{
+				{IP: "192.168.1.1", MAC: "aa:bb:cc:dd:ee:ff", State: "DYNAMIC", Iface: "192.168.1.10"},
+				{IP: "192.168.1.255", MAC: "ff:ff:ff:ff:ff:ff", State: "STATIC", Iface: "192.168.1.10"},
+				{IP: "10.8.0.1", MAC: "00:ff:3a:12:00:01", State: "DYNAMIC", Iface: "10.8.0.2"},
+			},
+		},
+		{
+			name: "Windows zero MAC",
+			output: "Interface: 192.168.1.10 --- 0xb\n" +
+				"  192.168.1.50          00-00-00-00-00-00     invalid\n",
+			want: []neighbor{{IP: "192.168.1.50", State: "INVALID", Iface: "192.168.1.10"}},
+		},
+		{
+			name: "macOS short octets and incomplete",
+			output: "? (192.168.1.1) at 0:11:22:3:44:5 on en0 ifscope [ethernet]\n" +
+				"? (192.168.1.7) at (incomplete) on en0 ifscope [ethernet]\n" +
+				"router.lan (192.168.1.254) at aa:bb:cc:dd:ee:ff on en0 ifscope permanent [ethernet]\n",
+			want: []neighbor{
+				{IP: "192.168.1.1", MAC: "00:11:22:03:44:05", State: "REACHABLE", Iface: "en0"},
+				{IP: "192.168.1.254", MAC: "aa:bb:cc:dd:ee:ff", State: "REACHABLE", Iface: "en0"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseARPTable(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseARPTable =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeighborResolved(t *testing.T) {
+	tests := []struct {
+		entry neighbor
+		want  bool
+	}{
+		{entry: neighbor{MAC: "aa:bb:cc:dd:ee:ff", State: "REACHABLE"}, want: true},
+		{entry: neighbor{MAC: "aa:bb:cc:dd:ee:ff", State: "STALE"}, want: true},
+		{entry: neighbor{MAC: "aa:bb:cc:dd:ee:ff", State: "FAILED"}, want: false},
+		{entry: neighbor{State: "INCOMPLETE"}, want: false},
+		{entry: neighbor{State: "PERMANENT"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.resolved(); got != tt.want {
+			t.Errorf("%+v resolved = %v, want %v", tt.entry, got, tt.want)
+		}
+	}
+}
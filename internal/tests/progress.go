@@ -0,0 +1,21 @@
+package tests
+
+import (
+	"context"
+
+	"conncheck/internal/model"
+)
+
+type progressKey struct{}
+
+// WithProgress returns a context through which a running test reports its
+// progress to report. Tests run the same way without one.
+func WithProgress(ctx context.Context, report func(model.Progress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+func reportProgress(ctx context.Context, progress model.Progress) {
+	if report, ok := ctx.Value(progressKey{}).(func(model.Progress)); ok {
+		report(progress)
+	}
+}
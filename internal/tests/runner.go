@@ -10,3 +10,11 @@ type Runner interface {
 	Name() string
 	Run(ctx context.Context) model.TestResult
 }
+
+// Watcher is a Runner that observes the whole run rather than a moment of
+// it. The engine calls Start before the first test; Run, scheduled after the
+// other tests, ends the observation and reports on it. Start must not block.
+type Watcher interface {
+	Runner
+	Start(ctx context.Context)
+}
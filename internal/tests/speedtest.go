@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"os/exec"
 	"time"
 
 	"conncheck/internal/config"
+	"conncheck/internal/i18n"
 	"conncheck/internal/model"
 	"conncheck/internal/sys"
 )
@@ -15,6 +17,7 @@ import (
 type Speedtest struct {
 	outDir string
 	cfg    config.Config
+	link   *model.Link
 }
 
 type speedtestResult struct {
@@ -33,8 +36,10 @@ type speedtestResult struct {
 	} `json:"server"`
 }
 
-func NewSpeedtest(outDir string, cfg config.Config) *Speedtest {
-	return &Speedtest{outDir: outDir, cfg: cfg}
+// NewSpeedtest returns the speedtest; link, when known, is the local link
+// its throughput is compared with.
+func NewSpeedtest(outDir string, cfg config.Config, link *model.Link) *Speedtest {
+	return &Speedtest{outDir: outDir, cfg: cfg, link: link}
 }
 
 func (s *Speedtest) Name() string {
@@ -48,11 +53,7 @@ func (s *Speedtest) Run(ctx context.Context) model.TestResult {
 	binary, err := exec.LookPath("speedtest")
 	if err != nil {
 		result.Status = StatusSkipped
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "INFO",
-			Title:    "speedtest.exe not found",
-			Detail:   "Place Ookla Speedtest CLI (speedtest.exe) next to the tool or in PATH.",
-		})
+		result.Findings = append(result.Findings, newFinding("INFO", "speedtest.binary_missing"))
 		result.EndedAt = time.Now()
 		return result
 	}
@@ -62,10 +63,10 @@ func (s *Speedtest) Run(ctx context.Context) model.TestResult {
 		label string
 		cfg   config.SpeedtestCategory
 	}{
-		{name: "local", label: "Local", cfg: s.cfg.Speedtest.Local},
-		{name: "national", label: "National", cfg: s.cfg.Speedtest.National},
-		{name: "eu", label: "EU", cfg: s.cfg.Speedtest.EU},
-		{name: "us", label: "US", cfg: s.cfg.Speedtest.US},
+		{name: "local", label: i18n.T("speedtest.category.local"), cfg: s.cfg.Speedtest.Local},
+		{name: "national", label: i18n.T("speedtest.category.national"), cfg: s.cfg.Speedtest.National},
+		{name: "eu", label: i18n.T("speedtest.category.eu"), cfg: s.cfg.Speedtest.EU},
+		{name: "us", label: i18n.T("speedtest.category.us"), cfg: s.cfg.Speedtest.US},
 	}
 
 	if allSpeedtestCategoriesEmpty(categories) {
@@ -74,12 +75,18 @@ func (s *Speedtest) Run(ctx context.Context) model.TestResult {
 			label string
 			cfg   config.SpeedtestCategory
 		}{
-			{name: "local", label: "Local", cfg: config.SpeedtestCategory{ServerIDs: []int{0}, Runs: 1, Weight: 1}},
+			{name: "local", label: i18n.T("speedtest.category.local"), cfg: config.SpeedtestCategory{ServerIDs: []int{0}, Runs: 1, Weight: 1}},
 		}
 	}
 
+	totalRuns, doneRuns := 0, 0
+	for _, category := range categories {
+		totalRuns += len(category.cfg.ServerIDs) * max(category.cfg.Runs, 1)
+	}
+
 	totalScore := 0.0
 	totalWeight := 0.0
+	bestDown, bestUp := 0.0, 0.0
 	for _, category := range categories {
 		serverIDs := category.cfg.ServerIDs
 		if len(serverIDs) == 0 {
@@ -104,21 +111,20 @@ func (s *Speedtest) Run(ctx context.Context) model.TestResult {
 				if serverID > 0 {
 					args = append(args, fmt.Sprintf("--server-id=%d", serverID))
 				}
+				runStarted := time.Now()
 				output, logPath, err := sys.RunCommand(s.outDir, binary, args...)
+				doneRuns++
 				if logPath != "" {
 					result.Evidence = append(result.Evidence, model.Evidence{
 						Label: "speedtest_raw",
 						Path:  logPath,
-						Note:  fmt.Sprintf("%s server %d run %d", category.label, serverID, runIndex),
+						Note:  i18n.T("speedtest.run_note", category.label, serverID, runIndex),
 					})
 				}
 				if err != nil {
 					result.Status = StatusWarn
-					result.Findings = append(result.Findings, model.Finding{
-						Severity: "WARN",
-						Title:    "Speedtest failed",
-						Detail:   fmt.Sprintf("%s server %d run %d: %s", category.label, serverID, runIndex, err.Error()),
-					})
+					result.Findings = append(result.Findings, newFinding("WARN", "speedtest.failed", category.label, serverID, runIndex, err.Error()))
+					reportProgress(ctx, model.Progress{Fraction: float64(doneRuns) / float64(totalRuns)})
 					continue
 				}
 
@@ -136,6 +142,13 @@ func (s *Speedtest) Run(ctx context.Context) model.TestResult {
 					result.Metrics[fmt.Sprintf("%s_down_bps", keyPrefix)] = fmt.Sprintf("%.0f", downBps)
 					result.Metrics[fmt.Sprintf("%s_up_bps", keyPrefix)] = fmt.Sprintf("%.0f", upBps)
 					result.Metrics[fmt.Sprintf("%s_name", keyPrefix)] = parsed.Server.Name
+					result.Metrics[fmt.Sprintf("%s_time", keyPrefix)] = runStarted.UTC().Format(time.RFC3339)
+					reportProgress(ctx, model.Progress{
+						Fraction: float64(doneRuns) / float64(totalRuns),
+						Target:   parsed.Server.Name,
+						DownBps:  downBps,
+						UpBps:    upBps,
+					})
 				}
 			}
 		}
@@ -149,6 +162,9 @@ func (s *Speedtest) Run(ctx context.Context) model.TestResult {
 			result.Metrics[fmt.Sprintf("%s_avg_ping_ms", category.name)] = fmt.Sprintf("%.2f", avgPing)
 			result.Metrics[fmt.Sprintf("%s_score_bps", category.name)] = fmt.Sprintf("%.0f", avgDown)
 
+			bestDown = math.Max(bestDown, avgDown)
+			bestUp = math.Max(bestUp, avgUp)
+
 			if weight > 0 {
 				totalScore += avgDown * weight
 				totalWeight += weight
@@ -163,6 +179,7 @@ func (s *Speedtest) Run(ctx context.Context) model.TestResult {
 	if result.Status == StatusSkipped {
 		result.Status = StatusOK
 	}
+	checkSpeedtestCap(&result, s.link, math.Max(bestDown, bestUp))
 	if result.Status == StatusOK && len(result.Metrics) == 0 {
 		result.Status = StatusWarn
 	}
@@ -2,8 +2,12 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,40 +33,38 @@ func (t *Traceroute) Run(ctx context.Context) model.TestResult {
 	result := baseResult(t.Name())
 	result.StartedAt = time.Now()
 
-	if len(t.cfg.Targets.Traceroute) == 0 {
+	targets := t.cfg.Targets.Resolve(t.cfg.Targets.Traceroute)
+	if len(targets) == 0 {
 		result.Status = StatusSkipped
-		result.Findings = append(result.Findings, model.Finding{
-			Severity: "INFO",
-			Title:    "No traceroute targets",
-			Detail:   "No traceroute targets are configured.",
-		})
+		result.Findings = append(result.Findings, newFinding("INFO", "traceroute.no_targets"))
 		result.EndedAt = time.Now()
 		return result
 	}
 
 	result.Status = StatusOK
-	for _, target := range t.cfg.Targets.Traceroute {
+	for _, entry := range targets {
+		target := entry.Address
+		args := append(stackArgs(entry.Stack), target)
 		var output, logPath string
 		var err error
 		if runtime.GOOS == "windows" {
-			output, logPath, err = sys.RunCommand(t.outDir, "tracert", target)
+			output, logPath, err = sys.RunCommand(t.outDir, "tracert", args...)
 		} else {
-			output, logPath, err = sys.RunCommand(t.outDir, "traceroute", target)
+			output, logPath, err = sys.RunCommand(t.outDir, "traceroute", args...)
 		}
 		if logPath != "" {
 			result.Evidence = append(result.Evidence, model.Evidence{Label: fmt.Sprintf("trace_%s", target), Path: logPath})
 		}
 		if err != nil {
 			result.Status = StatusWarn
-			result.Findings = append(result.Findings, model.Finding{
-				Severity: "WARN",
-				Title:    "Traceroute failed",
-				Detail:   fmt.Sprintf("Traceroute to %s failed: %s", target, err.Error()),
-			})
+			result.Findings = append(result.Findings, newFinding("WARN", "traceroute.failed", target, err.Error()))
 			continue
 		}
 		hops := countTracerouteHops(output)
 		result.Metrics[fmt.Sprintf("%s_hops", target)] = fmt.Sprintf("%d", hops)
+		if hopsJSON, err := json.Marshal(parseTracerouteHops(output)); err == nil {
+			result.Metrics[fmt.Sprintf("trace_hops.%s", target)] = string(hopsJSON)
+		}
 	}
 
 	result.EndedAt = time.Now()
@@ -80,3 +82,77 @@ func countTracerouteHops(output string) int {
 	}
 	return count
 }
+
+var tracerouteRTTRe = regexp.MustCompile(`^<?([\d.,]+)$`)
+
+type tracerouteHop struct {
+	Hop     int       `json:"hop"`
+	Host    string    `json:"host,omitempty"`
+	Address string    `json:"address,omitempty"`
+	RTTs    []float64 `json:"rtt_ms"`
+	Timeout bool      `json:"timeout"`
+}
+
+// parseTracerouteHops extracts per-hop host and RTT samples from Linux
+// traceroute and Windows tracert output.
+func parseTracerouteHops(output string) []tracerouteHop {
+	hops := []tracerouteHop{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		hopNumber, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		hop := tracerouteHop{Hop: hopNumber, RTTs: []float64{}}
+		var names []string
+		rest := fields[1:]
+		for i := 0; i < len(rest); i++ {
+			field := rest[i]
+			if field == "*" {
+				continue
+			}
+			if m := tracerouteRTTRe.FindStringSubmatch(field); m != nil && i+1 < len(rest) && rest[i+1] == "ms" {
+				if value, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", "."), 64); err == nil {
+					hop.RTTs = append(hop.RTTs, value)
+				}
+				i++
+				continue
+			}
+			if strings.HasSuffix(field, "ms") {
+				if m := tracerouteRTTRe.FindStringSubmatch(strings.TrimSuffix(field, "ms")); m != nil {
+					if value, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", "."), 64); err == nil {
+						hop.RTTs = append(hop.RTTs, value)
+					}
+					continue
+				}
+			}
+			if strings.HasPrefix(field, "!") {
+				continue
+			}
+			names = append(names, field)
+		}
+		for _, name := range names {
+			trimmed := strings.Trim(name, "()[]")
+			if net.ParseIP(trimmed) != nil {
+				hop.Address = trimmed
+				continue
+			}
+			if hop.Host == "" {
+				hop.Host = name
+			}
+		}
+		if hop.Host == "" {
+			hop.Host = hop.Address
+		}
+		hop.Timeout = len(hop.RTTs) == 0
+		if hop.Timeout {
+			hop.Host = ""
+			hop.Address = ""
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
@@ -1,7 +1,31 @@
 package tests
 
-import "strings"
+import (
+	"math"
+	"sort"
+	"strings"
+)
 
 func joinList(items []string) string {
 	return strings.Join(items, ",")
 }
+
+// percentile returns the p-th percentile (0-100) of values using linear
+// interpolation between closest ranks. values is not modified.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,505 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"conncheck/internal/config"
+	"conncheck/internal/model"
+	"conncheck/internal/sys"
+)
+
+// Signal levels, in dBm, below which the Wi-Fi link is reported: -67 is the
+// usual floor for voice and video, under -80 the link drops to the lowest
+// rates and disconnects.
+const (
+	wifiSignalWeakDbm = -67
+	wifiSignalPoorDbm = -80
+)
+
+// wifiSNRWarnDb is the signal-to-noise ratio under which even a strong
+// signal cannot use the higher modulations.
+const wifiSNRWarnDb = 20
+
+// wifiLowRateMbps is the PHY rate under which the station is using the
+// slowest modulations, because of distance or interference.
+const wifiLowRateMbps = 54
+
+// wifiThroughputRatio is the share of the PHY rate a single client usually
+// achieves as throughput, after contention and protocol overhead.
+const wifiThroughputRatio = 0.6
+
+var (
+	iwConnectedRe = regexp.MustCompile(`(?m)^Connected to ([0-9a-fA-F:]{17})`)
+	iwSSIDRe      = regexp.MustCompile(`(?m)^\s*SSID:\s*(.*?)\s*$`)
+	iwFreqRe      = regexp.MustCompile(`(?m)^\s*freq:\s*(\d+)`)
+	iwSignalRe    = regexp.MustCompile(`(?m)^\s*signal:\s*(-?\d+)`)
+	iwRxRateRe    = regexp.MustCompile(`(?m)^\s*rx bitrate:\s*([\d.]+) MBit/s(.*)$`)
+	iwTxRateRe    = regexp.MustCompile(`(?m)^\s*tx bitrate:\s*([\d.]+) MBit/s(.*)$`)
+	iwRateWidthRe = regexp.MustCompile(`\b(\d+)MHz\b`)
+	iwChannelRe   = regexp.MustCompile(`(?m)^\s*channel (\d+) \((\d+)(?:\.\d+)? MHz\)(?:, width: (\d+) MHz)?(?:[^,\n]*, center1: (\d+) MHz)?`)
+	iwNoiseRe     = regexp.MustCompile(`(?m)^\s*noise:\s*(-?\d+) dBm`)
+	wifiBandRe    = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)\s*GHz`)
+)
+
+// wifiLink is the state of the Wi-Fi connection. Zero means unknown for
+// the numeric fields: signal and noise levels are always negative.
+type wifiLink struct {
+	Interface  string
+	SSID       string
+	BSSID      string
+	FreqMHz    int
+	Band       string
+	Channel    int
+	WidthMHz   int
+	CenterMHz  int
+	SignalDbm  int
+	QualityPct int
+	NoiseDbm   int
+	RxRateMbps float64
+	TxRateMbps float64
+	RadioType  string
+}
+
+type WiFi struct {
+	outDir string
+	cfg    config.Config
+}
+
+func NewWiFi(outDir string, cfg config.Config) *WiFi {
+	return &WiFi{outDir: outDir, cfg: cfg}
+}
+
+func (w *WiFi) Name() string {
+	return "wifi"
+}
+
+func (w *WiFi) Run(ctx context.Context) model.TestResult {
+	result := baseResult(w.Name())
+	result.StartedAt = time.Now()
+	result.Status = StatusSkipped
+
+	var link *wifiLink
+	switch runtime.GOOS {
+	case "linux":
+		link = w.readLinux(ctx, &result)
+	case "windows":
+		link = w.readWindows(ctx, &result)
+	default:
+		result.Findings = append(result.Findings, newFinding("INFO", "wifi.unavailable", runtime.GOOS))
+	}
+	if link == nil {
+		result.EndedAt = time.Now()
+		return result
+	}
+
+	result.Status = StatusOK
+	w.recordMetrics(&result, link)
+	w.assess(&result, link)
+	if w.cfg.WiFi.Scan {
+		w.scan(ctx, &result, link)
+	}
+	result.EndedAt = time.Now()
+	return result
+}
+
+// run executes a command, logging it to raw_logs unless the privacy mode is
+// minimal: the outputs name the network and the access point.
+func (w *WiFi) run(ctx context.Context, result *model.TestResult, label, name string, args ...string) (string, error) {
+	if w.cfg.Privacy == "minimal" {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		return sys.RunCommandNoLog(ctx, name, args...)
+	}
+	output, logPath, err := sys.RunCommand(w.outDir, name, args...)
+	if logPath != "" {
+		result.Evidence = append(result.Evidence, model.Evidence{Label: label, Path: logPath})
+	}
+	return output, err
+}
+
+// readLinux reads the wireless interface carrying the default route with
+// iw, or /proc/net/wireless when iw is not installed.
+func (w *WiFi) readLinux(ctx context.Context, result *model.TestResult) *wifiLink {
+	wireless, _ := filepath.Glob("/sys/class/net/*/wireless")
+	if len(wireless) == 0 {
+		result.Findings = append(result.Findings, newFinding("INFO", "wifi.no_adapter"))
+		return nil
+	}
+	_, iface := localAddressFor("1.1.1.1")
+	if _, err := os.Stat(filepath.Join("/sys/class/net", iface, "wireless")); iface == "" || err != nil {
+		result.Findings = append(result.Findings, newFinding("INFO", "wifi.not_active", filepath.Base(filepath.Dir(wireless[0])), iface))
+		return nil
+	}
+
+	if _, err := exec.LookPath("iw"); err != nil {
+		data, err := os.ReadFile("/proc/net/wireless")
+		if err != nil {
+			result.Findings = append(result.Findings, newFinding("INFO", "wifi.unavailable", "iw, /proc/net/wireless"))
+			return nil
+		}
+		link := parseProcWireless(string(data), iface)
+		if link == nil {
+			result.Findings = append(result.Findings, newFinding("INFO", "wifi.not_connected", iface))
+			return nil
+		}
+		result.Metrics["source"] = "proc"
+		return link
+	}
+
+	output, err := w.run(ctx, result, "iw_link", "iw", "dev", iface, "link")
+	link := parseIWLink(output)
+	if err != nil || link == nil {
+		result.Findings = append(result.Findings, newFinding("INFO", "wifi.not_connected", iface))
+		return nil
+	}
+	link.Interface = iface
+	result.Metrics["source"] = "iw"
+	if output, err := w.run(ctx, result, "iw_info", "iw", "dev", iface, "info"); err == nil {
+		parseIWInfo(output, link)
+	}
+	if output, err := w.run(ctx, result, "iw_survey", "iw", "dev", iface, "survey", "dump"); err == nil {
+		link.NoiseDbm = parseIWSurveyNoise(output)
+	}
+	if link.NoiseDbm == 0 {
+		if data, err := os.ReadFile("/proc/net/wireless"); err == nil {
+			if proc := parseProcWireless(string(data), iface); proc != nil {
+				link.NoiseDbm = proc.NoiseDbm
+			}
+		}
+	}
+	return link
+}
+
+// readWindows parses `netsh wlan show interfaces`, preferring the interface
+// carrying the default route when several are connected.
+func (w *WiFi) readWindows(ctx context.Context, result *model.TestResult) *wifiLink {
+	output, err := w.run(ctx, result, "netsh_wlan", "netsh", "wlan", "show", "interfaces")
+	if err != nil {
+		// The WLAN AutoConfig service is not running when there is no
+		// wireless adapter.
+		result.Findings = append(result.Findings, newFinding("INFO", "wifi.no_adapter"))
+		return nil
+	}
+	links := parseNetshInterfaces(output)
+	if len(links) == 0 {
+		result.Findings = append(result.Findings, newFinding("INFO", "wifi.not_connected", "netsh"))
+		return nil
+	}
+	result.Metrics["source"] = "netsh"
+	_, iface := localAddressFor("1.1.1.1")
+	for i := range links {
+		if links[i].Interface == iface {
+			return &links[i]
+		}
+	}
+	return &links[0]
+}
+
+func (w *WiFi) recordMetrics(result *model.TestResult, link *wifiLink) {
+	result.Metrics["interface"] = link.Interface
+	if w.cfg.Privacy != "minimal" {
+		if link.SSID != "" {
+			result.Metrics["ssid"] = link.SSID
+		}
+		if link.BSSID != "" {
+			result.Metrics["bssid"] = link.BSSID
+		}
+	}
+	if link.Band != "" {
+		result.Metrics["band_ghz"] = link.Band
+	}
+	if link.RadioType != "" {
+		result.Metrics["radio_type"] = link.RadioType
+	}
+	for key, value := range map[string]int{
+		"frequency_mhz":      link.FreqMHz,
+		"channel":            link.Channel,
+		"width_mhz":          link.WidthMHz,
+		"signal_dbm":         link.SignalDbm,
+		"signal_quality_pct": link.QualityPct,
+		"noise_dbm":          link.NoiseDbm,
+	} {
+		if value != 0 {
+			result.Metrics[key] = strconv.Itoa(value)
+		}
+	}
+	if link.SignalDbm != 0 && link.NoiseDbm != 0 {
+		result.Metrics["snr_db"] = strconv.Itoa(link.SignalDbm - link.NoiseDbm)
+	}
+	if link.RxRateMbps > 0 {
+		result.Metrics["rx_rate_mbps"] = fmt.Sprintf("%.1f", link.RxRateMbps)
+	}
+	if link.TxRateMbps > 0 {
+		result.Metrics["tx_rate_mbps"] = fmt.Sprintf("%.1f", link.TxRateMbps)
+	}
+}
+
+// assess turns the link state into findings: signal strength (worse on the
+// crowded 2.4 GHz band), signal-to-noise ratio, PHY rate, and a PHY rate too
+// low to deliver the plan.
+func (w *WiFi) assess(result *model.TestResult, link *wifiLink) {
+	switch {
+	case link.SignalDbm == 0:
+	case link.SignalDbm < wifiSignalPoorDbm:
+		result.Status = worseStatus(result.Status, StatusFail)
+		result.Findings = append(result.Findings, newFinding("FAIL", "wifi.signal_poor", link.SignalDbm))
+	case link.SignalDbm < wifiSignalWeakDbm && link.Band == "2.4":
+		result.Status = worseStatus(result.Status, StatusWarn)
+		result.Findings = append(result.Findings, newFinding("WARN", "wifi.signal_weak_24", link.SignalDbm, link.Channel))
+	case link.SignalDbm < wifiSignalWeakDbm:
+		result.Status = worseStatus(result.Status, StatusWarn)
+		band := link.Band
+		if band == "" {
+			band = "?"
+		}
+		result.Findings = append(result.Findings, newFinding("WARN", "wifi.signal_weak", link.SignalDbm, band))
+	case link.Band == "2.4":
+		result.Findings = append(result.Findings, newFinding("INFO", "wifi.band_24", link.Channel))
+	}
+	if link.SignalDbm != 0 && link.NoiseDbm != 0 && link.SignalDbm-link.NoiseDbm < wifiSNRWarnDb {
+		result.Status = worseStatus(result.Status, StatusWarn)
+		result.Findings = append(result.Findings, newFinding("WARN", "wifi.low_snr", link.SignalDbm-link.NoiseDbm, link.SignalDbm, link.NoiseDbm))
+	}
+	if link.TxRateMbps > 0 && link.TxRateMbps < wifiLowRateMbps {
+		result.Status = worseStatus(result.Status, StatusWarn)
+		result.Findings = append(result.Findings, newFinding("WARN", "wifi.low_rate", link.TxRateMbps, link.RxRateMbps))
+	}
+	if plan := w.cfg.Plan.DownloadMbps; plan > 0 && link.RxRateMbps > 0 && link.RxRateMbps*wifiThroughputRatio < plan {
+		result.Status = worseStatus(result.Status, StatusWarn)
+		result.Findings = append(result.Findings, newFinding("WARN", "wifi.rate_below_plan", link.RxRateMbps, link.RxRateMbps*wifiThroughputRatio, plan))
+	}
+}
+
+// parseIWLink parses `iw dev <iface> link`. It returns nil when the
+// interface is not associated ("Not connected.").
+func parseIWLink(output string) *wifiLink {
+	m := iwConnectedRe.FindStringSubmatch(output)
+	if len(m) != 2 {
+		return nil
+	}
+	link := &wifiLink{BSSID: normalizeMAC(m[1])}
+	if m := iwSSIDRe.FindStringSubmatch(output); len(m) == 2 {
+		link.SSID = m[1]
+	}
+	if m := iwFreqRe.FindStringSubmatch(output); len(m) == 2 {
+		link.FreqMHz, _ = strconv.Atoi(m[1])
+		link.Band, link.Channel = wifiChannel(link.FreqMHz)
+	}
+	if m := iwSignalRe.FindStringSubmatch(output); len(m) == 2 {
+		link.SignalDbm, _ = strconv.Atoi(m[1])
+	}
+	if m := iwRxRateRe.FindStringSubmatch(output); len(m) == 3 {
+		link.RxRateMbps, _ = strconv.ParseFloat(m[1], 64)
+		link.RadioType = iwRadioType(m[2])
+		if width := iwRateWidthRe.FindStringSubmatch(m[2]); len(width) == 2 {
+			link.WidthMHz, _ = strconv.Atoi(width[1])
+		}
+	}
+	if m := iwTxRateRe.FindStringSubmatch(output); len(m) == 3 {
+		link.TxRateMbps, _ = strconv.ParseFloat(m[1], 64)
+		if link.RadioType == "" {
+			link.RadioType = iwRadioType(m[2])
+		}
+	}
+	return link
+}
+
+// iwRadioType names the standard from the MCS flavour iw prints after a
+// bitrate, e.g. "VHT-MCS 9 80MHz short GI VHT-NSS 2".
+func iwRadioType(rate string) string {
+	switch {
+	case strings.Contains(rate, "EHT-MCS"):
+		return "802.11be"
+	case strings.Contains(rate, "HE-MCS"):
+		return "802.11ax"
+	case strings.Contains(rate, "VHT-MCS"):
+		return "802.11ac"
+	case strings.Contains(rate, "MCS"):
+		return "802.11n"
+	}
+	return ""
+}
+
+// parseIWInfo adds the channel, width and centre frequency from
+// `iw dev <iface> info`, e.g.
+// "channel 36 (5180 MHz), width: 80 MHz, center1: 5210 MHz".
+func parseIWInfo(output string, link *wifiLink) {
+	m := iwChannelRe.FindStringSubmatch(output)
+	if len(m) != 5 {
+		return
+	}
+	link.Channel, _ = strconv.Atoi(m[1])
+	if link.FreqMHz == 0 {
+		link.FreqMHz, _ = strconv.Atoi(m[2])
+		link.Band, _ = wifiChannel(link.FreqMHz)
+	}
+	if m[3] != "" {
+		link.WidthMHz, _ = strconv.Atoi(m[3])
+	}
+	if m[4] != "" {
+		link.CenterMHz, _ = strconv.Atoi(m[4])
+	}
+}
+
+// parseIWSurveyNoise returns the noise floor of the channel marked
+// "[in use]" in `iw dev <iface> survey dump`, or 0.
+func parseIWSurveyNoise(output string) int {
+	for _, block := range strings.Split(output, "Survey data from") {
+		if !strings.Contains(block, "[in use]") {
+			continue
+		}
+		if m := iwNoiseRe.FindStringSubmatch(block); len(m) == 2 {
+			noise, _ := strconv.Atoi(m[1])
+			return noise
+		}
+	}
+	return 0
+}
+
+// parseProcWireless reads the signal and noise levels of iface from
+// /proc/net/wireless. Drivers without dBm levels report them unsigned, with
+// 256 added; -256 means the driver has no value.
+func parseProcWireless(output, iface string) *wifiLink {
+	for _, line := range strings.Split(output, "\n") {
+		name, values, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(name) != iface {
+			continue
+		}
+		fields := strings.Fields(values)
+		if len(fields) < 4 {
+			return nil
+		}
+		level := func(field string) int {
+			value, err := strconv.ParseFloat(strings.TrimSuffix(field, "."), 64)
+			if err != nil || value == -256 || value == 0 {
+				return 0
+			}
+			if value > 0 {
+				value -= 256
+			}
+			return int(value)
+		}
+		link := &wifiLink{Interface: iface, SignalDbm: level(fields[2]), NoiseDbm: level(fields[3])}
+		if link.SignalDbm == 0 {
+			// Listed but without a level: not associated.
+			return nil
+		}
+		return link
+	}
+	return nil
+}
+
+// netsh labels are localized; these are the English and Italian ones for the
+// fields that are not recognised by their value.
+var (
+	netshNameKeys    = []string{"name", "nome"}
+	netshChannelKeys = []string{"channel", "canale"}
+	netshBandKeys    = []string{"band", "banda"}
+	netshRadioKeys   = []string{"radio type", "tipo frequenza radio", "tipo radio"}
+	netshRxKeys      = []string{"receive rate (mbps)", "velocità ricezione (mbps)"}
+	netshTxKeys      = []string{"transmit rate (mbps)", "velocità trasmissione (mbps)"}
+	netshSignalKeys  = []string{"signal", "segnale"}
+)
+
+// parseNetshInterfaces parses `netsh wlan show interfaces` and returns the
+// connected interfaces, those listing a BSSID.
+func parseNetshInterfaces(output string) []wifiLink {
+	var links []wifiLink
+	var current *wifiLink
+	var rates []float64
+	flush := func() {
+		if current != nil && current.BSSID != "" {
+			// Unrecognised labels: receive is listed before transmit.
+			if current.RxRateMbps == 0 && current.TxRateMbps == 0 && len(rates) == 2 {
+				current.RxRateMbps, current.TxRateMbps = rates[0], rates[1]
+			}
+			if current.Band == "" && current.Channel > 0 {
+				current.Band = "5"
+				if current.Channel <= 14 {
+					current.Band = "2.4"
+				}
+			}
+			links = append(links, *current)
+		}
+		current, rates = nil, nil
+	}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if current == nil {
+			current = &wifiLink{}
+		}
+		switch {
+		case slices.Contains(netshNameKeys, key):
+			current.Interface = value
+		case key == "ssid":
+			current.SSID = value
+		case key == "bssid" || key == "ap bssid":
+			current.BSSID = normalizeMAC(value)
+		case slices.Contains(netshChannelKeys, key):
+			current.Channel, _ = strconv.Atoi(value)
+		case slices.Contains(netshBandKeys, key):
+			if m := wifiBandRe.FindStringSubmatch(value); len(m) == 2 {
+				current.Band = strings.ReplaceAll(m[1], ",", ".")
+			}
+		case slices.Contains(netshRadioKeys, key):
+			current.RadioType = value
+		case slices.Contains(netshRxKeys, key):
+			current.RxRateMbps, _ = strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+		case slices.Contains(netshTxKeys, key):
+			current.TxRateMbps, _ = strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+		case key == "rssi":
+			current.SignalDbm, _ = strconv.Atoi(value)
+		case slices.Contains(netshSignalKeys, key) || strings.HasSuffix(value, "%"):
+			if quality, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(value, "%"))); err == nil {
+				current.QualityPct = quality
+			}
+		case strings.HasSuffix(key, "(mbps)"):
+			if rate, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64); err == nil {
+				rates = append(rates, rate)
+			}
+		}
+	}
+	flush()
+	for i := range links {
+		if links[i].SignalDbm == 0 && links[i].QualityPct > 0 {
+			// Windows maps -100 dBm to 0% and -50 dBm to 100% linearly.
+			links[i].SignalDbm = min(links[i].QualityPct/2-100, -50)
+		}
+	}
+	return links
+}
+
+// wifiChannel returns the band ("2.4", "5" or "6" GHz) and channel number of
+// a centre frequency in MHz.
+func wifiChannel(freq int) (string, int) {
+	switch {
+	case freq == 2484:
+		return "2.4", 14
+	case freq >= 2412 && freq < 2484:
+		return "2.4", (freq - 2407) / 5
+	case freq >= 5150 && freq <= 5895:
+		return "5", (freq - 5000) / 5
+	case freq >= 5955 && freq <= 7115:
+		return "6", (freq - 5950) / 5
+	}
+	return "", 0
+}
@@ -0,0 +1,353 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIWLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   *wifiLink
+	}{
+		{
+			name: "VHT on 5 GHz",
+			output: "Connected to 3c:a6:2f:11:22:33 (on wlp2s0)\n" +
+				"\tSSID: HomeNet 5G\n" +
+				"\tfreq: 5180\n" +
+				"\tRX: 1234567 bytes (8901 packets)\n" +
+				"\tTX: 234567 bytes (1234 packets)\n" +
+				"\tsignal: -58 dBm\n" +
+				"\trx bitrate: 866.7 MBit/s VHT-MCS 9 80MHz short GI VHT-NSS 2\n" +
+				"\ttx bitrate: 780.0 MBit/s VHT-MCS 8 80MHz short GI VHT-NSS 2\n" +
+				"\n" +
+				"\tbss flags:\tshort-slot-time\n" +
+				"\tdtim period:\t1\n" +
+				"\tbeacon int:\t100\n",
+			want: &wifiLink{
+				SSID: "HomeNet 5G", BSSID: "3c:a6:2f:11:22:33", FreqMHz: 5180, Band: "5", Channel: 36,
+				WidthMHz: 80, SignalDbm: -58, RxRateMbps: 866.7, TxRateMbps: 780, RadioType: "802.11ac",
+			},
+		},
+		{
+			name: "HE on 2.4 GHz with a decimal frequency",
+			output: "Connected to 3C:A6:2F:11:22:44 (on wlan0)\n" +
+				"\tSSID: HomeNet\n" +
+				"\tfreq: 2437.0\n" +
+				"\tsignal: -71 dBm\n" +
+				"\trx bitrate: 286.7 MBit/s 40MHz HE-MCS 11 HE-NSS 2 HE-GI 0 HE-DCM 0\n" +
+				"\ttx bitrate: 72.2 MBit/s MCS 7 short GI\n",
+			want: &wifiLink{
+				SSID: "HomeNet", BSSID: "3c:a6:2f:11:22:44", FreqMHz: 2437, Band: "2.4", Channel: 6,
+				WidthMHz: 40, SignalDbm: -71, RxRateMbps: 286.7, TxRateMbps: 72.2, RadioType: "802.11ax",
+			},
+		},
+		{
+			name: "legacy receive rate takes the standard from transmit",
+			output: "Connected to 3c:a6:2f:11:22:55 (on wlan0)\n" +
+				"\tSSID: Guest\n" +
+				"\tfreq: 2412\n" +
+				"\tsignal: -82 dBm\n" +
+				"\trx bitrate: 6.0 MBit/s\n" +
+				"\ttx bitrate: 65.0 MBit/s MCS 7\n",
+			want: &wifiLink{
+				SSID: "Guest", BSSID: "3c:a6:2f:11:22:55", FreqMHz: 2412, Band: "2.4", Channel: 1,
+				SignalDbm: -82, RxRateMbps: 6, TxRateMbps: 65, RadioType: "802.11n",
+			},
+		},
+		{
+			name:   "not connected",
+			output: "Not connected.\n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseIWLink(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseIWLink =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIWInfo(t *testing.T) {
+	const header = "Interface wlp2s0\n" +
+		"\tifindex 3\n" +
+		"\twdev 0x1\n" +
+		"\taddr 3c:a6:2f:aa:bb:cc\n" +
+		"\tssid HomeNet 5G\n" +
+		"\ttype managed\n" +
+		"\twiphy 0\n"
+	tests := []struct {
+		name   string
+		output string
+		link   wifiLink
+		want   wifiLink
+	}{
+		{
+			name:   "width and centre frequency",
+			output: header + "\tchannel 36 (5180 MHz), width: 80 MHz, center1: 5210 MHz\n\ttxpower 22.00 dBm\n",
+			link:   wifiLink{FreqMHz: 5180, Band: "5", Channel: 36},
+			want:   wifiLink{FreqMHz: 5180, Band: "5", Channel: 36, WidthMHz: 80, CenterMHz: 5210},
+		},
+		{
+			name:   "6 GHz fills the frequency when link had none",
+			output: header + "\tchannel 37 (6135 MHz), width: 160 MHz, center1: 6185 MHz\n",
+			want:   wifiLink{FreqMHz: 6135, Band: "6", Channel: 37, WidthMHz: 160, CenterMHz: 6185},
+		},
+		{
+			name:   "no HT",
+			output: header + "\tchannel 1 (2412 MHz), width: 20 MHz (no HT), center1: 2412 MHz\n",
+			want:   wifiLink{FreqMHz: 2412, Band: "2.4", Channel: 1, WidthMHz: 20, CenterMHz: 2412},
+		},
+		{
+			name:   "channel without width",
+			output: header + "\tchannel 11 (2462 MHz)\n",
+			want:   wifiLink{FreqMHz: 2462, Band: "2.4", Channel: 11},
+		},
+		{
+			name:   "no channel line leaves the link alone",
+			output: header,
+			link:   wifiLink{FreqMHz: 5180, Band: "5", Channel: 36, WidthMHz: 80},
+			want:   wifiLink{FreqMHz: 5180, Band: "5", Channel: 36, WidthMHz: 80},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := tt.link
+			parseIWInfo(tt.output, &link)
+			if link != tt.want {
+				t.Errorf("parseIWInfo =\n%+v\nwant\n%+v", link, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIWSurveyNoise(t *testing.T) {
+	const dump = "Survey data from wlp2s0\n" +
+		"\tfrequency:\t\t\t5170 MHz\n" +
+		"Survey data from wlp2s0\n" +
+		"\tfrequency:\t\t\t5180 MHz [in use]\n" +
+		"\tnoise:\t\t\t\t-92 dBm\n" +
+		"\tchannel active time:\t\t1234 ms\n" +
+		"\tchannel busy time:\t\t345 ms\n" +
+		"\tchannel receive time:\t\t300 ms\n" +
+		"\tchannel transmit time:\t\t20 ms\n" +
+		"Survey data from wlp2s0\n" +
+		"\tfrequency:\t\t\t5200 MHz\n" +
+		"\tnoise:\t\t\t\t-95 dBm\n"
+	tests := []struct {
+		name   string
+		output string
+		want   int
+	}{
+		{name: "channel in use", output: dump, want: -92},
+		{name: "no channel in use", output: strings.ReplaceAll(dump, " [in use]", ""), want: 0},
+		{name: "in use without noise", output: "Survey data from wlan0\n\tfrequency:\t\t\t2437 MHz [in use]\n", want: 0},
+		{name: "empty", output: "", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseIWSurveyNoise(tt.output); got != tt.want {
+				t.Errorf("parseIWSurveyNoise = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProcWireless(t *testing.T) {
+	const header = "Inter-| sta-|   Quality        |   Discarded packets               | Missed | WE\n" +
+		" face | tus | link level noise |  nwid  crypt   frag  retry   misc | beacon | 22\n"
+	tests := []struct {
+		name   string
+		output string
+		iface  string
+		want   *wifiLink
+	}{
+		{
+			name:   "dBm levels without noise",
+			output: header + "wlp2s0: 0000   52.  -58.  -256        0      0      0      0     12        0\n",
+			iface:  "wlp2s0",
+			want:   &wifiLink{Interface: "wlp2s0", SignalDbm: -58},
+		},
+		{
+			name:   "unsigned levels with 256 added",
+			output: header + " wlan0: 0000   70.  198.  161.        0      0      0      0      0        0\n",
+			iface:  "wlan0",
+			want:   &wifiLink{Interface: "wlan0", SignalDbm: -58, NoiseDbm: -95},
+		},
+		{
+			name:   "listed but not associated",
+			output: header + " wlan0: 0000    0.    0.  -256        0      0      0      0      0        0\n",
+			iface:  "wlan0",
+			want:   nil,
+		},
+		{
+			name:   "other interface",
+			output: header + "wlp2s0: 0000   52.  -58.  -256        0      0      0      0     12        0\n",
+			iface:  "wlan0",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseProcWireless(tt.output, tt.iface); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProcWireless =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNetshInterfaces(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []wifiLink
+	}{
+		{
+			name: "English, one of two connected",
+			output: "\r\nThere are 2 interfaces on the system:\r\n\r\n" +
+				"    Name                   : Wi-Fi\r\n" +
+				"    Description            : Intel(R) Wi-Fi 6 AX201 160MHz\r\n" +
+				"    GUID                   : 5e7b3c1a-0d1f-4a51-9d77-0c2b1d8f9a10\r\n" +
+				"    Physical address       : 3c:a6:2f:aa:bb:cc\r\n" +
+				"    State                  : connected\r\n" +
+				"    SSID                   : HomeNet 5G\r\n" +
+				"    BSSID                  : 3c:a6:2f:11:22:33\r\n" +
+				"    Network type           : Infrastructure\r\n" +
+				"    Radio type             : 802.11ax\r\n" +
+				"    Authentication         : WPA2-Personal\r\n" +
+				"    Cipher                 : CCMP\r\n" +
+				"    Connection mode        : Auto Connect\r\n" +
+				"    Channel                : 36\r\n" +
+				"    Receive rate (Mbps)    : 1201\r\n" +
+				"    Transmit rate (Mbps)   : 960.7\r\n" +
+				"    Signal                 : 84%\r\n" +
+				"    Profile                : HomeNet 5G\r\n" +
+				"\r\n" +
+				"    Name                   : Wi-Fi 2\r\n" +
+				"    Description            : TP-Link Wireless USB Adapter\r\n" +
+				"    State                  : disconnected\r\n" +
+				"    Radio status           : Hardware On\r\n" +
+				"                             Software On\r\n" +
+				"\r\n" +
+				"    Hosted network status  : Not available\r\n",
+			want: []wifiLink{{
+				Interface: "Wi-Fi", SSID: "HomeNet 5G", BSSID: "3c:a6:2f:11:22:33", Band: "5", Channel: 36,
+				SignalDbm: -58, QualityPct: 84, RxRateMbps: 1201, TxRateMbps: 960.7, RadioType: "802.11ax",
+			}},
+		},
+		{
+			name: "Italian with decimal commas",
+			output: "\r\nNel sistema è presente 1 interfaccia:\r\n\r\n" +
+				"    Nome                   : Wi-Fi\r\n" +
+				"    Descrizione            : Realtek RTL8822CE 802.11ac PCIe Adapter\r\n" +
+				"    Indirizzo fisico       : 3c:a6:2f:aa:bb:cc\r\n" +
+				"    Stato                  : connessa\r\n" +
+				"    SSID                   : Casa\r\n" +
+				"    BSSID                  : 3c:a6:2f:11:22:44\r\n" +
+				"    Tipo di rete           : Infrastruttura\r\n" +
+				"    Tipo frequenza radio   : 802.11n\r\n" +
+				"    Autenticazione         : WPA2-Personal\r\n" +
+				"    Crittografia           : CCMP\r\n" +
+				"    Modalità connessione   : Connessione automatica\r\n" +
+				"    Canale                 : 6\r\n" +
+				"    Velocità ricezione (Mbps)  : 144,4\r\n" +
+				"    Velocità trasmissione (Mbps) : 130\r\n" +
+				"    Segnale                : 62%\r\n" +
+				"    Profilo                : Casa\r\n",
+			want: []wifiLink{{
+				Interface: "Wi-Fi", SSID: "Casa", BSSID: "3c:a6:2f:11:22:44", Band: "2.4", Channel: 6,
+				SignalDbm: -69, QualityPct: 62, RxRateMbps: 144.4, TxRateMbps: 130, RadioType: "802.11n",
+			}},
+		},
+		{
+			name: "Windows 11 with AP BSSID, band and RSSI",
+			output: "    Name                   : Wi-Fi\r\n" +
+				"    Description            : Intel(R) Wi-Fi 6E AX211 160MHz\r\n" +
+				"    Interface type         : Primary\r\n" +
+				"    State                  : connected\r\n" +
+				"    SSID                   : HomeNet 6G\r\n" +
+				"    AP BSSID               : 3c:a6:2f:11:22:55\r\n" +
+				"    Band                   : 6 GHz\r\n" +
+				"    Channel                : 37\r\n" +
+				"    Network type           : Infrastructure\r\n" +
+				"    Radio type             : 802.11ax\r\n" +
+				"    Authentication         : WPA3-Personal\r\n" +
+				"    Cipher                 : GCMP-256\r\n" +
+				"    Connection mode        : Auto Connect\r\n" +
+				"    Receive rate (Mbps)    : 2401.9\r\n" +
+				"    Transmit rate (Mbps)   : 2401.9\r\n" +
+				"    Signal                 : 88%\r\n" +
+				"    Rssi                   : -54\r\n" +
+				"    Profile                : HomeNet 6G\r\n" +
+				"    QoS MSCS Configured         : 0\r\n" +
+				"    QoS Map Configured          : 0\r\n",
+			want: []wifiLink{{
+				Interface: "Wi-Fi", SSID: "HomeNet 6G", BSSID: "3c:a6:2f:11:22:55", Band: "6", Channel: 37,
+				SignalDbm: -54, QualityPct: 88, RxRateMbps: 2401.9, TxRateMbps: 2401.9, RadioType: "802.11ax",
+			}},
+		},
+		{
+			name: "unrecognised labels",
+			output: "    Nombre                 : Wi-Fi\r\n" +
+				"    BSSID                  : 3c:a6:2f:11:22:66\r\n" +
+				"    Velocidad de recepción (Mbps)  : 300\r\n" +
+				"    Velocidad de transmisión (Mbps) : 270\r\n" +
+				"    Señal                  : 90%\r\n",
+			want: []wifiLink{{
+				BSSID: "3c:a6:2f:11:22:66", SignalDbm: -55, QualityPct: 90, RxRateMbps: 300, TxRateMbps: 270,
+			}},
+		},
+		{
+			name: "not connected",
+			output: "\r\nThere is 1 interface on the system:\r\n\r\n" +
+				"    Name                   : Wi-Fi\r\n" +
+				"    State                  : disconnected\r\n" +
+				"    Radio status           : Hardware On\r\n" +
+				"                             Software Off\r\n",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNetshInterfaces(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNetshInterfaces =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWiFiChannel(t *testing.T) {
+	tests := []struct {
+		freq        int
+		wantBand    string
+		wantChannel int
+	}{
+		{freq: 2411},
+		{freq: 2412, wantBand: "2.4", wantChannel: 1},
+		{freq: 2472, wantBand: "2.4", wantChannel: 13},
+		{freq: 2484, wantBand: "2.4", wantChannel: 14},
+		{freq: 2485},
+		{freq: 5149},
+		{freq: 5160, wantBand: "5", wantChannel: 32},
+		{freq: 5180, wantBand: "5", wantChannel: 36},
+		{freq: 5825, wantBand: "5", wantChannel: 165},
+		{freq: 5895, wantBand: "5", wantChannel: 179},
+		{freq: 5896},
+		{freq: 5950},
+		{freq: 5955, wantBand: "6", wantChannel: 1},
+		{freq: 6135, wantBand: "6", wantChannel: 37},
+		{freq: 7115, wantBand: "6", wantChannel: 233},
+		{freq: 7120},
+		{freq: 0},
+	}
+	for _, tt := range tests {
+		band, channel := wifiChannel(tt.freq)
+		if band != tt.wantBand || channel != tt.wantChannel {
+			t.Errorf("wifiChannel(%d) = %q, %d; want %q, %d", tt.freq, band, channel, tt.wantBand, tt.wantChannel)
+		}
+	}
+}
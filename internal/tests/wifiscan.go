@@ -0,0 +1,456 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"conncheck/internal/model"
+)
+
+// Congestion score levels, out of 100: each overlapping network adds up to
+// wifiStrongNetworkPoints depending on how loud it is.
+const (
+	wifiCongestionModerate = 30
+	wifiCongestionHigh     = 60
+)
+
+// Points an overlapping network adds to the congestion score: a strong
+// neighbour takes airtime from every transmission, a faint one mostly
+// raises the noise floor.
+const (
+	wifiStrongNetworkPoints = 10
+	wifiMediumNetworkPoints = 5
+	wifiFaintNetworkPoints  = 2
+)
+
+// wifiRecommendations caps the channels suggested instead of the current one.
+const wifiRecommendations = 3
+
+// Candidate primary channels: the three non-overlapping 2.4 GHz channels,
+// and the 5 GHz channels allowed in Europe and the US, DFS ones included.
+var (
+	wifiCandidates24 = []int{1, 6, 11}
+	wifiCandidates5  = []int{36, 40, 44, 48, 52, 56, 60, 64, 100, 104, 108, 112, 116, 120, 124, 128, 132, 136, 140, 144, 149, 153, 157, 161, 165}
+)
+
+var (
+	iwBSSRe          = regexp.MustCompile(`^BSS ([0-9a-fA-F:]{17})`)
+	iwScanFreqRe     = regexp.MustCompile(`^freq:\s*(\d+)`)
+	iwScanSignalRe   = regexp.MustCompile(`^signal:\s*(-?\d+)`)
+	iwPrimaryRe      = regexp.MustCompile(`primary channel:\s*(\d+)`)
+	iwSecondaryRe    = regexp.MustCompile(`secondary channel offset:\s*(above|below)`)
+	iwVHTWidthRe     = regexp.MustCompile(`^\* channel width:\s*(\d) \(`)
+	iwVHTCenterRe    = regexp.MustCompile(`^\* center freq segment 1:\s*(\d+)`)
+	netshScanBSSIDRe = regexp.MustCompile(`(?i)^bssid(?:\s+\d+)?\s*:\s*([0-9a-fA-F:-]{17})`)
+)
+
+// wifiNetwork is one access point found by a scan.
+type wifiNetwork struct {
+	BSSID     string
+	Band      string
+	Channel   int
+	WidthMHz  int
+	CenterMHz int
+	SignalDbm int
+}
+
+// span returns the frequency range the network occupies, in MHz.
+func (n wifiNetwork) span() (float64, float64) {
+	if n.CenterMHz > 0 && n.WidthMHz > 0 {
+		return float64(n.CenterMHz) - float64(n.WidthMHz)/2, float64(n.CenterMHz) + float64(n.WidthMHz)/2
+	}
+	return channelSpan(n.Band, n.Channel, n.WidthMHz)
+}
+
+// channelSpan is the frequency range of a primary channel at a width. A
+// 2.4 GHz channel is 22 MHz wide, so channels less than five apart overlap;
+// wider 5 GHz channels are the aligned blocks of 20 MHz channels.
+func channelSpan(band string, channel, width int) (float64, float64) {
+	center := float64(wifiFrequency(band, channel))
+	switch {
+	case band == "2.4" && width <= 20:
+		return center - 11, center + 11
+	case band == "2.4":
+		// The secondary channel is above unless that leaves the band.
+		if channel > 7 {
+			center -= 10
+		} else {
+			center += 10
+		}
+		return center - 20, center + 20
+	case width <= 20:
+		return center - 10, center + 10
+	}
+	start, blockChannels := channelBlock(band, channel, width)
+	center = float64(wifiFrequency(band, start+2*(blockChannels-1)))
+	return center - float64(width)/2, center + float64(width)/2
+}
+
+// channelBlock returns the first channel and the number of 20 MHz channels
+// of the aligned 5 or 6 GHz block holding channel at width.
+func channelBlock(band string, channel, width int) (int, int) {
+	base := 36
+	switch {
+	case band == "6":
+		base = 1
+	case channel >= 149:
+		base = 149
+	case channel >= 100:
+		base = 100
+	}
+	blockChannels := max(width/20, 1)
+	return base + 4*blockChannels*((channel-base)/(4*blockChannels)), blockChannels
+}
+
+// wifiFrequency is the centre frequency of a channel number, the inverse of
+// wifiChannel.
+func wifiFrequency(band string, channel int) int {
+	switch band {
+	case "2.4":
+		if channel == 14 {
+			return 2484
+		}
+		return 2407 + 5*channel
+	case "6":
+		return 5950 + 5*channel
+	}
+	return 5000 + 5*channel
+}
+
+// wifiChannelUsage is the scan seen from one channel: networks using it as
+// their primary channel, networks overlapping it and the resulting score.
+type wifiChannelUsage struct {
+	Channel     int `json:"channel"`
+	Networks    int `json:"networks"`
+	Overlapping int `json:"overlapping"`
+	Score       int `json:"score"`
+}
+
+// congestion counts the networks overlapping [lo, hi], ignoring our own
+// access point, and scores them by signal strength.
+func congestion(networks []wifiNetwork, band string, lo, hi float64, own string) (int, int) {
+	count, score := 0, 0
+	for _, network := range networks {
+		if network.Band != band || sameAccessPoint(network.BSSID, own) {
+			continue
+		}
+		netLo, netHi := network.span()
+		if netHi <= lo || netLo >= hi {
+			continue
+		}
+		count++
+		switch {
+		case network.SignalDbm >= wifiSignalWeakDbm:
+			score += wifiStrongNetworkPoints
+		case network.SignalDbm >= wifiSignalPoorDbm:
+			score += wifiMediumNetworkPoints
+		default:
+			score += wifiFaintNetworkPoints
+		}
+	}
+	return count, min(score, 100)
+}
+
+// scan lists the networks around, counts them per band and channel, scores
+// the congestion of the current channel and looks for quieter channels of
+// the same width.
+func (w *WiFi) scan(ctx context.Context, result *model.TestResult, link *wifiLink) {
+	var networks []wifiNetwork
+	switch runtime.GOOS {
+	case "linux":
+		// A fresh scan needs CAP_NET_ADMIN; the dump holds what the kernel
+		// saw during its own background scans.
+		output, err := w.run(ctx, result, "iw_scan", "iw", "dev", link.Interface, "scan")
+		networks = parseIWScan(output)
+		if err != nil || len(networks) == 0 {
+			output, _ = w.run(ctx, result, "iw_scan_dump", "iw", "dev", link.Interface, "scan", "dump")
+			networks = parseIWScan(output)
+		}
+	case "windows":
+		output, _ := w.run(ctx, result, "netsh_networks", "netsh", "wlan", "show", "networks", "mode=bssid", "interface="+link.Interface)
+		networks = parseNetshNetworks(output)
+	}
+	if len(networks) == 0 {
+		result.Findings = append(result.Findings, newFinding("INFO", "wifi.scan_empty"))
+		return
+	}
+
+	perBand := map[string]int{}
+	for _, network := range networks {
+		perBand[network.Band]++
+	}
+	result.Metrics["scan_networks"] = strconv.Itoa(len(networks))
+	for band, count := range perBand {
+		if band != "" {
+			result.Metrics["scan_networks_"+strings.ReplaceAll(band, ".", "")+"ghz"] = strconv.Itoa(count)
+		}
+	}
+	if link.Band == "" || link.Channel == 0 {
+		return
+	}
+
+	// Our own access point, as the scan saw it, knows its real width.
+	own := wifiNetwork{BSSID: link.BSSID, Band: link.Band, Channel: link.Channel, WidthMHz: link.WidthMHz, CenterMHz: link.CenterMHz}
+	for _, network := range networks {
+		if network.BSSID == link.BSSID && network.WidthMHz > 0 {
+			own = network
+		}
+	}
+	width := max(own.WidthMHz, 20)
+	lo, hi := own.span()
+	overlapping, score := congestion(networks, link.Band, lo, hi, link.BSSID)
+	result.Metrics["overlapping_networks"] = strconv.Itoa(overlapping)
+	result.Metrics["congestion_score"] = strconv.Itoa(score)
+
+	usage := wifiUsage(networks, link.Band, link.Channel, width, link.BSSID)
+	recommended := recommendChannels(usage, link.Band, link.Channel, width, score)
+	if usageJSON, err := json.Marshal(usage); err == nil {
+		result.Metrics["channel_usage"] = string(usageJSON)
+	}
+	channels := make([]string, 0, len(recommended))
+	for _, channel := range recommended {
+		channels = append(channels, strconv.Itoa(channel))
+	}
+	if len(channels) > 0 {
+		result.Metrics["recommended_channels"] = joinList(channels)
+	}
+
+	switch {
+	case score >= wifiCongestionHigh && len(channels) > 0:
+		result.Status = worseStatus(result.Status, StatusWarn)
+		result.Findings = append(result.Findings, newFinding("WARN", "wifi.channel_congested", link.Channel, overlapping, score, strings.Join(channels, ", ")))
+	case score >= wifiCongestionHigh:
+		result.Status = worseStatus(result.Status, StatusWarn)
+		result.Findings = append(result.Findings, newFinding("WARN", "wifi.band_congested", link.Channel, overlapping, score, link.Band))
+	case score >= wifiCongestionModerate && len(channels) > 0:
+		result.Findings = append(result.Findings, newFinding("INFO", "wifi.better_channel", link.Channel, overlapping, score, strings.Join(channels, ", ")))
+	}
+}
+
+// wifiUsage lists, for the candidate channels of band plus every channel
+// seen in the scan, the networks on it and the congestion a network of the
+// given width would face there.
+func wifiUsage(networks []wifiNetwork, band string, current, width int, own string) []wifiChannelUsage {
+	primary := map[int]int{}
+	for _, network := range networks {
+		if network.Band == band && !sameAccessPoint(network.BSSID, own) {
+			primary[network.Channel]++
+		}
+	}
+	channels := map[int]bool{current: true}
+	for channel := range primary {
+		channels[channel] = true
+	}
+	for _, channel := range wifiCandidates(band) {
+		channels[channel] = true
+	}
+	var usage []wifiChannelUsage
+	for channel := range channels {
+		lo, hi := channelSpan(band, channel, width)
+		overlapping, score := congestion(networks, band, lo, hi, own)
+		usage = append(usage, wifiChannelUsage{Channel: channel, Networks: primary[channel], Overlapping: overlapping, Score: score})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Channel < usage[j].Channel })
+	return usage
+}
+
+// validChannel reports whether channel is a candidate and, on 5 GHz, its
+// whole block at width is made of candidates (165 has no 40 MHz block).
+func validChannel(band string, channel, width int) bool {
+	candidates := wifiCandidates(band)
+	if !slices.Contains(candidates, channel) {
+		return false
+	}
+	if band != "5" {
+		return true
+	}
+	start, blockChannels := channelBlock(band, channel, width)
+	for i := 0; i < blockChannels; i++ {
+		if !slices.Contains(candidates, start+4*i) {
+			return false
+		}
+	}
+	return true
+}
+
+// dfsChannel reports whether a 5 GHz channel needs radar detection: the
+// access point must listen before using it and leave it when radar shows up.
+func dfsChannel(band string, channel int) bool {
+	return band == "5" && channel >= 52 && channel <= 144
+}
+
+// sameAccessPoint reports whether two BSSIDs differ only in the last
+// octet, as the extra networks (guest, IoT) of one radio usually do: they
+// share its airtime rather than compete for it.
+func sameAccessPoint(bssid, own string) bool {
+	return own != "" && len(bssid) == 17 && len(own) == 17 && bssid[:14] == own[:14]
+}
+
+func wifiCandidates(band string) []int {
+	switch band {
+	case "2.4":
+		return wifiCandidates24
+	case "5":
+		return wifiCandidates5
+	}
+	return nil
+}
+
+// recommendChannels returns up to wifiRecommendations candidate channels
+// whose score is at most half the current one, quietest first and non-DFS
+// first among equals. Channels in the same block as the current one, or as
+// a better candidate, are skipped: a wide channel would overlap the same
+// networks.
+func recommendChannels(usage []wifiChannelUsage, band string, current, width, score int) []int {
+	currentLo, currentHi := channelSpan(band, current, width)
+	var better []wifiChannelUsage
+	for _, entry := range usage {
+		lo, hi := channelSpan(band, entry.Channel, width)
+		if !validChannel(band, entry.Channel, width) || (lo == currentLo && hi == currentHi) || entry.Score*2 > score {
+			continue
+		}
+		better = append(better, entry)
+	}
+	sort.SliceStable(better, func(i, j int) bool {
+		if better[i].Score != better[j].Score {
+			return better[i].Score < better[j].Score
+		}
+		return !dfsChannel(band, better[i].Channel) && dfsChannel(band, better[j].Channel)
+	})
+	var channels []int
+	seen := map[[2]float64]bool{}
+	for _, entry := range better {
+		lo, hi := channelSpan(band, entry.Channel, width)
+		if seen[[2]float64{lo, hi}] {
+			continue
+		}
+		seen[[2]float64{lo, hi}] = true
+		channels = append(channels, entry.Channel)
+		if len(channels) == wifiRecommendations {
+			break
+		}
+	}
+	return channels
+}
+
+// parseIWScan parses `iw dev <iface> scan` (or `scan dump`): one "BSS"
+// block per access point, with the width taken from the HT and VHT
+// operation elements.
+func parseIWScan(output string) []wifiNetwork {
+	var networks []wifiNetwork
+	var current *wifiNetwork
+	secondary := ""
+	flush := func() {
+		if current == nil || current.Channel == 0 {
+			return
+		}
+		switch {
+		case current.WidthMHz >= 80:
+		case secondary != "":
+			// HT 40 MHz: the secondary channel sits above or below.
+			current.WidthMHz = 40
+			offset := 10
+			if secondary == "below" {
+				offset = -10
+			}
+			current.CenterMHz = wifiFrequency(current.Band, current.Channel) + offset
+		default:
+			current.WidthMHz = 20
+		}
+		networks = append(networks, *current)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if m := iwBSSRe.FindStringSubmatch(line); len(m) == 2 {
+			flush()
+			current, secondary = &wifiNetwork{BSSID: normalizeMAC(m[1])}, ""
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case iwScanFreqRe.MatchString(line):
+			freq, _ := strconv.Atoi(iwScanFreqRe.FindStringSubmatch(line)[1])
+			current.Band, current.Channel = wifiChannel(freq)
+		case iwScanSignalRe.MatchString(line):
+			current.SignalDbm, _ = strconv.Atoi(iwScanSignalRe.FindStringSubmatch(line)[1])
+		case iwPrimaryRe.MatchString(line):
+			current.Channel, _ = strconv.Atoi(iwPrimaryRe.FindStringSubmatch(line)[1])
+		case iwSecondaryRe.MatchString(line):
+			secondary = iwSecondaryRe.FindStringSubmatch(line)[1]
+		case iwVHTWidthRe.MatchString(line):
+			// VHT width 1 is 80 MHz, 2 is 160 MHz; 0 defers to HT.
+			switch iwVHTWidthRe.FindStringSubmatch(line)[1] {
+			case "1":
+				current.WidthMHz = 80
+			case "2":
+				current.WidthMHz = 160
+			}
+		case iwVHTCenterRe.MatchString(line) && current.WidthMHz >= 80:
+			center, _ := strconv.Atoi(iwVHTCenterRe.FindStringSubmatch(line)[1])
+			current.CenterMHz = wifiFrequency(current.Band, center)
+		}
+	}
+	flush()
+	return networks
+}
+
+// parseNetshNetworks parses `netsh wlan show networks mode=bssid`: each
+// "BSSID n" line starts an access point, followed by its signal quality
+// and channel. netsh does not report the width, so 20 MHz is assumed.
+func parseNetshNetworks(output string) []wifiNetwork {
+	var networks []wifiNetwork
+	var current *wifiNetwork
+	flush := func() {
+		if current == nil || current.Channel == 0 {
+			return
+		}
+		if current.Band == "" {
+			current.Band = "5"
+			if current.Channel <= 14 {
+				current.Band = "2.4"
+			}
+		}
+		networks = append(networks, *current)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if m := netshScanBSSIDRe.FindStringSubmatch(line); len(m) == 2 {
+			flush()
+			current = &wifiNetwork{BSSID: normalizeMAC(m[1]), WidthMHz: 20}
+			continue
+		}
+		if strings.HasPrefix(strings.ToUpper(line), "SSID") {
+			// The next network: its access points follow.
+			flush()
+			current = nil
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if current == nil || !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch {
+		case strings.HasSuffix(value, "%"):
+			if quality, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(value, "%"))); err == nil {
+				current.SignalDbm = min(quality/2-100, -50)
+			}
+		case slices.Contains(netshChannelKeys, key):
+			current.Channel, _ = strconv.Atoi(value)
+		case slices.Contains(netshBandKeys, key):
+			if m := wifiBandRe.FindStringSubmatch(value); len(m) == 2 {
+				current.Band = strings.ReplaceAll(m[1], ",", ".")
+			}
+		}
+	}
+	flush()
+	return networks
+}
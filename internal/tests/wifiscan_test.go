@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+)
+
+// iwScanOutput is `iw dev wlan0 scan` around an access point on channel 6
+// (3c:a6:2f:11:22:33, with a guest network on the same radio): a strong
+// 40 MHz neighbour on 1, a medium one on 7, a faint one on 11 and an
+// 80 MHz network on 5 GHz.
+const iwScanOutput = "BSS 3c:a6:2f:11:22:33(on wlan0) -- associated\n" +
+	"\tlast seen: 1234.567s [boottime]\n" +
+	"\tTSF: 1234567890 usec (0d, 00:20:34)\n" +
+	"\tfreq: 2437\n" +
+	"\tbeacon interval: 100 TUs\n" +
+	"\tcapability: ESS Privacy ShortSlotTime (0x0411)\n" +
+	"\tsignal: -50.00 dBm\n" +
+	"\tlast seen: 20 ms ago\n" +
+	"\tSSID: HomeNet\n" +
+	"\tDS Parameter set: channel 6\n" +
+	"\tHT operation:\n" +
+	"\t\t * primary channel: 6\n" +
+	"\t\t * secondary channel offset: no secondary\n" +
+	"\t\t * STA channel width: 20 MHz\n" +
+	"BSS 3c:a6:2f:11:22:34(on wlan0)\n" +
+	"\tfreq: 2437\n" +
+	"\tsignal: -51.00 dBm\n" +
+	"\tSSID: HomeNet-Guest\n" +
+	"\tHT operation:\n" +
+	"\t\t * primary channel: 6\n" +
+	"\t\t * secondary channel offset: no secondary\n" +
+	"BSS 10:20:30:40:50:60(on wlan0)\n" +
+	"\tfreq: 2412.0\n" +
+	"\tsignal: -60.00 dBm\n" +
+	"\tSSID: Neighbour\n" +
+	"\tHT operation:\n" +
+	"\t\t * primary channel: 1\n" +
+	"\t\t * secondary channel offset: above\n" +
+	"\t\t * STA channel width: any\n" +
+	"BSS 10:20:30:40:50:61(on wlan0)\n" +
+	"\tfreq: 2442\n" +
+	"\tsignal: -70.00 dBm\n" +
+	"\tSSID: Upstairs\n" +
+	"BSS 10:20:30:40:50:62(on wlan0)\n" +
+	"\tfreq: 2462\n" +
+	"\tsignal: -85.00 dBm\n" +
+	"\tSSID: FarAway\n" +
+	"\tHT operation:\n" +
+	"\t\t * primary channel: 11\n" +
+	"\t\t * secondary channel offset: no secondary\n" +
+	"BSS 10:20:30:40:50:63(on wlan0)\n" +
+	"\tfreq: 5180\n" +
+	"\tsignal: -65.00 dBm\n" +
+	"\tSSID: Neighbour 5G\n" +
+	"\tHT operation:\n" +
+	"\t\t * primary channel: 36\n" +
+	"\t\t * secondary channel offset: above\n" +
+	"\t\t * STA channel width: any\n" +
+	"\tVHT operation:\n" +
+	"\t\t * channel width: 1 (80 MHz)\n" +
+	"\t\t * center freq segment 1: 42\n" +
+	"\t\t * center freq segment 2: 0\n" +
+	"BSS 10:20:30:40:50:64(on wlan0)\n" +
+	"\tsignal: -90.00 dBm\n" +
+	"\tSSID: NoFrequency\n"
+
+func TestParseIWScan(t *testing.T) {
+	want := []wifiNetwork{
+		{BSSID: "3c:a6:2f:11:22:33", Band: "2.4", Channel: 6, WidthMHz: 20, SignalDbm: -50},
+		{BSSID: "3c:a6:2f:11:22:34", Band: "2.4", Channel: 6, WidthMHz: 20, SignalDbm: -51},
+		{BSSID: "10:20:30:40:50:60", Band: "2.4", Channel: 1, WidthMHz: 40, CenterMHz: 2422, SignalDbm: -60},
+		{BSSID: "10:20:30:40:50:61", Band: "2.4", Channel: 7, WidthMHz: 20, SignalDbm: -70},
+		{BSSID: "10:20:30:40:50:62", Band: "2.4", Channel: 11, WidthMHz: 20, SignalDbm: -85},
+		{BSSID: "10:20:30:40:50:63", Band: "5", Channel: 36, WidthMHz: 80, CenterMHz: 5210, SignalDbm: -65},
+	}
+	if got := parseIWScan(iwScanOutput); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIWScan =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseNetshNetworks(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []wifiNetwork
+	}{
+		{
+			name: "English",
+			output: "\r\nInterface name : Wi-Fi\r\n" +
+				"There are 3 networks currently visible.\r\n\r\n" +
+				"SSID 1 : HomeNet\r\n" +
+				"    Network type            : Infrastructure\r\n" +
+				"    Authentication          : WPA2-Personal\r\n" +
+				"    Encryption              : CCMP\r\n" +
+				"    BSSID 1                 : 3c:a6:2f:11:22:33\r\n" +
+				"         Signal             : 90%\r\n" +
+				"         Radio type         : 802.11ax\r\n" +
+				"         Band               : 2.4 GHz\r\n" +
+				"         Channel            : 6\r\n" +
+				"         Basic rates (Mbps) : 1 2 5.5 11\r\n" +
+				"         Other rates (Mbps) : 6 9 12 18 24 36 48 54\r\n" +
+				"    BSSID 2                 : 3c:a6:2f:11:22:37\r\n" +
+				"         Signal             : 100%\r\n" +
+				"         Radio type         : 802.11ax\r\n" +
+				"         Band               : 5 GHz\r\n" +
+				"         Channel            : 36\r\n\r\n" +
+				"SSID 2 : Neighbour\r\n" +
+				"    Network type            : Infrastructure\r\n" +
+				"    BSSID 1                 : 10-20-30-40-50-60\r\n" +
+				"         Signal             : 40%\r\n" +
+				"         Radio type         : 802.11n\r\n" +
+				"         Channel            : 1\r\n\r\n" +
+				"SSID 3 : \r\n" +
+				"    BSSID 1                 : 10:20:30:40:50:61\r\n" +
+				"         Signal             : 60%\r\n" +
+				"         Channel            : 100\r\n",
+			want: []wifiNetwork{
+				{BSSID: "3c:a6:2f:11:22:33", Band: "2.4", Channel: 6, WidthMHz: 20, SignalDbm: -55},
+				{BSSID: "3c:a6:2f:11:22:37", Band: "5", Channel: 36, WidthMHz: 20, SignalDbm: -50},
+				{BSSID: "10:20:30:40:50:60", Band: "2.4", Channel: 1, WidthMHz: 20, SignalDbm: -80},
+				{BSSID: "10:20:30:40:50:61", Band: "5", Channel: 100, WidthMHz: 20, SignalDbm: -70},
+			},
+		},
+		{
+			name: "Italian",
+			output: "\r\nNome interfaccia : Wi-Fi\r\n" +
+				"Attualmente sono visibili 1 reti.\r\n\r\n" +
+				"SSID 1 : Casa\r\n" +
+				"    Tipo di rete            : Infrastruttura\r\n" +
+				"    Autenticazione          : WPA2-Personal\r\n" +
+				"    BSSID 1                 : 3c:a6:2f:11:22:33\r\n" +
+				"         Segnale            : 84%\r\n" +
+				"         Tipo frequenza radio : 802.11ac\r\n" +
+				"         Banda              : 2,4 GHz\r\n" +
+				"         Canale             : 11\r\n",
+			want: []wifiNetwork{
+				{BSSID: "3c:a6:2f:11:22:33", Band: "2.4", Channel: 11, WidthMHz: 20, SignalDbm: -58},
+			},
+		},
+		{
+			name:   "no networks",
+			output: "\r\nInterface name : Wi-Fi\r\nThere are 0 networks currently visible.\r\n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNetshNetworks(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNetshNetworks =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWiFiUsage(t *testing.T) {
+	const own = "3c:a6:2f:11:22:33"
+	networks := parseIWScan(iwScanOutput)
+
+	// Channel 6 at 20 MHz spans 2426-2448: the 40 MHz network on 1
+	// (2402-2442) and the one on 7 (2431-2453) overlap it, the one on 11
+	// (2451-2473) does not, and the guest network is our own radio.
+	lo, hi := channelSpan("2.4", 6, 20)
+	if overlapping, score := congestion(networks, "2.4", lo, hi, own); overlapping != 2 || score != wifiStrongNetworkPoints+wifiMediumNetworkPoints {
+		t.Errorf("congestion on channel 6 = %d networks, score %d; want 2, %d", overlapping, score, wifiStrongNetworkPoints+wifiMediumNetworkPoints)
+	}
+
+	usage := wifiUsage(networks, "2.4", 6, 20, own)
+	want := []wifiChannelUsage{
+		{Channel: 1, Networks: 1, Overlapping: 1, Score: 10},
+		{Channel: 6, Networks: 0, Overlapping: 2, Score: 15},
+		{Channel: 7, Networks: 1, Overlapping: 3, Score: 17},
+		{Channel: 11, Networks: 1, Overlapping: 2, Score: 7},
+	}
+	if !reflect.DeepEqual(usage, want) {
+		t.Errorf("wifiUsage =\n%+v\nwant\n%+v", usage, want)
+	}
+	// Only 11 scores at most half of 15; 7 is not a candidate channel.
+	if got := recommendChannels(usage, "2.4", 6, 20, 15); !reflect.DeepEqual(got, []int{11}) {
+		t.Errorf("recommendChannels = %v, want [11]", got)
+	}
+}
+
+func TestRecommendChannels(t *testing.T) {
+	tests := []struct {
+		name    string
+		usage   []wifiChannelUsage
+		band    string
+		current int
+		width   int
+		score   int
+		want    []int
+	}{
+		{
+			name: "quietest first, non-DFS first among equals, capped",
+			usage: []wifiChannelUsage{
+				{Channel: 36, Score: 40}, {Channel: 40}, {Channel: 52}, {Channel: 100},
+				{Channel: 116}, {Channel: 132, Score: 25}, {Channel: 149}, {Channel: 153}, {Channel: 165},
+			},
+			band: "5", current: 36, width: 80, score: 40,
+			// 40 shares the block of 36, 153 the block of 149, 165 has no
+			// 80 MHz block and 132 is not quiet enough.
+			want: []int{149, 52, 100},
+		},
+		{
+			name: "20 MHz on 5 GHz",
+			usage: []wifiChannelUsage{
+				{Channel: 36, Score: 30}, {Channel: 40, Score: 10}, {Channel: 44, Score: 15}, {Channel: 165, Score: 5},
+			},
+			band: "5", current: 36, width: 20, score: 30,
+			want: []int{165, 40, 44},
+		},
+		{
+			name:  "2.4 GHz",
+			usage: []wifiChannelUsage{{Channel: 1, Score: 20}, {Channel: 3, Score: 0}, {Channel: 6, Score: 60}, {Channel: 11, Score: 30}},
+			band:  "2.4", current: 6, width: 20, score: 60,
+			want: []int{1, 11},
+		},
+		{
+			name:  "nothing quieter",
+			usage: []wifiChannelUsage{{Channel: 1, Score: 40}, {Channel: 6, Score: 60}, {Channel: 11, Score: 35}},
+			band:  "2.4", current: 6, width: 20, score: 60,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recommendChannels(tt.usage, tt.band, tt.current, tt.width, tt.score); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recommendChannels = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package timeseries
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format selects the wire encoding of points.
+type Format string
+
+const (
+	FormatInflux Format = "influx"
+	FormatNDJSON Format = "ndjson"
+)
+
+// ParseFormat accepts the names used in conncheck.yaml.
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case FormatInflux, FormatNDJSON:
+		return Format(value), nil
+	default:
+		return "", fmt.Errorf("unknown time-series format %q (use influx or ndjson)", value)
+	}
+}
+
+// Extension is the file extension used by the file sink.
+func (f Format) Extension() string {
+	if f == FormatNDJSON {
+		return ".ndjson"
+	}
+	return ".lp"
+}
+
+// ContentType is the HTTP content type used by the push sink.
+func (f Format) ContentType() string {
+	if f == FormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// Encode writes points in format f, one point per line.
+func Encode(w io.Writer, f Format, points []Point) error {
+	for _, point := range points {
+		var line string
+		var err error
+		if f == FormatNDJSON {
+			line, err = ndjsonLine(point)
+		} else {
+			line, err = lineProtocol(point)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineProtocol renders p as InfluxDB line protocol with a nanosecond
+// timestamp.
+func lineProtocol(p Point) (string, error) {
+	if len(p.Fields) == 0 {
+		return "", fmt.Errorf("point %s has no fields", p.Measurement)
+	}
+	var b strings.Builder
+	b.WriteString(escapeLP(p.Measurement, ", "))
+	for _, key := range sortedKeys(p.Tags) {
+		value := p.Tags[key]
+		if value == "" {
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(escapeLP(key, ",= "))
+		b.WriteByte('=')
+		b.WriteString(escapeLP(value, ",= "))
+	}
+	b.WriteByte(' ')
+	for i, key := range sortedKeys(p.Fields) {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(escapeLP(key, ",= "))
+		b.WriteByte('=')
+		value, err := fieldValue(p.Fields[key])
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", p.Measurement, key, err)
+		}
+		b.WriteString(value)
+	}
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(p.Time.UnixNano(), 10))
+	return b.String(), nil
+}
+
+func fieldValue(value any) (string, error) {
+	switch v := value.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("non-finite value %v", v)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int64:
+		return strconv.FormatInt(v, 10) + "i", nil
+	case int:
+		return strconv.Itoa(v) + "i", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case string:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`, nil
+	default:
+		return "", fmt.Errorf("unsupported field type %T", value)
+	}
+}
+
+func escapeLP(value, special string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r == '\\' || strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		if r == '\n' {
+			b.WriteString(`\n`)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func ndjsonLine(p Point) (string, error) {
+	data, err := json.Marshal(struct {
+		Measurement string            `json:"measurement"`
+		Time        string            `json:"time"`
+		Tags        map[string]string `json:"tags"`
+		Fields      map[string]any    `json:"fields"`
+	}{p.Measurement, p.Time.UTC().Format(time.RFC3339Nano), p.Tags, p.Fields})
+	return string(data), err
+}
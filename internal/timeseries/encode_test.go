@@ -0,0 +1,109 @@
+package timeseries
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineProtocol(t *testing.T) {
+	at := time.Unix(1700000000, 5)
+	tests := []struct {
+		name  string
+		point Point
+		want  string
+	}{
+		{
+			name:  "plain",
+			point: Point{Measurement: "conncheck_test", Tags: map[string]string{"test": "dns"}, Fields: map[string]any{"ok": true}, Time: at},
+			want:  `conncheck_test,test=dns ok=true 1700000000000000005`,
+		},
+		{
+			name:  "space in tag value",
+			point: Point{Measurement: "m", Tags: map[string]string{"host": "office pc"}, Fields: map[string]any{"v": 1.5}, Time: at},
+			want:  `m,host=office\ pc v=1.5 1700000000000000005`,
+		},
+		{
+			name:  "comma in tag value",
+			point: Point{Measurement: "m", Tags: map[string]string{"region": "it,north"}, Fields: map[string]any{"v": 1.5}, Time: at},
+			want:  `m,region=it\,north v=1.5 1700000000000000005`,
+		},
+		{
+			name:  "equals in tag key and value",
+			point: Point{Measurement: "m", Tags: map[string]string{"a=b": "c=d"}, Fields: map[string]any{"v": 1.5}, Time: at},
+			want:  `m,a\=b=c\=d v=1.5 1700000000000000005`,
+		},
+		{
+			name:  "measurement escapes comma and space but not equals",
+			point: Point{Measurement: "my m,x=y", Fields: map[string]any{"v": 1.5}, Time: at},
+			want:  `my\ m\,x=y v=1.5 1700000000000000005`,
+		},
+		{
+			name:  "backslash and newline",
+			point: Point{Measurement: "m", Tags: map[string]string{"path": `C:\temp` + "\n"}, Fields: map[string]any{"v": 1.5}, Time: at},
+			want:  `m,path=C:\\temp\n v=1.5 1700000000000000005`,
+		},
+		{
+			name:  "empty tags are dropped and tags are sorted",
+			point: Point{Measurement: "m", Tags: map[string]string{"z": "1", "a": "2", "empty": ""}, Fields: map[string]any{"v": 1.5}, Time: at},
+			want:  `m,a=2,z=1 v=1.5 1700000000000000005`,
+		},
+		{
+			name:  "field types",
+			point: Point{Measurement: "m", Fields: map[string]any{"b": false, "f": 0.25, "i": 3, "j": int64(-4), "s": `say "hi" \ bye`}, Time: at},
+			want:  `m b=false,f=0.25,i=3i,j=-4i,s="say \"hi\" \\ bye" 1700000000000000005`,
+		},
+		{
+			name:  "field key escaping",
+			point: Point{Measurement: "m", Fields: map[string]any{"rtt ms,p=95": 1.5}, Time: at},
+			want:  `m rtt\ ms\,p\=95=1.5 1700000000000000005`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lineProtocol(tt.point)
+			if err != nil {
+				t.Fatalf("lineProtocol: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("lineProtocol =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLineProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		point Point
+	}{
+		{name: "no fields", point: Point{Measurement: "m"}},
+		{name: "NaN", point: Point{Measurement: "m", Fields: map[string]any{"v": math.NaN()}}},
+		{name: "infinity", point: Point{Measurement: "m", Fields: map[string]any{"v": math.Inf(1)}}},
+		{name: "unsupported type", point: Point{Measurement: "m", Fields: map[string]any{"v": []int{1}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := lineProtocol(tt.point); err == nil {
+				t.Error("lineProtocol succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestEncodeNDJSON(t *testing.T) {
+	points := []Point{
+		{Measurement: "m", Tags: map[string]string{"host": "office pc"}, Fields: map[string]any{"v": 1.5}, Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{Measurement: "m", Tags: map[string]string{}, Fields: map[string]any{"ok": true}, Time: time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC)},
+	}
+	var b strings.Builder
+	if err := Encode(&b, FormatNDJSON, points); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	want := `{"measurement":"m","time":"2026-01-02T03:04:05Z","tags":{"host":"office pc"},"fields":{"v":1.5}}` + "\n" +
+		`{"measurement":"m","time":"2026-01-02T03:04:06Z","tags":{},"fields":{"ok":true}}` + "\n"
+	if b.String() != want {
+		t.Errorf("Encode =\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,211 @@
+package timeseries
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"conncheck/internal/model"
+)
+
+// Measurement names shared by every encoder.
+const (
+	MeasurementTest      = "conncheck_test"
+	MeasurementLatency   = "conncheck_latency"
+	MeasurementDNS       = "conncheck_dns_query"
+	MeasurementSpeedtest = "conncheck_speedtest"
+)
+
+// Point is one time-series sample. Field values are float64, int64, bool or
+// string.
+type Point struct {
+	Measurement string            `json:"measurement"`
+	Tags        map[string]string `json:"tags"`
+	Fields      map[string]any    `json:"fields"`
+	Time        time.Time         `json:"time"`
+}
+
+type latencySample struct {
+	OffsetMs  int  `json:"t"`
+	LatencyMs int  `json:"latency"`
+	Loss      bool `json:"loss"`
+}
+
+type dnsSample struct {
+	OffsetMs  int     `json:"t"`
+	Domain    string  `json:"domain"`
+	LatencyMs float64 `json:"ms"`
+	OK        bool    `json:"ok"`
+}
+
+var speedtestRunKey = regexp.MustCompile(`^([a-z]+)_server_(\d+)_run_(\d+)_down_bps$`)
+
+// Collect flattens result into points: one summary per test, every latency
+// sample (including bufferbloat phases), every DNS query and every speedtest
+// run. Each point carries the host tag so runs from several machines can share
+// a bucket.
+func Collect(result model.Result) []Point {
+	var points []Point
+	host := result.Environment.Hostname
+	for _, test := range result.Tests {
+		points = append(points, testPoint(test, host))
+		switch test.Name {
+		case "latency":
+			points = append(points, latencyPoints(test, "latency_series.", "target", host)...)
+		case "bufferbloat":
+			points = append(points, latencyPoints(test, "bufferbloat_series.", "phase", host)...)
+		case "dns_benchmark":
+			points = append(points, dnsPoints(test, host)...)
+		case "speedtest":
+			points = append(points, speedtestPoints(test, host)...)
+		}
+	}
+	return points
+}
+
+func testPoint(test model.TestResult, host string) Point {
+	severities := map[string]int64{}
+	for _, finding := range test.Findings {
+		severities[finding.Severity]++
+	}
+	return Point{
+		Measurement: MeasurementTest,
+		Tags:        withHost(map[string]string{"test": test.Name, "status": test.Status}, host),
+		Fields: map[string]any{
+			"duration_ms":    float64(test.EndedAt.Sub(test.StartedAt).Microseconds()) / 1000,
+			"status_code":    statusCode(test.Status),
+			"findings_info":  severities["INFO"],
+			"findings_warn":  severities["WARN"],
+			"findings_fail":  severities["FAIL"],
+			"evidence_count": int64(len(test.Evidence)),
+		},
+		Time: test.EndedAt,
+	}
+}
+
+// statusCode orders statuses so dashboards can plot the worst one.
+func statusCode(status string) int64 {
+	switch status {
+	case "OK":
+		return 0
+	case "WARN":
+		return 1
+	case "FAIL":
+		return 2
+	default:
+		return -1
+	}
+}
+
+func latencyPoints(test model.TestResult, prefix, tagKey, host string) []Point {
+	var points []Point
+	for _, key := range sortedKeys(test.Metrics) {
+		name, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		var samples []latencySample
+		if err := json.Unmarshal([]byte(test.Metrics[key]), &samples); err != nil {
+			continue
+		}
+		tags := map[string]string{"test": test.Name, tagKey: name}
+		if tagKey != "target" && test.Metrics["target"] != "" {
+			tags["target"] = test.Metrics["target"]
+		}
+		for _, sample := range samples {
+			fields := map[string]any{"loss": sample.Loss}
+			if !sample.Loss && sample.LatencyMs >= 0 {
+				fields["rtt_ms"] = float64(sample.LatencyMs)
+			}
+			points = append(points, Point{
+				Measurement: MeasurementLatency,
+				Tags:        withHost(copyTags(tags), host),
+				Fields:      fields,
+				Time:        test.StartedAt.Add(time.Duration(sample.OffsetMs) * time.Millisecond),
+			})
+		}
+	}
+	return points
+}
+
+func dnsPoints(test model.TestResult, host string) []Point {
+	var points []Point
+	for _, key := range sortedKeys(test.Metrics) {
+		resolver, ok := strings.CutPrefix(key, "dns_series.")
+		if !ok {
+			continue
+		}
+		var samples []dnsSample
+		if err := json.Unmarshal([]byte(test.Metrics[key]), &samples); err != nil {
+			continue
+		}
+		for _, sample := range samples {
+			points = append(points, Point{
+				Measurement: MeasurementDNS,
+				Tags:        withHost(map[string]string{"resolver": resolver, "domain": sample.Domain}, host),
+				Fields:      map[string]any{"duration_ms": sample.LatencyMs, "success": sample.OK},
+				Time:        test.StartedAt.Add(time.Duration(sample.OffsetMs) * time.Millisecond),
+			})
+		}
+	}
+	return points
+}
+
+func speedtestPoints(test model.TestResult, host string) []Point {
+	var points []Point
+	for _, key := range sortedKeys(test.Metrics) {
+		match := speedtestRunKey.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+		prefix := strings.TrimSuffix(key, "_down_bps")
+		fields := map[string]any{}
+		for _, field := range []struct{ suffix, name string }{{"_down_bps", "download_bps"}, {"_up_bps", "upload_bps"}, {"_ping_ms", "ping_ms"}} {
+			if value, err := strconv.ParseFloat(test.Metrics[prefix+field.suffix], 64); err == nil {
+				fields[field.name] = value
+			}
+		}
+		at := test.EndedAt
+		if parsed, err := time.Parse(time.RFC3339, test.Metrics[prefix+"_time"]); err == nil {
+			at = parsed
+		}
+		tags := map[string]string{"category": match[1], "server_id": match[2], "run": match[3]}
+		if name := test.Metrics[prefix+"_name"]; name != "" {
+			tags["server_name"] = name
+		}
+		points = append(points, Point{
+			Measurement: MeasurementSpeedtest,
+			Tags:        withHost(tags, host),
+			Fields:      fields,
+			Time:        at,
+		})
+	}
+	return points
+}
+
+func withHost(tags map[string]string, host string) map[string]string {
+	if host != "" {
+		tags["host"] = host
+	}
+	return tags
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	out := make(map[string]string, len(tags))
+	for key, value := range tags {
+		out[key] = value
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,136 @@
+package timeseries
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	defaultBatchSize = 5000
+	defaultRetries   = 3
+	defaultBackoff   = time.Second
+	pushTimeout      = 30 * time.Second
+)
+
+// Sink receives encoded points.
+type Sink interface {
+	Write(ctx context.Context, points []Point) error
+}
+
+// FileSink appends points to Path, creating it when missing. Each run and
+// each monitor cycle passes a path inside its own output folder.
+type FileSink struct {
+	Path   string
+	Format Format
+}
+
+func (f FileSink) Write(_ context.Context, points []Point) error {
+	file, err := os.OpenFile(f.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := Encode(file, f.Format, points); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// HTTPSink POSTs points to URL in batches of BatchSize. Network errors, 429
+// and 5xx responses are retried up to Retries times with exponential backoff
+// starting at Backoff; other 4xx responses fail immediately.
+type HTTPSink struct {
+	URL       string
+	Format    Format
+	Token     string
+	BatchSize int
+	Retries   int
+	Backoff   time.Duration
+	Client    *http.Client
+}
+
+func (h HTTPSink) Write(ctx context.Context, points []Point) error {
+	batchSize := h.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	for start := 0; start < len(points); start += batchSize {
+		end := start + batchSize
+		if end > len(points) {
+			end = len(points)
+		}
+		var buf bytes.Buffer
+		if err := Encode(&buf, h.Format, points[start:end]); err != nil {
+			return err
+		}
+		if err := h.push(ctx, buf.Bytes()); err != nil {
+			return fmt.Errorf("batch %d-%d: %w", start, end, err)
+		}
+	}
+	return nil
+}
+
+func (h HTTPSink) push(ctx context.Context, body []byte) error {
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: pushTimeout}
+	}
+	retries := h.Retries
+	if retries < 0 {
+		retries = 0
+	} else if retries == 0 {
+		retries = defaultRetries
+	}
+	backoff := h.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff << (attempt - 1)):
+			}
+		}
+		retry, err := h.send(ctx, client, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// send performs one POST and reports whether a failure is worth retrying.
+func (h HTTPSink) send(ctx context.Context, client *http.Client, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", h.Format.ContentType())
+	if h.Token != "" {
+		req.Header.Set("Authorization", "Token "+h.Token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(message))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
@@ -0,0 +1,177 @@
+package timeseries
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is an HTTP stand-in answering with statuses in turn, then 204.
+type recorder struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	times    []time.Time
+	headers  []http.Header
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, string(body))
+	r.times = append(r.times, time.Now())
+	r.headers = append(r.headers, req.Header.Clone())
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (r *recorder) attempts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+func testPoints(n int) []Point {
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{
+			Measurement: MeasurementLatency,
+			Tags:        map[string]string{"target": "1.1.1.1"},
+			Fields:      map[string]any{"rtt_ms": float64(i)},
+			Time:        time.Unix(1700000000, int64(i)),
+		}
+	}
+	return points
+}
+
+func TestHTTPSinkBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		points    int
+		batchSize int
+		want      []int
+	}{
+		{name: "single partial batch", points: 3, batchSize: 5, want: []int{3}},
+		{name: "exact multiple", points: 10, batchSize: 5, want: []int{5, 5}},
+		{name: "remainder in last batch", points: 11, batchSize: 5, want: []int{5, 5, 1}},
+		{name: "batch of one", points: 3, batchSize: 1, want: []int{1, 1, 1}},
+		{name: "default batch size", points: 7, batchSize: 0, want: []int{7}},
+		{name: "no points", points: 0, batchSize: 5, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			server := httptest.NewServer(rec)
+			defer server.Close()
+
+			sink := HTTPSink{URL: server.URL, Format: FormatInflux, Token: "secret", BatchSize: tt.batchSize}
+			if err := sink.Write(context.Background(), testPoints(tt.points)); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			var got []int
+			for _, body := range rec.bodies {
+				got = append(got, strings.Count(body, "\n"))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("batches = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("batches = %v, want %v", got, tt.want)
+				}
+			}
+			for _, header := range rec.headers {
+				if header.Get("Authorization") != "Token secret" {
+					t.Errorf("Authorization = %q", header.Get("Authorization"))
+				}
+				if header.Get("Content-Type") != FormatInflux.ContentType() {
+					t.Errorf("Content-Type = %q", header.Get("Content-Type"))
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPSinkRetries(t *testing.T) {
+	const backoff = 20 * time.Millisecond
+	tests := []struct {
+		name         string
+		statuses     []int
+		retries      int
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "success first time", statuses: nil, retries: 3, wantAttempts: 1},
+		{name: "429 then success", statuses: []int{429}, retries: 3, wantAttempts: 2},
+		{name: "5xx then success", statuses: []int{500, 503}, retries: 3, wantAttempts: 3},
+		{name: "400 is not retried", statuses: []int{400}, retries: 3, wantAttempts: 1, wantErr: true},
+		{name: "401 is not retried", statuses: []int{401}, retries: 3, wantAttempts: 1, wantErr: true},
+		{name: "gives up after max attempts", statuses: []int{502, 502, 502, 502, 502}, retries: 2, wantAttempts: 3, wantErr: true},
+		{name: "negative retries means one attempt", statuses: []int{503}, retries: -1, wantAttempts: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{statuses: append([]int(nil), tt.statuses...)}
+			server := httptest.NewServer(rec)
+			defer server.Close()
+
+			sink := HTTPSink{URL: server.URL, Format: FormatInflux, Retries: tt.retries, Backoff: backoff}
+			err := sink.Write(context.Background(), testPoints(2))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Write error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := rec.attempts(); got != tt.wantAttempts {
+				t.Fatalf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			// Each retry waits twice as long as the previous one.
+			for i := 1; i < len(rec.times); i++ {
+				gap := rec.times[i].Sub(rec.times[i-1])
+				if want := backoff << (i - 1); gap < want {
+					t.Errorf("retry %d after %v, want at least %v", i, gap, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPSinkRetriesConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	sink := HTTPSink{URL: url, Format: FormatInflux, Retries: 2, Backoff: time.Millisecond}
+	start := time.Now()
+	if err := sink.Write(context.Background(), testPoints(1)); err == nil {
+		t.Fatal("Write to a closed server succeeded")
+	}
+	// Two retries wait 1 ms and 2 ms.
+	if elapsed := time.Since(start); elapsed < 3*time.Millisecond {
+		t.Errorf("gave up after %v, before retrying", elapsed)
+	}
+}
+
+func TestHTTPSinkStopsOnCancel(t *testing.T) {
+	rec := &recorder{statuses: []int{503, 503, 503}}
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	sink := HTTPSink{URL: server.URL, Format: FormatInflux, Retries: 3, Backoff: time.Hour}
+	if err := sink.Write(ctx, testPoints(1)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Write error = %v, want context.Canceled", err)
+	}
+	if got := rec.attempts(); got != 1 {
+		t.Fatalf("attempts = %d, want 1", got)
+	}
+}
@@ -0,0 +1,241 @@
+package webui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"conncheck/internal/config"
+	"conncheck/internal/engine"
+	"conncheck/internal/history"
+	"conncheck/internal/model"
+	"conncheck/internal/report"
+)
+
+// RunRequest is what the start form asks for.
+type RunRequest struct {
+	Mode string
+	Only []string
+	Skip []string
+}
+
+// RunFunc runs the suite into dir, reporting progress to events, and
+// returns the result once every output is written.
+type RunFunc func(ctx context.Context, dir string, req RunRequest, events func(model.Event)) (model.Result, error)
+
+// subscriberBuffer is how many events a browser may lag behind. Progress
+// events only fill half of it, keeping room for test and run events.
+const subscriberBuffer = 256
+
+// job is a run started from the web app. It keeps the events seen so far so
+// a page opened mid-run can catch up: every event except test_progress, of
+// which only the latest per test is kept.
+type job struct {
+	dir     string
+	request RunRequest
+	cancel  context.CancelFunc
+
+	mu          sync.Mutex
+	events      []model.Event
+	progress    map[string]model.Event
+	done        bool
+	subscribers map[chan model.Event]struct{}
+}
+
+func (j *job) name() string {
+	return filepath.Base(j.dir)
+}
+
+func (j *job) finished() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.done
+}
+
+func (j *job) publish(event model.Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if event.Type == "test_progress" {
+		j.progress[event.Test] = event
+	} else {
+		j.events = append(j.events, event)
+	}
+	for ch := range j.subscribers {
+		if event.Type == "test_progress" && len(ch) >= cap(ch)/2 {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+			// A stalled browser is dropped; EventSource reconnects and
+			// catches up from the backlog.
+			delete(j.subscribers, ch)
+			close(ch)
+		}
+	}
+	if event.Type == "run_end" || event.Type == "error" {
+		j.done = true
+		for ch := range j.subscribers {
+			close(ch)
+		}
+		j.subscribers = nil
+	}
+}
+
+// subscribe returns the events so far and, unless the run has ended, a
+// channel for the next ones.
+func (j *job) subscribe() ([]model.Event, chan model.Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	backlog := slices.Clone(j.events)
+	for _, event := range j.progress {
+		backlog = append(backlog, event)
+	}
+	slices.SortStableFunc(backlog, func(a, b model.Event) int {
+		return a.Time.Compare(b.Time)
+	})
+	if j.done {
+		return backlog, nil
+	}
+	ch := make(chan model.Event, subscriberBuffer)
+	j.subscribers[ch] = struct{}{}
+	return backlog, ch
+}
+
+func (j *job) unsubscribe(ch chan model.Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, ok := j.subscribers[ch]; ok {
+		delete(j.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (s *Server) current() *job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.job
+}
+
+func (s *Server) startRun(w http.ResponseWriter, r *http.Request) {
+	req := RunRequest{
+		Mode: r.PostFormValue("mode"),
+		Only: splitList(r.PostFormValue("only")),
+		Skip: splitList(r.PostFormValue("skip")),
+	}
+	if !slices.Contains(config.Modes, req.Mode) {
+		redirectError(w, r, fmt.Sprintf("unknown mode %q", req.Mode))
+		return
+	}
+	if _, err := engine.ParseSelection(req.Only, req.Skip); err != nil {
+		redirectError(w, r, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	if s.job != nil && !s.job.finished() {
+		s.mu.Unlock()
+		http.Redirect(w, r, "/live", http.StatusSeeOther)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	current := &job{
+		dir:         history.NewRunDir(s.Root),
+		request:     req,
+		cancel:      cancel,
+		progress:    map[string]model.Event{},
+		subscribers: map[chan model.Event]struct{}{},
+	}
+	s.job = current
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		result, err := s.Run(ctx, current.dir, req, current.publish)
+		if err != nil {
+			current.publish(model.Event{Type: "error", Message: err.Error()})
+			return
+		}
+		current.publish(model.Event{Type: "run_end", Status: report.WorstStatus(result), Message: report.FormatSummary(result)})
+	}()
+	http.Redirect(w, r, "/live", http.StatusSeeOther)
+}
+
+func (s *Server) cancelRun(w http.ResponseWriter, r *http.Request) {
+	if job := s.current(); job != nil {
+		job.cancel()
+	}
+	http.Redirect(w, r, "/live", http.StatusSeeOther)
+}
+
+// events streams the current run as Server-Sent Events, one JSON
+// model.Event per message, starting with what happened before the page
+// connected. The stream ends with run_end or error.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+	job := s.current()
+	if job == nil {
+		http.NotFound(w, r)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	backlog, ch := job.subscribe()
+	for _, event := range backlog {
+		writeEvent(w, event)
+	}
+	flusher.Flush()
+	if ch == nil {
+		return
+	}
+	defer job.unsubscribe(ch)
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeEvent(w, event)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event model.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
+
+func redirectError(w http.ResponseWriter, r *http.Request, message string) {
+	http.Redirect(w, r, "/?error="+url.QueryEscape(message), http.StatusSeeOther)
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
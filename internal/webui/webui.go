@@ -0,0 +1,373 @@
+// Package webui serves a small local web app for remote-assist sessions:
+// start a run, follow its progress live and browse previous runs.
+package webui
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"conncheck/internal/config"
+	"conncheck/internal/engine"
+	"conncheck/internal/history"
+	"conncheck/internal/i18n"
+	"conncheck/internal/report"
+)
+
+// Server is the web app. Root is the outputs folder; Run executes one run.
+// At most one run is in progress at a time, since concurrent runs would
+// disturb each other's measurements.
+type Server struct {
+	Root string
+	Run  RunFunc
+	// AllowRemote accepts requests whose Host is not a loopback name, for
+	// servers started with -allow-remote.
+	AllowRemote bool
+
+	token string
+
+	mu  sync.Mutex
+	job *job
+}
+
+// New returns a server with a fresh CSRF token. It fails only if the
+// system random source does.
+func New(root string, run RunFunc) (*Server, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("csrf token: %w", err)
+	}
+	return &Server{Root: root, Run: run, token: hex.EncodeToString(buf)}, nil
+}
+
+// Handler routes the app:
+//
+//	GET  /           start form, current run and previous runs
+//	POST /run        start a run (form: mode, only, skip, csrf)
+//	POST /cancel     cancel the current run (form: csrf)
+//	GET  /live       progress page of the current or last run
+//	GET  /events     progress of that run as Server-Sent Events
+//	GET  /runs/...   files of the run folders, including report.html
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/runs/", http.StripPrefix("/runs/", http.FileServer(http.Dir(s.Root))))
+	mux.HandleFunc("/run", s.post(s.startRun))
+	mux.HandleFunc("/cancel", s.post(s.cancelRun))
+	mux.HandleFunc("/live", s.live)
+	mux.HandleFunc("/events", s.events)
+	mux.HandleFunc("/", s.index)
+	return s.guard(mux)
+}
+
+// IsLoopback reports whether a listen address only accepts local
+// connections. An empty host listens on every interface.
+func IsLoopback(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	return isLoopbackHost(host)
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// guard sets the security headers and rejects requests addressed to a
+// non-loopback Host, which is how a DNS rebinding page would reach the app.
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		if !s.AllowRemote {
+			host := r.Host
+			if h, _, err := net.SplitHostPort(r.Host); err == nil {
+				host = h
+			}
+			if !isLoopbackHost(host) {
+				http.Error(w, "forbidden host", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// post accepts only POST requests that carry the CSRF token and, when the
+// browser sends one, a same-origin Origin header.
+func (s *Server) post(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			parsed, err := url.Parse(origin)
+			if err != nil || parsed.Host != r.Host {
+				http.Error(w, "cross-origin request refused", http.StatusForbidden)
+				return
+			}
+		}
+		if subtle.ConstantTimeCompare([]byte(r.PostFormValue("csrf")), []byte(s.token)) != 1 {
+			http.Error(w, "invalid CSRF token", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	runs, err := history.List(s.Root)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	view := indexView{
+		Root:  s.Root,
+		Token: s.token,
+		Modes: config.Modes,
+		Tests: engine.TestNames(),
+		Error: r.URL.Query().Get("error"),
+	}
+	if job := s.current(); job != nil && !job.finished() {
+		view.Running = job.name()
+	}
+	for _, run := range runs {
+		view.Runs = append(view.Runs, runView{
+			Name:    run.Name,
+			Started: run.Result.StartedAt.Local().Format("2006-01-02 15:04:05"),
+			Status:  report.WorstStatus(run.Result),
+			Summary: report.FormatSummary(run.Result),
+		})
+	}
+	render(w, indexTemplate, view)
+}
+
+func (s *Server) live(w http.ResponseWriter, r *http.Request) {
+	job := s.current()
+	if job == nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	labels := map[string]string{}
+	for _, name := range engine.TestNames() {
+		labels[name] = i18n.Label("test", name)
+	}
+	render(w, liveTemplate, liveView{Run: job.name(), Token: s.token, Request: job.request, Labels: labels})
+}
+
+func render(w http.ResponseWriter, tpl *template.Template, view any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tpl.Execute(w, view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+type indexView struct {
+	Root    string
+	Token   string
+	Modes   []string
+	Tests   []string
+	Error   string
+	Running string
+	Runs    []runView
+}
+
+type runView struct {
+	Name    string
+	Started string
+	Status  string
+	Summary string
+}
+
+type liveView struct {
+	Run     string
+	Token   string
+	Request RunRequest
+	Labels  map[string]string
+}
+
+var funcs = template.FuncMap{
+	"t":     i18n.T,
+	"label": i18n.Label,
+	"lang":  i18n.Language,
+	"join":  strings.Join,
+	"isDefault": func(mode string) bool {
+		return mode == config.Default().Mode
+	},
+	"category": engine.Category,
+}
+
+const style = `<style>
+body { font-family: system-ui, sans-serif; margin: 24px; color: #111827; max-width: 1000px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 6px 10px; border-bottom: 1px solid #e5e7eb; }
+form.run { display: flex; gap: 12px; flex-wrap: wrap; align-items: end; padding: 12px; background: #f9fafb; border: 1px solid #e5e7eb; border-radius: 8px; }
+form.run label { display: flex; flex-direction: column; font-size: 13px; gap: 4px; }
+.error { color: #dc2626; }
+.bar { height: 16px; background: #e5e7eb; border-radius: 8px; overflow: hidden; }
+.bar div { height: 100%; width: 0; background: #2563eb; transition: width 0.3s; }
+.status-OK { color: #16a34a; } .status-WARN { color: #d97706; } .status-FAIL { color: #dc2626; } .status-SKIPPED { color: #6b7280; }
+</style>`
+
+var indexTemplate = template.Must(template.New("index").Funcs(funcs).Parse(`<!doctype html>
+<html lang="{{ lang }}">
+<head>
+<meta charset="utf-8">
+<title>{{ t "web.title" }}</title>
+` + style + `
+</head>
+<body>
+<h1>{{ t "web.title" }}</h1>
+{{ if .Error }}<p class="error">{{ .Error }}</p>{{ end }}
+{{ if .Running }}
+<p>{{ t "web.running" .Running }} <a href="/live">{{ t "web.follow" }}</a></p>
+{{ else }}
+<form class="run" method="post" action="/run">
+  <input type="hidden" name="csrf" value="{{ .Token }}">
+  <label>{{ t "web.mode" }}
+    <select name="mode">
+      {{ range .Modes }}<option value="{{ . }}"{{ if isDefault . }} selected{{ end }}>{{ . }}</option>{{ end }}
+    </select>
+  </label>
+  <label>{{ t "web.only" }} <input name="only" placeholder="dns_benchmark,path"></label>
+  <label>{{ t "web.skip" }} <input name="skip" placeholder="speed*"></label>
+  <button type="submit">{{ t "web.start" }}</button>
+</form>
+<p><small>{{ t "web.selection_help" }}{{ range $i, $name := .Tests }}{{ if $i }}, {{ end }}{{ $name }} ({{ category $name }}){{ end }}</small></p>
+{{ end }}
+<h2>{{ t "web.previous" }}</h2>
+<p><small>{{ t "web.root" .Root }}</small></p>
+{{ if .Runs }}
+<table>
+  <tr><th>{{ t "web.run" }}</th><th>{{ t "web.started" }}</th><th>{{ t "web.status" }}</th><th>{{ t "web.summary" }}</th><th></th></tr>
+  {{ range .Runs }}
+  <tr>
+    <td>{{ .Name }}</td>
+    <td>{{ .Started }}</td>
+    <td class="status-{{ .Status }}">{{ label "status" .Status }}</td>
+    <td>{{ .Summary }}</td>
+    <td><a href="/runs/{{ .Name }}/report.html">{{ t "web.report" }}</a> · <a href="/runs/{{ .Name }}/">{{ t "web.files" }}</a></td>
+  </tr>
+  {{ end }}
+</table>
+{{ else }}
+<p>{{ t "web.none" }}</p>
+{{ end }}
+</body>
+</html>
+`))
+
+var liveTemplate = template.Must(template.New("live").Funcs(funcs).Parse(`<!doctype html>
+<html lang="{{ lang }}">
+<head>
+<meta charset="utf-8">
+<title>{{ t "web.title" }} · {{ .Run }}</title>
+` + style + `
+</head>
+<body>
+<p><a href="/">{{ t "web.back" }}</a></p>
+<h1>{{ .Run }}</h1>
+<p><small>{{ t "web.mode" }}: {{ .Request.Mode }}{{ if .Request.Only }} · -only {{ join .Request.Only "," }}{{ end }}{{ if .Request.Skip }} · -skip {{ join .Request.Skip "," }}{{ end }}</small></p>
+<p id="step">{{ t "gui.starting" }}</p>
+<div class="bar"><div id="bar"></div></div>
+<form id="cancel" method="post" action="/cancel">
+  <input type="hidden" name="csrf" value="{{ .Token }}">
+  <button type="submit">{{ t "web.cancel" }}</button>
+</form>
+<table id="tests"><tr><th>{{ t "web.test" }}</th><th>{{ t "web.status" }}</th><th>{{ t "web.detail" }}</th></tr></table>
+<p id="outcome"></p>
+<script>
+(function () {
+  var stepText = {{ t "gui.step" }};
+  var labels = {{ .Labels }};
+  var rows = {};
+  function row(test) {
+    if (!rows[test]) {
+      var tr = document.getElementById("tests").insertRow(-1);
+      tr.insertCell(0).textContent = labels[test] || test;
+      tr.insertCell(1);
+      tr.insertCell(2);
+      rows[test] = tr;
+    }
+    return rows[test];
+  }
+  function progress(ev, fraction) {
+    if (ev.total) {
+      document.getElementById("bar").style.width = (((ev.index - 1) + fraction) / ev.total * 100) + "%";
+    }
+  }
+  var source = new EventSource("/events");
+  source.onmessage = function (msg) {
+    var ev = JSON.parse(msg.data);
+    switch (ev.type) {
+    case "test_start":
+      document.getElementById("step").textContent = stepText.replace("%d", ev.index).replace("%d", ev.total).replace("%s", labels[ev.test] || ev.test);
+      row(ev.test).cells[1].textContent = "…";
+      progress(ev, 0);
+      break;
+    case "test_progress":
+      var p = ev.progress || {};
+      var detail = Math.round((p.fraction || 0) * 100) + "%";
+      if (p.down_bps || p.up_bps) {
+        detail += " · " + p.target + " ↓ " + (p.down_bps / 1e6).toFixed(1) + " ↑ " + (p.up_bps / 1e6).toFixed(1) + " Mbps";
+      } else if (p.target) {
+        detail += " · " + p.target + " " + (p.lost ? "×" : Math.round(p.rtt_ms) + " ms");
+      }
+      row(ev.test).cells[2].textContent = detail;
+      progress(ev, p.fraction || 0);
+      break;
+    case "test_end":
+      var tr = row(ev.test);
+      tr.cells[1].textContent = ev.status;
+      tr.cells[1].className = "status-" + ev.status;
+      tr.cells[2].textContent = "";
+      progress(ev, 1);
+      break;
+    case "test_excluded":
+      row(ev.test).cells[1].textContent = {{ t "web.excluded" }};
+      break;
+    case "run_end":
+    case "error":
+      source.close();
+      document.getElementById("cancel").style.display = "none";
+      var outcome = document.getElementById("outcome");
+      if (ev.type === "error") {
+        outcome.className = "error";
+        outcome.textContent = ev.message;
+      } else {
+        document.getElementById("bar").style.width = "100%";
+        document.getElementById("step").textContent = ev.message;
+        var link = document.createElement("a");
+        link.href = "/runs/{{ .Run }}/report.html";
+        link.textContent = {{ t "web.report" }};
+        outcome.appendChild(link);
+      }
+      break;
+    }
+  };
+})();
+</script>
+</body>
+</html>
+`))